@@ -0,0 +1,111 @@
+/*
+Package apperr provides a standard application error carrying a machine
+readable code, a client safe message, the HTTP status it maps to and the
+underlying cause.
+*/
+package apperr
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Error codes used by the predefined constructors.
+const (
+	CodeBadRequest   = "bad_request"
+	CodeUnauthorized = "unauthorized"
+	CodeForbidden    = "forbidden"
+	CodeNotFound     = "not_found"
+	CodeConflict     = "conflict"
+	CodeInternal     = "internal"
+)
+
+// Error is an application error mapped to an HTTP status.
+type Error struct {
+	Code    string
+	Message string
+	Status  int
+	Cause   error
+}
+
+// New creates a new Error with the given status, code and message.
+func New(status int, code, message string) *Error {
+	return &Error{
+		Code:    code,
+		Message: message,
+		Status:  status,
+	}
+}
+
+// BadRequest creates a new 400 Bad Request Error.
+func BadRequest(message string) *Error {
+	return New(http.StatusBadRequest, CodeBadRequest, message)
+}
+
+// Unauthorized creates a new 401 Unauthorized Error.
+func Unauthorized(message string) *Error {
+	return New(http.StatusUnauthorized, CodeUnauthorized, message)
+}
+
+// Forbidden creates a new 403 Forbidden Error.
+func Forbidden(message string) *Error {
+	return New(http.StatusForbidden, CodeForbidden, message)
+}
+
+// NotFound creates a new 404 Not Found Error.
+func NotFound(message string) *Error {
+	return New(http.StatusNotFound, CodeNotFound, message)
+}
+
+// Conflict creates a new 409 Conflict Error.
+func Conflict(message string) *Error {
+	return New(http.StatusConflict, CodeConflict, message)
+}
+
+// Internal creates a new 500 Internal Server Error.
+func Internal(message string) *Error {
+	return New(http.StatusInternalServerError, CodeInternal, message)
+}
+
+// Error returns the message followed by the cause, if any.
+func (e *Error) Error() string {
+	if e.Cause == nil {
+		return e.Message
+	}
+	return e.Message + ": " + e.Cause.Error()
+}
+
+// Unwrap returns the underlying cause.
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
+
+// Fields returns the code and status of the error as structured log fields.
+func (e *Error) Fields() map[string]interface{} {
+	return map[string]interface{}{
+		"error_code": e.Code,
+		"status":     e.Status,
+	}
+}
+
+// WithCause returns a copy of the error wrapping the given cause.
+func (e *Error) WithCause(cause error) *Error {
+	wrapped := *e
+	wrapped.Cause = cause
+	return &wrapped
+}
+
+// StatusCode returns the HTTP status of the first Error found in err's chain.
+// It returns 200 OK for a nil error and 500 Internal Server Error when the
+// chain holds no Error.
+func StatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	var appErr *Error
+	if errors.As(err, &appErr) && appErr.Status != 0 {
+		return appErr.Status
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,85 @@
+package apperr_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/junkd0g/go-microservice-commons/apperr"
+)
+
+func Test_Constructors(t *testing.T) {
+	type testCase struct {
+		name           string
+		err            *apperr.Error
+		expectedStatus int
+		expectedCode   string
+	}
+
+	testCases := []testCase{
+		{name: "bad request", err: apperr.BadRequest("msg"), expectedStatus: http.StatusBadRequest, expectedCode: apperr.CodeBadRequest},
+		{name: "unauthorized", err: apperr.Unauthorized("msg"), expectedStatus: http.StatusUnauthorized, expectedCode: apperr.CodeUnauthorized},
+		{name: "forbidden", err: apperr.Forbidden("msg"), expectedStatus: http.StatusForbidden, expectedCode: apperr.CodeForbidden},
+		{name: "not found", err: apperr.NotFound("msg"), expectedStatus: http.StatusNotFound, expectedCode: apperr.CodeNotFound},
+		{name: "conflict", err: apperr.Conflict("msg"), expectedStatus: http.StatusConflict, expectedCode: apperr.CodeConflict},
+		{name: "internal", err: apperr.Internal("msg"), expectedStatus: http.StatusInternalServerError, expectedCode: apperr.CodeInternal},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedStatus, tc.err.Status)
+			assert.Equal(t, tc.expectedCode, tc.err.Code)
+			assert.Equal(t, "msg", tc.err.Error())
+		})
+	}
+}
+
+func Test_WithCause(t *testing.T) {
+	cause := errors.New("sql: no rows")
+	notFound := apperr.NotFound("user not found")
+
+	err := notFound.WithCause(cause)
+
+	assert.ErrorIs(t, err, cause)
+	assert.Equal(t, "user not found: sql: no rows", err.Error())
+	assert.Nil(t, notFound.Cause)
+}
+
+func Test_Fields(t *testing.T) {
+	fields := apperr.NotFound("user not found").Fields()
+
+	assert.Equal(t, map[string]interface{}{"error_code": apperr.CodeNotFound, "status": http.StatusNotFound}, fields)
+}
+
+func Test_StatusCode(t *testing.T) {
+	type testCase struct {
+		name           string
+		err            error
+		expectedStatus int
+	}
+
+	testCases := []testCase{
+		{name: "nil error", err: nil, expectedStatus: http.StatusOK},
+		{name: "plain error", err: errors.New("boom"), expectedStatus: http.StatusInternalServerError},
+		{name: "app error", err: apperr.NotFound("user not found"), expectedStatus: http.StatusNotFound},
+		{
+			name:           "wrapped app error",
+			err:            fmt.Errorf("loading profile: %w", apperr.Unauthorized("token expired")),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "app error without status",
+			err:            &apperr.Error{Code: "custom"},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedStatus, apperr.StatusCode(tc.err))
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"time"
+
+	"github.com/junkd0g/go-microservice-commons/cache"
+)
+
+// cacheExpirySkew is how long before the token expiry a cached entry stops being served.
+const cacheExpirySkew = time.Second
+
+// TokenCache is a size-bounded LRU cache of verified token claims, keyed by
+// the SHA-256 hash of the token so raw tokens are never kept in memory.
+// Entries are served until the earlier of the cache TTL and just before
+// the token expiry, the latter checked against the clock of the validator.
+// It is safe for concurrent use.
+type TokenCache struct {
+	ttl     time.Duration
+	entries *cache.LRU[[sha256.Size]byte, JwtClaim]
+}
+
+// NewTokenCache creates a TokenCache holding at most size tokens, each for at most ttl.
+func NewTokenCache(size int, ttl time.Duration) *TokenCache {
+	return &TokenCache{
+		ttl:     ttl,
+		entries: cache.NewLRU[[sha256.Size]byte, JwtClaim](size),
+	}
+}
+
+// Len returns the number of cached tokens, including expired ones not yet evicted.
+func (c *TokenCache) Len() int {
+	return c.entries.Len()
+}
+
+// get returns a copy of the cached claims for the token, if present and not
+// expired at now.
+func (c *TokenCache) get(token string, now time.Time) (*JwtClaim, bool) {
+	key := sha256.Sum256([]byte(token))
+
+	claims, ok := c.entries.Get(key)
+	if !ok {
+		return nil, false
+	}
+	if !now.Before(cacheExpiry(&claims, now.Add(c.ttl))) {
+		c.entries.Delete(key)
+		return nil, false
+	}
+
+	return claims.clone(), true
+}
+
+// add caches a copy of the claims for the token, verified at now, evicting the
+// least recently used entry when full.
+func (c *TokenCache) add(token string, claims *JwtClaim, now time.Time) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	ttl := cacheExpiry(claims, now.Add(c.ttl)).Sub(now)
+	if ttl <= 0 {
+		return
+	}
+
+	c.entries.SetWithTTL(sha256.Sum256([]byte(token)), *claims.clone(), ttl)
+}
+
+// cacheExpiry returns the earlier of limit and just before the token expiry.
+func cacheExpiry(claims *JwtClaim, limit time.Time) time.Time {
+	if tokenExpiresAt, ok := claims.ExpiresAtTime(); ok {
+		if tokenExpiry := tokenExpiresAt.Add(-cacheExpirySkew); tokenExpiry.Before(limit) {
+			return tokenExpiry
+		}
+	}
+	return limit
+}
@@ -0,0 +1,202 @@
+package auth_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/junkd0g/go-microservice-commons/auth"
+)
+
+func Test_ValidateTokenWithCache(t *testing.T) {
+	t.Run("should serve repeated tokens from the cache", func(t *testing.T) {
+		ctx := context.Background()
+
+		jwtWrapper, err := auth.NewJwtWrapper("some-secret-key", "some-issuer", 1)
+		assert.NoError(t, err)
+		jwtWrapper.Cache = auth.NewTokenCache(10, time.Minute)
+
+		token, err := jwtWrapper.GenerateToken(ctx, "some-uuid", "some-email")
+		assert.NoError(t, err)
+
+		first, err := jwtWrapper.ValidateToken(ctx, token)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, jwtWrapper.Cache.Len())
+
+		second, err := jwtWrapper.ValidateToken(ctx, token)
+		assert.NoError(t, err)
+		assert.Equal(t, first, second)
+
+		// Callers get their own copy of the cached claims.
+		second.Email = "changed"
+		third, err := jwtWrapper.ValidateToken(ctx, token)
+		assert.NoError(t, err)
+		assert.Equal(t, "some-email", third.Email)
+	})
+
+	t.Run("should not share the cached claims between callers", func(t *testing.T) {
+		ctx := context.Background()
+
+		jwtWrapper, err := auth.NewJwtWrapper("some-secret-key", "some-issuer", 1)
+		assert.NoError(t, err)
+		jwtWrapper.Cache = auth.NewTokenCache(10, time.Minute)
+
+		expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+		token := signToken(t, "some-secret-key", &auth.JwtClaim{
+			ID:     "some-uuid",
+			Custom: map[string]interface{}{"plan": "pro", "limits": map[string]interface{}{"seats": "5"}},
+			RegisteredClaims: jwt.RegisteredClaims{
+				Audience:  jwt.ClaimStrings{"users-api"},
+				ExpiresAt: jwt.NewNumericDate(expiresAt),
+			},
+		})
+
+		first, err := jwtWrapper.ValidateToken(ctx, token)
+		assert.NoError(t, err)
+
+		// Mutate everything reachable from the first caller's claims
+		second, err := jwtWrapper.ValidateToken(ctx, token)
+		assert.NoError(t, err)
+		second.Audience[0] = "admin-api"
+		second.ExpiresAt.Time = expiresAt.Add(time.Hour)
+		second.Custom["plan"] = "enterprise"
+		second.Custom["limits"].(map[string]interface{})["seats"] = "500"
+
+		third, err := jwtWrapper.ValidateToken(ctx, token)
+		assert.NoError(t, err)
+		assert.Equal(t, first, third)
+		assert.Equal(t, jwt.ClaimStrings{"users-api"}, third.Audience)
+		assert.True(t, expiresAt.Equal(third.ExpiresAt.Time))
+		assert.Equal(t, "pro", third.Custom["plan"])
+		assert.Equal(t, "5", third.Custom["limits"].(map[string]interface{})["seats"])
+	})
+
+	t.Run("should expire entries with the wrapper clock", func(t *testing.T) {
+		ctx := context.Background()
+
+		now := time.Now()
+		jwtWrapper, err := auth.New(
+			auth.WithSecret("some-secret-key"),
+			auth.WithIssuer("some-issuer"),
+			auth.WithExpiration(10*time.Minute),
+			auth.WithClock(func() time.Time { return now }),
+			auth.WithCache(auth.NewTokenCache(10, time.Hour)),
+		)
+		assert.NoError(t, err)
+
+		token, err := jwtWrapper.GenerateToken(ctx, "some-uuid", "some-email")
+		assert.NoError(t, err)
+
+		_, err = jwtWrapper.ValidateToken(ctx, token)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, jwtWrapper.Cache.Len())
+
+		// The entry expires with the token on the wrapper clock, not the wall clock
+		now = now.Add(20 * time.Minute)
+
+		_, err = jwtWrapper.ValidateToken(ctx, token)
+		assert.Error(t, err)
+		assert.Equal(t, 0, jwtWrapper.Cache.Len())
+	})
+
+	t.Run("should evict the least recently used token", func(t *testing.T) {
+		ctx := context.Background()
+
+		jwtWrapper, err := auth.NewJwtWrapper("some-secret-key", "some-issuer", 1)
+		assert.NoError(t, err)
+		jwtWrapper.Cache = auth.NewTokenCache(2, time.Minute)
+
+		for i := 0; i < 3; i++ {
+			token, err := jwtWrapper.GenerateToken(ctx, fmt.Sprintf("uuid-%d", i), "some-email")
+			assert.NoError(t, err)
+
+			_, err = jwtWrapper.ValidateToken(ctx, token)
+			assert.NoError(t, err)
+		}
+
+		assert.Equal(t, 2, jwtWrapper.Cache.Len())
+	})
+
+	t.Run("should not serve expired tokens", func(t *testing.T) {
+		ctx := context.Background()
+
+		jwtWrapper, err := auth.NewJwtWrapper("some-secret-key", "some-issuer", 1)
+		assert.NoError(t, err)
+		jwtWrapper.Cache = auth.NewTokenCache(10, time.Minute)
+
+		token := signToken(t, "some-secret-key", &auth.JwtClaim{
+			ID: "some-uuid",
+			RegisteredClaims: jwt.RegisteredClaims{
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(2 * time.Second)),
+			},
+		})
+
+		_, err = jwtWrapper.ValidateToken(ctx, token)
+		assert.NoError(t, err)
+
+		time.Sleep(2100 * time.Millisecond)
+
+		claims, err := jwtWrapper.ValidateToken(ctx, token)
+		assert.Error(t, err)
+		assert.Nil(t, claims)
+	})
+
+	t.Run("should respect revocation for cached tokens", func(t *testing.T) {
+		ctx := context.Background()
+
+		jwtWrapper, err := auth.NewJwtWrapper("some-secret-key", "some-issuer", 1)
+		assert.NoError(t, err)
+		jwtWrapper.Cache = auth.NewTokenCache(10, time.Minute)
+		revoker := auth.NewMemoryRevoker()
+		jwtWrapper.Revoker = revoker
+
+		token, err := jwtWrapper.GenerateToken(ctx, "some-uuid", "some-email")
+		assert.NoError(t, err)
+
+		_, err = jwtWrapper.ValidateToken(ctx, token)
+		assert.NoError(t, err)
+
+		revoker.Revoke("some-uuid")
+
+		claims, err := jwtWrapper.ValidateToken(ctx, token)
+		assert.ErrorIs(t, err, auth.ErrTokenRevoked)
+		assert.Nil(t, claims)
+	})
+}
+
+func BenchmarkValidateToken(b *testing.B) {
+	ctx := context.Background()
+
+	run := func(b *testing.B, cache *auth.TokenCache) {
+		jwtWrapper, err := auth.NewJwtWrapper("some-secret-key", "some-issuer", 1)
+		if err != nil {
+			b.Fatal(err)
+		}
+		jwtWrapper.Cache = cache
+
+		token, err := jwtWrapper.GenerateToken(ctx, "some-uuid", "some-email")
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := jwtWrapper.ValidateToken(ctx, token); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+
+	b.Run("uncached", func(b *testing.B) {
+		run(b, nil)
+	})
+
+	b.Run("cached", func(b *testing.B) {
+		run(b, auth.NewTokenCache(100, time.Minute))
+	})
+}
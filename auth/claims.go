@@ -0,0 +1,360 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Claim keys used by SafeMap.
+const (
+	ClaimKeyID        = "id"
+	ClaimKeyEmail     = "email"
+	ClaimKeyIssuer    = "issuer"
+	ClaimKeySubject   = "subject"
+	ClaimKeyAudience  = "audience"
+	ClaimKeyExpiresAt = "expires_at"
+	ClaimKeyIssuedAt  = "issued_at"
+	ClaimKeyTokenID   = "token_id"
+	ClaimKeyScopes    = "scopes"
+)
+
+// defaultSafeClaimKeys is the conservative set of keys SafeMap returns when none are given.
+var defaultSafeClaimKeys = []string{ClaimKeyID, ClaimKeyIssuer, ClaimKeyExpiresAt}
+
+// SafeMap returns the claims restricted to the allowed keys (see the ClaimKey
+// constants) as a map suitable for the logger's field API. Unknown or absent
+// claims are skipped. When no keys are given, only the ID, issuer and expiry
+// are returned so PII such as the email is never logged by accident.
+// Times are formatted as RFC 3339 strings.
+func (c *JwtClaim) SafeMap(allow ...string) map[string]interface{} {
+	if len(allow) == 0 {
+		allow = defaultSafeClaimKeys
+	}
+
+	safe := make(map[string]interface{}, len(allow))
+	for _, key := range allow {
+		if value, ok := c.claimValue(key); ok {
+			safe[key] = value
+		}
+	}
+	return safe
+}
+
+// MapOption configures the map returned by ToMap.
+type MapOption func(*mapConfig)
+
+// mapConfig holds the settings collected from MapOptions.
+type mapConfig struct {
+	withoutEmail bool
+}
+
+// WithoutEmail leaves the email out of the map returned by ToMap for privacy.
+func WithoutEmail() MapOption {
+	return func(c *mapConfig) {
+		c.withoutEmail = true
+	}
+}
+
+// ToMap returns the ID, email, issuer and expiry of the claims as a map
+// suitable for the logger's field API or serialization, keyed by the ClaimKey
+// constants. It never includes the token or the signing secret. Use SafeMap to
+// choose the keys.
+func (c *JwtClaim) ToMap(opts ...MapOption) map[string]interface{} {
+	config := &mapConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	if config.withoutEmail {
+		return c.SafeMap(ClaimKeyID, ClaimKeyIssuer, ClaimKeyExpiresAt)
+	}
+	return c.SafeMap(ClaimKeyID, ClaimKeyEmail, ClaimKeyIssuer, ClaimKeyExpiresAt)
+}
+
+// claimValue returns the loggable value of the claim with the given key, if present.
+func (c *JwtClaim) claimValue(key string) (interface{}, bool) {
+	switch key {
+	case ClaimKeyID:
+		return c.ID, c.ID != ""
+	case ClaimKeyEmail:
+		return c.Email, c.Email != ""
+	case ClaimKeyIssuer:
+		return c.Issuer, c.Issuer != ""
+	case ClaimKeySubject:
+		return c.Subject, c.Subject != ""
+	case ClaimKeyAudience:
+		return []string(c.Audience), len(c.Audience) > 0
+	case ClaimKeyExpiresAt:
+		expiresAt, ok := c.ExpiresAtTime()
+		if !ok {
+			return nil, false
+		}
+		return expiresAt.UTC().Format(time.RFC3339), true
+	case ClaimKeyIssuedAt:
+		issuedAt, ok := c.IssuedAtTime()
+		if !ok {
+			return nil, false
+		}
+		return issuedAt.UTC().Format(time.RFC3339), true
+	case ClaimKeyTokenID:
+		return c.RegisteredClaims.ID, c.RegisteredClaims.ID != ""
+	case ClaimKeyScopes:
+		scopes := c.Scopes()
+		return scopes, len(scopes) > 0
+	}
+	return nil, false
+}
+
+// Scopes returns the scopes of the scope claim. Both the OAuth standard space
+// delimiter and commas are accepted, and empty entries are skipped.
+func (c *JwtClaim) Scopes() []string {
+	return strings.FieldsFunc(c.Scope, func(r rune) bool {
+		return r == ' ' || r == ','
+	})
+}
+
+// knownClaimKeys are the JSON keys decoded into the fields of JwtClaim.
+var knownClaimKeys = []string{"ID", "Email", "fpt", "scope", "iss", "sub", "aud", "exp", "nbf", "iat", "jti"}
+
+// jwtClaimFields has the fields of JwtClaim without its JSON methods.
+type jwtClaimFields JwtClaim
+
+// UnmarshalJSON decodes the claims, keeping the claims not mapped to a field in
+// Custom with numbers decoded as json.Number instead of float64.
+func (c *JwtClaim) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, (*jwtClaimFields)(c)); err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var all map[string]interface{}
+	if err := decoder.Decode(&all); err != nil {
+		return err
+	}
+
+	c.Custom = nil
+	for key, value := range all {
+		if isKnownClaimKey(key) {
+			continue
+		}
+		if c.Custom == nil {
+			c.Custom = make(map[string]interface{})
+		}
+		c.Custom[key] = value
+	}
+	return nil
+}
+
+// MarshalJSON encodes the claims along with the Custom claims. Custom claims
+// whose key is decoded into a field of JwtClaim, such as "scope" or "aud", are
+// dropped even when the field is empty, so they cannot be injected as real
+// claims.
+func (c JwtClaim) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(jwtClaimFields(c))
+	if err != nil || len(c.Custom) == 0 {
+		return data, err
+	}
+
+	all := make(map[string]interface{}, len(c.Custom))
+	for key, value := range c.Custom {
+		if isKnownClaimKey(key) {
+			continue
+		}
+		all[key] = value
+	}
+
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for key, value := range fields {
+		all[key] = value
+	}
+
+	return json.Marshal(all)
+}
+
+// isKnownClaimKey reports whether the key is decoded into a field of JwtClaim,
+// matching case-insensitively like encoding/json.
+func isKnownClaimKey(key string) bool {
+	for _, known := range knownClaimKeys {
+		if strings.EqualFold(key, known) {
+			return true
+		}
+	}
+	return false
+}
+
+// Int64Claim returns the custom claim with the given key as an int64. The
+// boolean is false when the claim is absent or not an integer.
+func (c *JwtClaim) Int64Claim(key string) (int64, bool) {
+	number, ok := c.Custom[key].(json.Number)
+	if !ok {
+		return 0, false
+	}
+	value, err := number.Int64()
+	return value, err == nil
+}
+
+// Float64Claim returns the custom claim with the given key as a float64. The
+// boolean is false when the claim is absent or not a number.
+func (c *JwtClaim) Float64Claim(key string) (float64, bool) {
+	number, ok := c.Custom[key].(json.Number)
+	if !ok {
+		return 0, false
+	}
+	value, err := number.Float64()
+	return value, err == nil
+}
+
+// clone returns a deep copy of the claims, so the copy shares no pointer,
+// slice or map with c.
+func (c *JwtClaim) clone() *JwtClaim {
+	claims := *c
+	claims.ExpiresAt = cloneNumericDate(c.ExpiresAt)
+	claims.IssuedAt = cloneNumericDate(c.IssuedAt)
+	claims.NotBefore = cloneNumericDate(c.NotBefore)
+	if c.Audience != nil {
+		claims.Audience = append(jwt.ClaimStrings(nil), c.Audience...)
+	}
+	if c.Custom != nil {
+		claims.Custom = cloneClaimValue(c.Custom).(map[string]interface{})
+	}
+	return &claims
+}
+
+// cloneNumericDate returns a copy of the date, or nil.
+func cloneNumericDate(date *jwt.NumericDate) *jwt.NumericDate {
+	if date == nil {
+		return nil
+	}
+	clone := *date
+	return &clone
+}
+
+// cloneClaimValue returns a deep copy of a decoded JSON value.
+func cloneClaimValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		clone := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			clone[key] = cloneClaimValue(item)
+		}
+		return clone
+	case []interface{}:
+		clone := make([]interface{}, len(v))
+		for i, item := range v {
+			clone[i] = cloneClaimValue(item)
+		}
+		return clone
+	}
+	return value
+}
+
+// ExpiresAtTime returns the time of the exp claim. The boolean is false when
+// the claim is absent.
+func (c *JwtClaim) ExpiresAtTime() (time.Time, bool) {
+	if c.ExpiresAt == nil {
+		return time.Time{}, false
+	}
+	return c.ExpiresAt.Time, true
+}
+
+// IssuedAtTime returns the time of the iat claim. The boolean is false when
+// the claim is absent.
+func (c *JwtClaim) IssuedAtTime() (time.Time, bool) {
+	if c.IssuedAt == nil {
+		return time.Time{}, false
+	}
+	return c.IssuedAt.Time, true
+}
+
+// EqualOption configures the comparison made by Equal.
+type EqualOption func(*equalConfig)
+
+// equalConfig holds the settings collected from EqualOptions.
+type equalConfig struct {
+	timeClaims bool
+	tokenID    bool
+}
+
+// CompareTimeClaims makes Equal also compare the exp, iat and nbf claims.
+func CompareTimeClaims() EqualOption {
+	return func(c *equalConfig) {
+		c.timeClaims = true
+	}
+}
+
+// CompareTokenID makes Equal also compare the jti claim.
+func CompareTokenID() EqualOption {
+	return func(c *equalConfig) {
+		c.tokenID = true
+	}
+}
+
+// Equal reports whether the claims match other, typically in tests asserting
+// round-tripped claims. By default it compares the ID, email, fingerprint,
+// scope, issuer, subject, audience and custom claims, the latter by their JSON
+// encoding so 1 and json.Number("1") are equal. The claims that change with
+// every token (exp, iat, nbf and jti) are ignored unless requested with
+// CompareTimeClaims and CompareTokenID.
+func (c *JwtClaim) Equal(other *JwtClaim, opts ...EqualOption) bool {
+	if c == nil || other == nil {
+		return c == other
+	}
+
+	config := &equalConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	if c.ID != other.ID ||
+		c.Email != other.Email ||
+		c.Fingerprint != other.Fingerprint ||
+		c.Scope != other.Scope ||
+		c.Issuer != other.Issuer ||
+		c.Subject != other.Subject ||
+		!slices.Equal(c.Audience, other.Audience) {
+		return false
+	}
+
+	if config.timeClaims &&
+		(!equalNumericDate(c.ExpiresAt, other.ExpiresAt) ||
+			!equalNumericDate(c.IssuedAt, other.IssuedAt) ||
+			!equalNumericDate(c.NotBefore, other.NotBefore)) {
+		return false
+	}
+
+	if config.tokenID && c.RegisteredClaims.ID != other.RegisteredClaims.ID {
+		return false
+	}
+
+	return equalCustomClaims(c.Custom, other.Custom)
+}
+
+// equalNumericDate reports whether both dates are absent or at the same time.
+func equalNumericDate(a, b *jwt.NumericDate) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(b.Time)
+}
+
+// equalCustomClaims compares custom claims by their JSON encoding, which sorts
+// the keys and renders equal numbers identically whatever their Go type.
+func equalCustomClaims(a, b map[string]interface{}) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return len(a) == len(b)
+	}
+
+	encodedA, errA := json.Marshal(a)
+	encodedB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(encodedA, encodedB)
+}
@@ -0,0 +1,282 @@
+package auth_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/junkd0g/go-microservice-commons/auth"
+)
+
+func Test_SafeMap(t *testing.T) {
+	expiresAt := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	claims := &auth.JwtClaim{
+		ID:    "some-uuid",
+		Email: "user@example.com",
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    "some-issuer",
+			Subject:   "some-subject",
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	t.Run("should return the conservative defaults without keys", func(t *testing.T) {
+		assert.Equal(t, map[string]interface{}{
+			"id":         "some-uuid",
+			"issuer":     "some-issuer",
+			"expires_at": "2030-01-02T03:04:05Z",
+		}, claims.SafeMap())
+	})
+
+	t.Run("should return only the allowed keys", func(t *testing.T) {
+		assert.Equal(t, map[string]interface{}{
+			"subject": "some-subject",
+			"email":   "user@example.com",
+		}, claims.SafeMap(auth.ClaimKeySubject, auth.ClaimKeyEmail))
+	})
+
+	t.Run("should skip unknown and absent claims", func(t *testing.T) {
+		assert.Empty(t, claims.SafeMap("password", auth.ClaimKeyIssuedAt, auth.ClaimKeyAudience))
+	})
+}
+
+func Test_ToMap(t *testing.T) {
+	expiresAt := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	claims := &auth.JwtClaim{
+		ID:          "some-uuid",
+		Email:       "user@example.com",
+		Fingerprint: "some-fingerprint",
+		Custom:      map[string]interface{}{"password": "secret"},
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    "some-issuer",
+			Subject:   "some-subject",
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			ID:        "some-token-id",
+		},
+	}
+
+	t.Run("should return the identifying claims", func(t *testing.T) {
+		assert.Equal(t, map[string]interface{}{
+			"id":         "some-uuid",
+			"email":      "user@example.com",
+			"issuer":     "some-issuer",
+			"expires_at": "2030-01-02T03:04:05Z",
+		}, claims.ToMap())
+	})
+
+	t.Run("should exclude the email on request", func(t *testing.T) {
+		assert.Equal(t, map[string]interface{}{
+			"id":         "some-uuid",
+			"issuer":     "some-issuer",
+			"expires_at": "2030-01-02T03:04:05Z",
+		}, claims.ToMap(auth.WithoutEmail()))
+	})
+}
+
+func Test_Scopes(t *testing.T) {
+	testCases := []struct {
+		name     string
+		scope    string
+		expected []string
+	}{
+		{name: "empty", scope: "", expected: []string{}},
+		{name: "single", scope: "read", expected: []string{"read"}},
+		{name: "space delimited", scope: "read write admin", expected: []string{"read", "write", "admin"}},
+		{name: "comma delimited", scope: "read,write, admin", expected: []string{"read", "write", "admin"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			claims := &auth.JwtClaim{Scope: tc.scope}
+			assert.Equal(t, tc.expected, claims.Scopes())
+		})
+	}
+
+	t.Run("should round trip scopes through a generated token", func(t *testing.T) {
+		ctx := context.Background()
+		jwtWrapper, err := auth.NewJwtWrapper("some-secret-key", "some-issuer", 1)
+		assert.NoError(t, err)
+
+		token, err := jwtWrapper.GenerateTokenWithScopes(ctx, "some-uuid", "some-email", []string{"read", "write"})
+		assert.NoError(t, err)
+
+		claims, err := jwtWrapper.ValidateToken(ctx, token)
+		assert.NoError(t, err)
+		assert.Equal(t, "read write", claims.Scope)
+		assert.Equal(t, []string{"read", "write"}, claims.Scopes())
+		assert.Equal(t, []string{"read", "write"}, claims.SafeMap(auth.ClaimKeyScopes)[auth.ClaimKeyScopes])
+	})
+}
+
+func Test_CustomClaims(t *testing.T) {
+	ctx := context.Background()
+	jwtWrapper, err := auth.NewJwtWrapper("some-secret-key", "some-issuer", 1)
+	assert.NoError(t, err)
+
+	// 2^53 + 1 cannot be represented exactly as a float64
+	token := signToken(t, "some-secret-key", &auth.JwtClaim{
+		ID: "some-uuid",
+		Custom: map[string]interface{}{
+			"account_id": json.Number("9007199254740993"),
+			"ratio":      0.25,
+			"plan":       "pro",
+			// Custom claims never override the fields
+			"ID": "other-uuid",
+		},
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))},
+	})
+
+	claims, err := jwtWrapper.ValidateToken(ctx, token)
+	assert.NoError(t, err)
+	assert.Equal(t, "some-uuid", claims.ID)
+
+	accountID, ok := claims.Int64Claim("account_id")
+	assert.True(t, ok)
+	assert.Equal(t, int64(9007199254740993), accountID)
+	assert.Equal(t, json.Number("9007199254740993"), claims.Custom["account_id"])
+
+	ratio, ok := claims.Float64Claim("ratio")
+	assert.True(t, ok)
+	assert.Equal(t, 0.25, ratio)
+
+	_, ok = claims.Int64Claim("ratio")
+	assert.False(t, ok)
+	_, ok = claims.Int64Claim("plan")
+	assert.False(t, ok)
+	_, ok = claims.Float64Claim("missing")
+	assert.False(t, ok)
+
+	assert.Equal(t, "pro", claims.Custom["plan"])
+	assert.NotContains(t, claims.Custom, "exp")
+	assert.NotContains(t, claims.Custom, "ID")
+
+	t.Run("should keep numbers when re-marshaled", func(t *testing.T) {
+		data, err := json.Marshal(claims)
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), `"account_id":9007199254740993`)
+	})
+}
+
+func Test_CustomClaimsCannotInjectKnownClaims(t *testing.T) {
+	ctx := context.Background()
+	jwtWrapper, err := auth.NewJwtWrapper("some-secret-key", "some-issuer", 1)
+	assert.NoError(t, err)
+
+	// The typed fields are empty, so omitempty leaves their keys free
+	token := signToken(t, "some-secret-key", &auth.JwtClaim{
+		ID: "some-uuid",
+		Custom: map[string]interface{}{
+			"scope": "admin",
+			"Scope": "admin",
+			"fpt":   "forged",
+			"aud":   "other-service",
+			"plan":  "pro",
+		},
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))},
+	})
+
+	claims, err := jwtWrapper.ValidateToken(ctx, token)
+	assert.NoError(t, err)
+	assert.Empty(t, claims.Scope)
+	assert.Empty(t, claims.Scopes())
+	assert.Empty(t, claims.Fingerprint)
+	assert.Empty(t, claims.Audience)
+	assert.Equal(t, map[string]interface{}{"plan": "pro"}, claims.Custom)
+}
+
+func Test_TimeClaims(t *testing.T) {
+	t.Run("should return the exp and iat claims", func(t *testing.T) {
+		issuedAt := time.Unix(1700000000, 0)
+		claims := &auth.JwtClaim{
+			RegisteredClaims: jwt.RegisteredClaims{
+				IssuedAt:  jwt.NewNumericDate(issuedAt),
+				ExpiresAt: jwt.NewNumericDate(issuedAt.Add(time.Hour)),
+			},
+		}
+
+		expiresAt, ok := claims.ExpiresAtTime()
+		assert.True(t, ok)
+		assert.True(t, issuedAt.Add(time.Hour).Equal(expiresAt))
+
+		got, ok := claims.IssuedAtTime()
+		assert.True(t, ok)
+		assert.True(t, issuedAt.Equal(got))
+	})
+
+	t.Run("should report absent claims", func(t *testing.T) {
+		claims := &auth.JwtClaim{}
+
+		expiresAt, ok := claims.ExpiresAtTime()
+		assert.False(t, ok)
+		assert.True(t, expiresAt.IsZero())
+
+		issuedAt, ok := claims.IssuedAtTime()
+		assert.False(t, ok)
+		assert.True(t, issuedAt.IsZero())
+	})
+}
+
+func Test_Equal(t *testing.T) {
+	ctx := context.Background()
+	jwtWrapper, err := auth.NewJwtWrapper("some-secret-key", "some-issuer", 1)
+	assert.NoError(t, err)
+
+	expected := &auth.JwtClaim{
+		ID:     "some-uuid",
+		Email:  "some-email",
+		Scope:  "read write",
+		Custom: map[string]interface{}{"account_id": 42, "plan": "pro"},
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    "some-issuer",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+
+	token := signToken(t, "some-secret-key", expected)
+	claims, err := jwtWrapper.ValidateToken(ctx, token)
+	assert.NoError(t, err)
+
+	t.Run("should match round-tripped claims", func(t *testing.T) {
+		assert.True(t, claims.Equal(expected))
+		assert.True(t, claims.Equal(expected, auth.CompareTimeClaims()))
+	})
+
+	t.Run("should ignore volatile claims by default", func(t *testing.T) {
+		other := *expected
+		other.ExpiresAt = jwt.NewNumericDate(time.Now().Add(2 * time.Hour))
+		other.RegisteredClaims.ID = "other-token-id"
+
+		assert.True(t, claims.Equal(&other))
+		assert.False(t, claims.Equal(&other, auth.CompareTimeClaims()))
+		assert.False(t, claims.Equal(&other, auth.CompareTokenID()))
+	})
+
+	t.Run("should detect differences", func(t *testing.T) {
+		otherEmail := *expected
+		otherEmail.Email = "other-email"
+		assert.False(t, claims.Equal(&otherEmail))
+
+		otherIssuer := *expected
+		otherIssuer.Issuer = "other-issuer"
+		assert.False(t, claims.Equal(&otherIssuer))
+
+		otherCustom := *expected
+		otherCustom.Custom = map[string]interface{}{"account_id": 43, "plan": "pro"}
+		assert.False(t, claims.Equal(&otherCustom))
+
+		noCustom := *expected
+		noCustom.Custom = nil
+		assert.False(t, claims.Equal(&noCustom))
+	})
+
+	t.Run("should handle nil claims", func(t *testing.T) {
+		var none *auth.JwtClaim
+		assert.True(t, none.Equal(nil))
+		assert.False(t, claims.Equal(nil))
+		assert.False(t, none.Equal(claims))
+	})
+}
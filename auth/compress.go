@@ -0,0 +1,139 @@
+package auth
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// DefaultMaxClaimsSize is the maximum decompressed size of the claims of
+// compressed tokens unless MaxClaimsSize is set.
+const DefaultMaxClaimsSize = 64 * 1024
+
+// compressionDeflate is the "zip" header value of DEFLATE-compressed tokens,
+// as defined for JWE by RFC 7516.
+const compressionDeflate = "DEF"
+
+// signCompressed signs the token with its claims DEFLATE-compressed.
+func signCompressed(token *jwt.Token, key interface{}) (string, error) {
+	token.Header["zip"] = compressionDeflate
+
+	header, err := json.Marshal(token.Header)
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(token.Claims)
+	if err != nil {
+		return "", err
+	}
+
+	var payload bytes.Buffer
+	w, err := flate.NewWriter(&payload, flate.BestCompression)
+	if err != nil {
+		return "", err
+	}
+	if _, err := w.Write(claims); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+
+	signingString := jwt.EncodeSegment(header) + "." + jwt.EncodeSegment(payload.Bytes())
+	signature, err := token.Method.Sign(signingString, key)
+	if err != nil {
+		return "", err
+	}
+
+	return signingString + "." + signature, nil
+}
+
+// isCompressed reports whether the header of the token marks its claims as
+// DEFLATE-compressed.
+func isCompressed(signedToken string) bool {
+	encodedHeader, _, ok := strings.Cut(signedToken, ".")
+	if !ok {
+		return false
+	}
+	header, err := jwt.DecodeSegment(encodedHeader)
+	if err != nil {
+		return false
+	}
+
+	var fields struct {
+		Zip string `json:"zip"`
+	}
+	return json.Unmarshal(header, &fields) == nil && fields.Zip == compressionDeflate
+}
+
+// parseCompressed parses a token with DEFLATE-compressed claims. The signature
+// is verified before anything is decompressed, and the claims are rejected
+// with ErrTokenTooLarge once they exceed maxSize.
+func parseCompressed(parser *jwt.Parser, signedToken string, keyFunc jwt.Keyfunc, maxSize int) (*jwt.Token, error) {
+	parts := strings.Split(signedToken, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformedToken
+	}
+
+	header, err := jwt.DecodeSegment(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformedToken, err)
+	}
+	token := &jwt.Token{Raw: signedToken}
+	if err := json.Unmarshal(header, &token.Header); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformedToken, err)
+	}
+
+	alg, _ := token.Header["alg"].(string)
+	if token.Method = jwt.GetSigningMethod(alg); token.Method == nil {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	if len(parser.ValidMethods) > 0 && !slices.Contains(parser.ValidMethods, alg) {
+		return nil, fmt.Errorf("unexpected signing method: %v", alg)
+	}
+
+	key, err := keyFunc(token)
+	if err != nil {
+		return nil, err
+	}
+	if err := token.Method.Verify(parts[0]+"."+parts[1], parts[2], key); err != nil {
+		return nil, err
+	}
+
+	payload, err := jwt.DecodeSegment(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformedToken, err)
+	}
+	claims, err := io.ReadAll(io.LimitReader(flate.NewReader(bytes.NewReader(payload)), int64(maxSize)+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformedToken, err)
+	}
+	if len(claims) > maxSize {
+		return nil, ErrTokenTooLarge
+	}
+
+	// Decode the claims with the parser's settings, the signature being verified above
+	decoded, _, err := parser.ParseUnverified(parts[0]+"."+jwt.EncodeSegment(claims)+"."+parts[2], &JwtClaim{})
+	if err != nil {
+		return nil, err
+	}
+	token.Claims = decoded.Claims
+	token.Signature = parts[2]
+	token.Valid = true
+
+	return token, nil
+}
+
+// maxClaimsSize returns the maximum decompressed size of compressed claims.
+func (j *JwtWrapper) maxClaimsSize() int {
+	if j.MaxClaimsSize != 0 {
+		return j.MaxClaimsSize
+	}
+	return DefaultMaxClaimsSize
+}
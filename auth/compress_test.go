@@ -0,0 +1,109 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/junkd0g/go-microservice-commons/auth"
+)
+
+func Test_ClaimsCompression(t *testing.T) {
+	ctx := context.Background()
+
+	permissions := make([]string, 200)
+	for i := range permissions {
+		permissions[i] = fmt.Sprintf("orders:%d:read", i)
+	}
+
+	compressing := newTestWrapper(t, auth.WithClaimsCompression())
+	plain := newTestWrapper(t)
+
+	t.Run("should round-trip compressed claims", func(t *testing.T) {
+		compressed, err := compressing.GenerateTokenWithScopes(ctx, "some-uuid", "some-email", permissions)
+		assert.NoError(t, err)
+		uncompressed, err := plain.GenerateTokenWithScopes(ctx, "some-uuid", "some-email", permissions)
+		assert.NoError(t, err)
+		assert.Less(t, len(compressed), len(uncompressed)/2)
+
+		claims, err := compressing.ValidateToken(ctx, compressed)
+		assert.NoError(t, err)
+		assert.Equal(t, "some-uuid", claims.ID)
+		assert.Equal(t, permissions, claims.Scopes())
+	})
+
+	t.Run("should validate uncompressed tokens normally", func(t *testing.T) {
+		token, err := plain.GenerateToken(ctx, "some-uuid", "some-email")
+		assert.NoError(t, err)
+
+		claims, err := compressing.ValidateToken(ctx, token)
+		assert.NoError(t, err)
+		assert.Equal(t, "some-uuid", claims.ID)
+	})
+
+	t.Run("should decompress tokens whatever the setting", func(t *testing.T) {
+		token, err := compressing.GenerateToken(ctx, "some-uuid", "some-email")
+		assert.NoError(t, err)
+
+		claims, err := plain.ValidateToken(ctx, token)
+		assert.NoError(t, err)
+		assert.Equal(t, "some-uuid", claims.ID)
+	})
+
+	t.Run("should reject claims decompressing above the limit", func(t *testing.T) {
+		limited := newTestWrapper(t, auth.WithMaxClaimsSize(512))
+
+		token, err := compressing.GenerateTokenWithScopes(ctx, "some-uuid", "some-email", permissions)
+		assert.NoError(t, err)
+
+		claims, err := limited.ValidateToken(ctx, token)
+		assert.ErrorIs(t, err, auth.ErrTokenTooLarge)
+		assert.Nil(t, claims)
+	})
+
+	t.Run("should reject a tampered compressed token", func(t *testing.T) {
+		token, err := compressing.GenerateToken(ctx, "some-uuid", "some-email")
+		assert.NoError(t, err)
+
+		other := newTestWrapper(t, auth.WithSecret("other-secret-key"), auth.WithClaimsCompression())
+		otherToken, err := other.GenerateToken(ctx, "other-uuid", "some-email")
+		assert.NoError(t, err)
+
+		parts := strings.Split(token, ".")
+		otherParts := strings.Split(otherToken, ".")
+		tampered := parts[0] + "." + otherParts[1] + "." + parts[2]
+
+		claims, err := compressing.ValidateToken(ctx, tampered)
+		assert.Error(t, err)
+		assert.Nil(t, claims)
+	})
+
+	t.Run("should compress EdDSA tokens", func(t *testing.T) {
+		publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		assert.NoError(t, err)
+		jwtWrapper, err := auth.New(
+			auth.WithEdDSAKeys(privateKey, publicKey),
+			auth.WithIssuer("some-issuer"),
+			auth.WithExpirationHours(1),
+			auth.WithClaimsCompression(),
+		)
+		assert.NoError(t, err)
+
+		token, err := jwtWrapper.GenerateToken(ctx, "some-uuid", "some-email")
+		assert.NoError(t, err)
+
+		claims, err := jwtWrapper.ValidateToken(ctx, token)
+		assert.NoError(t, err)
+		assert.Equal(t, "some-uuid", claims.ID)
+
+		// An HMAC wrapper must not accept it
+		claims, err = plain.ValidateToken(ctx, token)
+		assert.Error(t, err)
+		assert.Nil(t, claims)
+	})
+}
@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"context"
+	"errors"
+
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+)
+
+// contextKey represents the type of the key for storing values within the context.
+type contextKey string
+
+// contextKeyClaims is the context key under which validated claims are stored.
+var contextKeyClaims = contextKey("claims")
+
+// WithClaims associates validated claims with a context. The user of the
+// claims is also stored with goctx.WithUser, which the logger reads to tag
+// entries with the authenticated user.
+func WithClaims(ctx context.Context, claims *JwtClaim) context.Context {
+	if claims != nil {
+		ctx = goctx.WithUser(ctx, goctx.User{ID: claims.ID, Email: claims.Email})
+	}
+	return context.WithValue(ctx, contextKeyClaims, claims)
+}
+
+// ClaimsFromContext retrieves the claims associated with a context.
+// If the claims do not exist, it returns an ErrClaimsNotFound error.
+func ClaimsFromContext(ctx context.Context) (*JwtClaim, error) {
+	claims, ok := ctx.Value(contextKeyClaims).(*JwtClaim)
+	if !ok || claims == nil {
+		return nil, ErrClaimsNotFound
+	}
+	return claims, nil
+}
+
+// IsAuthenticated reports whether validated claims were stored in the context,
+// e.g. by the middleware, so handlers serving both public and protected
+// content can tell the two apart.
+func IsAuthenticated(ctx context.Context) bool {
+	_, err := ClaimsFromContext(ctx)
+	return err == nil
+}
+
+// Authenticate validates the token and returns a context carrying the claims,
+// ready for downstream use. It is intended for non-HTTP entry points such as
+// message queue consumers where the token arrives in message metadata.
+func Authenticate(ctx context.Context, validator Validator, token string) (context.Context, *JwtClaim, error) {
+	if validator == nil {
+		return ctx, nil, errors.New("validator must be set")
+	}
+
+	claims, err := validator.Validate(ctx, token)
+	if err != nil {
+		return ctx, nil, err
+	}
+
+	return WithClaims(ctx, claims), claims, nil
+}
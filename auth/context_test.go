@@ -0,0 +1,87 @@
+package auth_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/junkd0g/go-microservice-commons/auth"
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+)
+
+func Test_ClaimsContext(t *testing.T) {
+	t.Run("Add successfully claims and retrieve them", func(t *testing.T) {
+		claims := &auth.JwtClaim{ID: "some-uuid", Email: "some-email"}
+		ctx := auth.WithClaims(context.Background(), claims)
+
+		got, err := auth.ClaimsFromContext(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, claims, got)
+	})
+
+	t.Run("Store the user of the claims for the logger", func(t *testing.T) {
+		ctx := auth.WithClaims(context.Background(), &auth.JwtClaim{ID: "some-uuid", Email: "some-email"})
+
+		user, ok := goctx.UserFromContext(ctx)
+		assert.True(t, ok)
+		assert.Equal(t, goctx.User{ID: "some-uuid", Email: "some-email"}, user)
+	})
+
+	t.Run("No claims found", func(t *testing.T) {
+		got, err := auth.ClaimsFromContext(context.Background())
+		assert.ErrorIs(t, err, auth.ErrClaimsNotFound)
+		assert.Nil(t, got)
+	})
+}
+
+func Test_IsAuthenticated(t *testing.T) {
+	assert.True(t, auth.IsAuthenticated(auth.WithClaims(context.Background(), &auth.JwtClaim{ID: "some-uuid"})))
+	assert.False(t, auth.IsAuthenticated(context.Background()))
+}
+
+func Test_Authenticate(t *testing.T) {
+	jwtWrapper, err := auth.NewJwtWrapper("some-secret-key", "some-issuer", 1)
+	assert.NoError(t, err)
+
+	// consume simulates a queue consumer receiving a token in the message metadata.
+	consume := func(ctx context.Context, metadata map[string]string) (string, error) {
+		ctx, _, err := auth.Authenticate(ctx, jwtWrapper, metadata["authorization"])
+		if err != nil {
+			return "", err
+		}
+
+		// Downstream processing only has access to the context.
+		claims, err := auth.ClaimsFromContext(ctx)
+		if err != nil {
+			return "", err
+		}
+		return claims.ID, nil
+	}
+
+	t.Run("should attach the claims of a valid token to the context", func(t *testing.T) {
+		ctx := context.Background()
+
+		token, err := jwtWrapper.GenerateToken(ctx, "some-uuid", "some-email")
+		assert.NoError(t, err)
+
+		id, err := consume(ctx, map[string]string{"authorization": token})
+		assert.NoError(t, err)
+		assert.Equal(t, "some-uuid", id)
+	})
+
+	t.Run("should fail with an invalid token", func(t *testing.T) {
+		ctx := context.Background()
+
+		newCtx, claims, err := auth.Authenticate(ctx, jwtWrapper, "invalid-token")
+		assert.Error(t, err)
+		assert.Nil(t, claims)
+		assert.Equal(t, ctx, newCtx)
+	})
+
+	t.Run("should fail without a wrapper", func(t *testing.T) {
+		_, claims, err := auth.Authenticate(context.Background(), nil, "some-token")
+		assert.Error(t, err)
+		assert.Nil(t, claims)
+	})
+}
@@ -0,0 +1,198 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+// Errors returned when parsing Ed25519 keys.
+var (
+	// ErrInvalidEd25519Key is the error returned when data holds no Ed25519 key
+	ErrInvalidEd25519Key = errors.New("invalid Ed25519 key")
+
+	// ErrEncryptedKey is the error returned for passphrase protected OpenSSH keys
+	ErrEncryptedKey = errors.New("encrypted keys are not supported")
+)
+
+const (
+	// openSSHKeyMagic starts the decoded body of OpenSSH private keys.
+	openSSHKeyMagic = "openssh-key-v1\x00"
+	// sshEd25519 is the OpenSSH name of Ed25519 keys.
+	sshEd25519 = "ssh-ed25519"
+)
+
+// ParseEd25519PrivateKey parses an Ed25519 private key encoded either as a
+// PKCS #8 PEM block ("PRIVATE KEY"), as written by openssl, or as an
+// unencrypted OpenSSH key ("OPENSSH PRIVATE KEY"), as written by ssh-keygen.
+func ParseEd25519PrivateKey(data []byte) (ed25519.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%w: no PEM block found", ErrInvalidEd25519Key)
+	}
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidEd25519Key, err)
+		}
+		privateKey, ok := key.(ed25519.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("%w: unexpected key type %T", ErrInvalidEd25519Key, key)
+		}
+		return privateKey, nil
+	case "OPENSSH PRIVATE KEY":
+		return parseOpenSSHPrivateKey(block.Bytes)
+	}
+
+	return nil, fmt.Errorf("%w: unexpected PEM block %q", ErrInvalidEd25519Key, block.Type)
+}
+
+// ParseEd25519PublicKey parses an Ed25519 public key encoded either as a PKIX
+// PEM block ("PUBLIC KEY"), as written by openssl, or in the OpenSSH
+// authorized_keys format ("ssh-ed25519 AAAA... comment").
+func ParseEd25519PublicKey(data []byte) (ed25519.PublicKey, error) {
+	data = bytes.TrimSpace(data)
+
+	if bytes.HasPrefix(data, []byte(sshEd25519+" ")) {
+		return parseOpenSSHPublicKey(data)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%w: no PEM block or OpenSSH key found", ErrInvalidEd25519Key)
+	}
+	if block.Type != "PUBLIC KEY" {
+		return nil, fmt.Errorf("%w: unexpected PEM block %q", ErrInvalidEd25519Key, block.Type)
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidEd25519Key, err)
+	}
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: unexpected key type %T", ErrInvalidEd25519Key, key)
+	}
+	return publicKey, nil
+}
+
+// validateEdDSAKeys checks the key sizes and, when a private key is given,
+// that it belongs to the public key.
+func validateEdDSAKeys(privateKey ed25519.PrivateKey, publicKey ed25519.PublicKey) error {
+	if len(publicKey) != ed25519.PublicKeySize {
+		return errors.New("invalid EdDSA public key size")
+	}
+	if privateKey == nil {
+		return nil
+	}
+	if len(privateKey) != ed25519.PrivateKeySize {
+		return errors.New("invalid EdDSA private key size")
+	}
+	if !publicKey.Equal(privateKey.Public()) {
+		return errors.New("EdDSA private key does not match the public key")
+	}
+	return nil
+}
+
+// parseOpenSSHPublicKey parses an Ed25519 key in the authorized_keys format.
+func parseOpenSSHPublicKey(data []byte) (ed25519.PublicKey, error) {
+	fields := bytes.Fields(data)
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("%w: missing key data", ErrInvalidEd25519Key)
+	}
+
+	blob, err := base64.StdEncoding.DecodeString(string(fields[1]))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidEd25519Key, err)
+	}
+
+	r := &sshReader{buf: blob}
+	keyType := r.bytes()
+	key := r.bytes()
+	if r.err != nil || string(keyType) != sshEd25519 || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("%w: malformed OpenSSH public key", ErrInvalidEd25519Key)
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// parseOpenSSHPrivateKey parses the body of an unencrypted OpenSSH private key
+// holding a single Ed25519 key, following the openssh-key-v1 format described
+// in OpenSSH's PROTOCOL.key.
+func parseOpenSSHPrivateKey(data []byte) (ed25519.PrivateKey, error) {
+	if !bytes.HasPrefix(data, []byte(openSSHKeyMagic)) {
+		return nil, fmt.Errorf("%w: not an openssh-key-v1 key", ErrInvalidEd25519Key)
+	}
+
+	r := &sshReader{buf: data[len(openSSHKeyMagic):]}
+	cipherName := r.bytes()
+	r.bytes() // kdf name
+	r.bytes() // kdf options
+	keyCount := r.uint32()
+	r.bytes() // public key
+	private := &sshReader{buf: r.bytes()}
+	if r.err != nil {
+		return nil, fmt.Errorf("%w: malformed OpenSSH private key", ErrInvalidEd25519Key)
+	}
+	if string(cipherName) != "none" {
+		return nil, ErrEncryptedKey
+	}
+	if keyCount != 1 {
+		return nil, fmt.Errorf("%w: expected a single key, got %d", ErrInvalidEd25519Key, keyCount)
+	}
+
+	check1, check2 := private.uint32(), private.uint32()
+	keyType := private.bytes()
+	private.bytes() // public key
+	key := private.bytes()
+	if private.err != nil || check1 != check2 {
+		return nil, fmt.Errorf("%w: malformed OpenSSH private key", ErrInvalidEd25519Key)
+	}
+	if string(keyType) != sshEd25519 || len(key) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("%w: unexpected OpenSSH key type %q", ErrInvalidEd25519Key, keyType)
+	}
+
+	return ed25519.PrivateKey(bytes.Clone(key)), nil
+}
+
+// sshReader reads the uint32 and length-prefixed byte strings of the SSH wire
+// format, recording the first error instead of returning it from every call.
+type sshReader struct {
+	buf []byte
+	err error
+}
+
+// uint32 reads a big-endian uint32.
+func (r *sshReader) uint32() uint32 {
+	if r.err != nil {
+		return 0
+	}
+	if len(r.buf) < 4 {
+		r.err = errors.New("unexpected end of data")
+		return 0
+	}
+	v := binary.BigEndian.Uint32(r.buf)
+	r.buf = r.buf[4:]
+	return v
+}
+
+// bytes reads a length-prefixed byte string.
+func (r *sshReader) bytes() []byte {
+	n := r.uint32()
+	if r.err != nil {
+		return nil
+	}
+	if uint32(len(r.buf)) < n {
+		r.err = errors.New("unexpected end of data")
+		return nil
+	}
+	v := r.buf[:n]
+	r.buf = r.buf[n:]
+	return v
+}
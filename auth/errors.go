@@ -0,0 +1,56 @@
+package auth
+
+import "errors"
+
+var (
+	// ErrClaimsNotFound is the error returned when no claims are found in the context
+	ErrClaimsNotFound = errors.New("claims not found in context")
+
+	// ErrMissingToken is the error returned when the request carries no token
+	ErrMissingToken = errors.New("missing token")
+
+	// ErrInvalidAuthHeader is the error returned when the token header does not use the expected scheme
+	ErrInvalidAuthHeader = errors.New("invalid authorization header")
+
+	// ErrTokenRevoked is the error returned when the token has been revoked
+	ErrTokenRevoked = errors.New("token is revoked")
+
+	// ErrSubjectNotAllowed is the error returned when the token subject is not in the allow-list
+	ErrSubjectNotAllowed = errors.New("subject is not allowed")
+
+	// ErrInvalidIssuer is the error returned when the token issuer is not the expected one
+	ErrInvalidIssuer = errors.New("invalid issuer")
+
+	// ErrInvalidAudience is the error returned when the token audience does not contain the expected one
+	ErrInvalidAudience = errors.New("invalid audience")
+
+	// ErrNoExpiration is the error returned when an expiration is required but the token has none
+	ErrNoExpiration = errors.New("token has no expiration")
+
+	// ErrFingerprintMismatch is the error returned when the token is not bound to the expected fingerprint
+	ErrFingerprintMismatch = errors.New("token fingerprint mismatch")
+
+	// ErrMalformedToken is the error returned when the token is empty or only whitespace
+	ErrMalformedToken = errors.New("malformed token")
+
+	// ErrTokenInactive is the error returned when the introspection endpoint reports the token as inactive
+	ErrTokenInactive = errors.New("token is not active")
+
+	// ErrUnexpectedTokenType is the error returned when the token's typ header is not an accepted type
+	ErrUnexpectedTokenType = errors.New("unexpected token type")
+
+	// ErrTokenTooLarge is the error returned when the token exceeds the maximum accepted size
+	ErrTokenTooLarge = errors.New("token is too large")
+
+	// ErrMissingSigningKey is the error returned when generating a token with a verify-only wrapper
+	ErrMissingSigningKey = errors.New("missing signing key")
+
+	// ErrRefreshTokenNotFound is the error returned when the refresh token is unknown
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+
+	// ErrRefreshTokenRevoked is the error returned when the refresh token has been rotated or revoked
+	ErrRefreshTokenRevoked = errors.New("refresh token is revoked")
+
+	// ErrRefreshTokenExpired is the error returned when the refresh token has expired
+	ErrRefreshTokenExpired = errors.New("refresh token is expired")
+)
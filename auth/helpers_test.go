@@ -0,0 +1,33 @@
+package auth_test
+
+import (
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/junkd0g/go-microservice-commons/auth"
+)
+
+// signToken signs the given claims with HS256 and the given secret.
+func signToken(t *testing.T, secret string, claims jwt.Claims) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	assert.NoError(t, err)
+	return token
+}
+
+// newTestWrapper returns a wrapper with the test secret and issuer and a one
+// hour expiration, configured further by opts.
+func newTestWrapper(t *testing.T, opts ...auth.Option) *auth.JwtWrapper {
+	t.Helper()
+
+	jwtWrapper, err := auth.New(append([]auth.Option{
+		auth.WithSecret("some-secret-key"),
+		auth.WithIssuer("some-issuer"),
+		auth.WithExpirationHours(1),
+	}, opts...)...)
+	assert.NoError(t, err)
+	return jwtWrapper
+}
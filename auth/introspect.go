@@ -0,0 +1,40 @@
+package auth
+
+import (
+	"context"
+	"time"
+)
+
+// TokenIntrospection is the detailed result of validating a token.
+type TokenIntrospection struct {
+	// Claims are the validated claims of the token.
+	Claims *JwtClaim
+	// NearExpiry reports whether the token expires within the wrapper's
+	// NearExpiryThreshold. It is always false when the threshold is not set.
+	NearExpiry bool
+	// ValidationDuration is how long validating the token took, including
+	// revocation checks and custom rules.
+	ValidationDuration time.Duration
+}
+
+// IntrospectToken validates the token like ValidateToken and additionally
+// reports details about it, such as whether it is close to expiry so callers
+// can refresh it proactively.
+func (j *JwtWrapper) IntrospectToken(ctx context.Context, signedToken string) (*TokenIntrospection, error) {
+	start := time.Now()
+	claims, err := j.ValidateToken(ctx, signedToken)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &TokenIntrospection{
+		Claims:             claims,
+		ValidationDuration: time.Since(start),
+	}
+
+	if expiresAt, ok := claims.ExpiresAtTime(); ok && j.NearExpiryThreshold > 0 {
+		result.NearExpiry = expiresAt.Sub(j.now()) <= j.NearExpiryThreshold
+	}
+
+	return result, nil
+}
@@ -0,0 +1,121 @@
+package auth_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/junkd0g/go-microservice-commons/auth"
+)
+
+func Test_IntrospectToken(t *testing.T) {
+	t.Run("should flag a token close to expiry", func(t *testing.T) {
+		ctx := context.Background()
+
+		jwtWrapper, err := auth.NewJwtWrapper("some-secret-key", "some-issuer", 1)
+		assert.NoError(t, err)
+		jwtWrapper.NearExpiryThreshold = time.Minute
+
+		token := signToken(t, "some-secret-key", &auth.JwtClaim{
+			ID: "some-uuid",
+			RegisteredClaims: jwt.RegisteredClaims{
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(30 * time.Second)),
+				Issuer:    "some-issuer",
+			},
+		})
+
+		result, err := jwtWrapper.IntrospectToken(ctx, token)
+		assert.NoError(t, err)
+		assert.True(t, result.NearExpiry)
+		assert.Equal(t, "some-uuid", result.Claims.ID)
+	})
+
+	t.Run("should not flag a token far from expiry", func(t *testing.T) {
+		ctx := context.Background()
+
+		jwtWrapper, err := auth.NewJwtWrapper("some-secret-key", "some-issuer", 1)
+		assert.NoError(t, err)
+		jwtWrapper.NearExpiryThreshold = time.Minute
+
+		token, err := jwtWrapper.GenerateToken(ctx, "some-uuid", "some-email")
+		assert.NoError(t, err)
+
+		result, err := jwtWrapper.IntrospectToken(ctx, token)
+		assert.NoError(t, err)
+		assert.False(t, result.NearExpiry)
+	})
+
+	t.Run("should not flag anything when the threshold is disabled", func(t *testing.T) {
+		ctx := context.Background()
+
+		jwtWrapper, err := auth.NewJwtWrapper("some-secret-key", "some-issuer", 1)
+		assert.NoError(t, err)
+
+		token := signToken(t, "some-secret-key", &auth.JwtClaim{
+			RegisteredClaims: jwt.RegisteredClaims{
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(30 * time.Second)),
+			},
+		})
+
+		result, err := jwtWrapper.IntrospectToken(ctx, token)
+		assert.NoError(t, err)
+		assert.False(t, result.NearExpiry)
+	})
+
+	t.Run("should fail with an invalid token", func(t *testing.T) {
+		jwtWrapper, err := auth.NewJwtWrapper("some-secret-key", "some-issuer", 1)
+		assert.NoError(t, err)
+
+		result, err := jwtWrapper.IntrospectToken(context.Background(), "invalid-token")
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+}
+
+func Test_ValidationDuration(t *testing.T) {
+	ctx := context.Background()
+
+	type recorded struct {
+		duration time.Duration
+		err      error
+	}
+	var records []recorded
+
+	jwtWrapper, err := auth.New(
+		auth.WithSecret("some-secret-key"),
+		auth.WithIssuer("some-issuer"),
+		auth.WithExpirationHours(1),
+		auth.WithValidationRecorder(func(ctx context.Context, duration time.Duration, err error) {
+			records = append(records, recorded{duration: duration, err: err})
+		}),
+	)
+	assert.NoError(t, err)
+
+	token, err := jwtWrapper.GenerateToken(ctx, "some-uuid", "some-email")
+	assert.NoError(t, err)
+
+	t.Run("should populate the duration of the introspection", func(t *testing.T) {
+		records = nil
+
+		result, err := jwtWrapper.IntrospectToken(ctx, token)
+		assert.NoError(t, err)
+		assert.Positive(t, result.ValidationDuration)
+
+		assert.Len(t, records, 1)
+		assert.Positive(t, records[0].duration)
+		assert.NoError(t, records[0].err)
+	})
+
+	t.Run("should record failed validations", func(t *testing.T) {
+		records = nil
+
+		_, err := jwtWrapper.ValidateToken(ctx, "invalid-token")
+		assert.Error(t, err)
+
+		assert.Len(t, records, 1)
+		assert.Equal(t, err, records[0].err)
+	})
+}
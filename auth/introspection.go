@@ -0,0 +1,146 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	// defaultIntrospectionCacheSize is the number of active tokens cached by default.
+	defaultIntrospectionCacheSize = 1024
+
+	// defaultIntrospectionCacheTTL is how long an active token is cached by default.
+	defaultIntrospectionCacheTTL = 30 * time.Second
+
+	// maxIntrospectionResponseSize bounds the introspection responses read, so
+	// a misbehaving server can't make the service buffer unbounded JSON.
+	maxIntrospectionResponseSize = 1 << 20
+)
+
+// IntrospectionValidator validates opaque (non-JWT) tokens through an OAuth 2.0
+// token introspection endpoint (RFC 7662) and normalizes the response into a
+// JwtClaim, so the same downstream code handles both JWT and opaque tokens.
+type IntrospectionValidator struct {
+	Endpoint     string
+	ClientID     string
+	ClientSecret string
+
+	// HTTPClient is used to call the endpoint. Nil means http.DefaultClient.
+	HTTPClient *http.Client
+
+	// Cache keeps active tokens briefly to avoid calling the endpoint for every
+	// request. Nil disables caching.
+	Cache *TokenCache
+}
+
+// introspectionResponse is the RFC 7662 introspection response.
+type introspectionResponse struct {
+	Active   bool   `json:"active"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	jwt.RegisteredClaims
+}
+
+// NewIntrospectionValidator creates a new IntrospectionValidator authenticating
+// to the endpoint with the given client credentials. Active tokens are cached
+// for 30 seconds, or until their expiry if sooner.
+func NewIntrospectionValidator(endpoint, clientID, clientSecret string) (*IntrospectionValidator, error) {
+	if endpoint == "" {
+		return nil, errors.New("introspection endpoint must be set")
+	}
+
+	if clientID == "" {
+		return nil, errors.New("client id must be set")
+	}
+
+	return &IntrospectionValidator{
+		Endpoint:     endpoint,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Cache:        NewTokenCache(defaultIntrospectionCacheSize, defaultIntrospectionCacheTTL),
+	}, nil
+}
+
+// ValidateToken introspects the token and returns its normalized claims. The
+// subject is mapped to the ID claim. Inactive tokens are rejected with
+// ErrTokenInactive. The request honors the cancellation of ctx.
+func (v *IntrospectionValidator) ValidateToken(ctx context.Context, token string) (*JwtClaim, error) {
+	token, err := normalizeToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if v.Cache != nil {
+		if claims, ok := v.Cache.get(token, time.Now()); ok {
+			return claims, nil
+		}
+	}
+
+	response, err := v.introspect(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if !response.Active {
+		return nil, ErrTokenInactive
+	}
+
+	claims := &JwtClaim{
+		ID:               response.Subject,
+		Email:            response.Email,
+		RegisteredClaims: response.RegisteredClaims,
+	}
+
+	if v.Cache != nil {
+		v.Cache.add(token, claims, time.Now())
+	}
+
+	return claims, nil
+}
+
+// introspect calls the introspection endpoint for the token.
+func (v *IntrospectionValidator) introspect(ctx context.Context, token string) (*introspectionResponse, error) {
+	form := url.Values{
+		"token":           {token},
+		"token_type_hint": {"access_token"},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.Endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("creating introspection request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(v.ClientID), url.QueryEscape(v.ClientSecret))
+
+	client := v.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("calling introspection endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("introspection endpoint returned status %d", resp.StatusCode)
+	}
+
+	var response introspectionResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxIntrospectionResponseSize)).Decode(&response); err != nil {
+		return nil, fmt.Errorf("decoding introspection response: %w", err)
+	}
+
+	return &response, nil
+}
@@ -0,0 +1,135 @@
+package auth_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/junkd0g/go-microservice-commons/auth"
+)
+
+// newIntrospectionServer returns a fake RFC 7662 endpoint that knows a single
+// active token and counts the calls it receives.
+func newIntrospectionServer(t *testing.T, calls *int32) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+
+		clientID, clientSecret, ok := r.BasicAuth()
+		if !ok || clientID != "some-client" || clientSecret != "some-secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if r.PostFormValue("token") != "opaque-token" {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"active": false})
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"active": true,
+			"sub":    "some-uuid",
+			"email":  "user@example.com",
+			"iss":    "some-issuer",
+			"aud":    "some-api",
+			"exp":    time.Now().Add(time.Hour).Unix(),
+		})
+	}))
+}
+
+func Test_NewIntrospectionValidator(t *testing.T) {
+	_, err := auth.NewIntrospectionValidator("", "some-client", "some-secret")
+	assert.EqualError(t, err, "introspection endpoint must be set")
+
+	_, err = auth.NewIntrospectionValidator("http://localhost", "", "some-secret")
+	assert.EqualError(t, err, "client id must be set")
+}
+
+func Test_IntrospectionValidator(t *testing.T) {
+	t.Run("should normalize the claims of an active token and cache them", func(t *testing.T) {
+		var calls int32
+		server := newIntrospectionServer(t, &calls)
+		defer server.Close()
+
+		validator, err := auth.NewIntrospectionValidator(server.URL, "some-client", "some-secret")
+		assert.NoError(t, err)
+
+		for i := 0; i < 3; i++ {
+			claims, err := validator.ValidateToken(context.Background(), "opaque-token")
+			assert.NoError(t, err)
+			assert.Equal(t, "some-uuid", claims.ID)
+			assert.Equal(t, "user@example.com", claims.Email)
+			assert.Equal(t, "some-issuer", claims.Issuer)
+			assert.Equal(t, []string{"some-api"}, []string(claims.Audience))
+		}
+
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("should reject an inactive token", func(t *testing.T) {
+		var calls int32
+		server := newIntrospectionServer(t, &calls)
+		defer server.Close()
+
+		validator, err := auth.NewIntrospectionValidator(server.URL, "some-client", "some-secret")
+		assert.NoError(t, err)
+
+		claims, err := validator.ValidateToken(context.Background(), "revoked-token")
+		assert.ErrorIs(t, err, auth.ErrTokenInactive)
+		assert.Nil(t, claims)
+	})
+
+	t.Run("should fail when the endpoint rejects the client", func(t *testing.T) {
+		var calls int32
+		server := newIntrospectionServer(t, &calls)
+		defer server.Close()
+
+		validator, err := auth.NewIntrospectionValidator(server.URL, "some-client", "wrong-secret")
+		assert.NoError(t, err)
+
+		claims, err := validator.ValidateToken(context.Background(), "opaque-token")
+		assert.ErrorContains(t, err, "status 401")
+		assert.Nil(t, claims)
+	})
+
+	t.Run("should fail on an oversized response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"active": true,
+				"sub":    strings.Repeat("a", 2<<20),
+			})
+		}))
+		defer server.Close()
+
+		validator, err := auth.NewIntrospectionValidator(server.URL, "some-client", "some-secret")
+		assert.NoError(t, err)
+
+		claims, err := validator.ValidateToken(context.Background(), "opaque-token")
+		assert.ErrorContains(t, err, "decoding introspection response")
+		assert.Nil(t, claims)
+	})
+
+	t.Run("should honor context cancellation", func(t *testing.T) {
+		var calls int32
+		server := newIntrospectionServer(t, &calls)
+		defer server.Close()
+
+		validator, err := auth.NewIntrospectionValidator(server.URL, "some-client", "some-secret")
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		claims, err := validator.ValidateToken(ctx, "opaque-token")
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, claims)
+	})
+}
@@ -5,11 +5,18 @@ package auth
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/subtle"
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
+
+	goctx "github.com/junkd0g/go-microservice-commons/context"
 )
 
 // JwtWrapper wraps the signing key and the issuer.
@@ -17,49 +24,194 @@ type JwtWrapper struct {
 	SecretKey       string
 	Issuer          string
 	ExpirationHours int64
+
+	// Expiration is the default token lifetime. When set it takes precedence
+	// over ExpirationHours, allowing lifetimes shorter than an hour.
+	Expiration time.Duration
+
+	// Leeway is the clock skew tolerated when checking the exp, iat and nbf claims.
+	Leeway time.Duration
+
+	// Clock returns the current time used to issue and validate tokens.
+	// Nil means time.Now.
+	Clock func() time.Time
+
+	// IDGenerator returns the unique token ID (jti) of generated tokens.
+	// Nil means a random UUID v4.
+	IDGenerator func() string
+
+	// ValidateFunc is an optional hook for custom business rules, invoked at the
+	// end of ValidateToken. A non-nil error is returned as the validation error.
+	ValidateFunc func(ctx context.Context, claims *JwtClaim) error
+
+	// NearExpiryThreshold makes IntrospectToken flag tokens expiring within
+	// this duration. Zero disables the check.
+	NearExpiryThreshold time.Duration
+
+	// Revoker is consulted on every validation, including cached ones, to
+	// reject revoked tokens. Nil disables revocation checks.
+	Revoker Revoker
+
+	// RevocationFailOpen treats tokens as not revoked when the Revoker fails,
+	// e.g. during a store outage, logging a warning through the context
+	// logger, or the Logger when the context has none. By default such
+	// validations fail, favoring security over availability.
+	RevocationFailOpen bool
+
+	// ValidationOptions declare additional rules applied when parsing tokens,
+	// such as the expected issuer or audience.
+	ValidationOptions []ValidationOption
+
+	// Cache keeps recently verified tokens so identical tokens skip signature
+	// verification. Nil disables caching.
+	Cache *TokenCache
+
+	// Logger receives a warning from New when the secret is weak, and warnings
+	// logged during validation when the context has no logger. Nil disables it.
+	Logger goctx.Logger
+
+	// RefreshStore keeps the refresh tokens issued by IssueRefreshToken.
+	// Nil disables refresh tokens.
+	RefreshStore RefreshStore
+
+	// RefreshExpiration is the lifetime of refresh tokens. Zero means DefaultRefreshExpiration.
+	RefreshExpiration time.Duration
+
+	// MaxTokenSize is the length in bytes above which tokens are rejected with
+	// ErrTokenTooLarge before being parsed. Zero means DefaultMaxTokenSize.
+	MaxTokenSize int
+
+	// CompressClaims DEFLATE-compresses the claims of generated tokens, marked
+	// with a "zip": "DEF" header, to keep tokens with large claim sets small.
+	// Compressed tokens are decompressed on validation whatever the setting.
+	CompressClaims bool
+
+	// ValidationRecorder, when set, is called after every ValidateToken with
+	// how long the validation took and its error, nil on success, e.g. to feed
+	// a latency histogram. Nil disables the timing.
+	ValidationRecorder func(ctx context.Context, duration time.Duration, err error)
+
+	// MaxClaimsSize is the decompressed size in bytes above which compressed
+	// tokens are rejected with ErrTokenTooLarge, guarding against decompression
+	// bombs. Zero means DefaultMaxClaimsSize.
+	MaxClaimsSize int
+
+	// EdDSAPublicKey, when set, switches the wrapper from HS256 to EdDSA:
+	// tokens are verified with this key instead of the SecretKey, and signed
+	// with EdDSAPrivateKey. A nil EdDSAPrivateKey makes a verify-only wrapper.
+	EdDSAPublicKey  ed25519.PublicKey
+	EdDSAPrivateKey ed25519.PrivateKey
+
+	// SigningMethod is the HMAC variant signing generated tokens: HS256,
+	// HS384 or HS512. Nil means HS256. Tokens signed with any HMAC variant are
+	// accepted on validation, so the method can be upgraded without rejecting
+	// tokens issued before.
+	SigningMethod *jwt.SigningMethodHMAC
+
+	// KeyProvider, when set, supplies the HMAC keys in place of the SecretKey,
+	// allowing keys to be rotated without rebuilding the wrapper. It cannot be
+	// combined with EdDSA keys.
+	KeyProvider KeyProvider
+
+	// subjectAllowList is the set of subjects replaced by SetSubjectAllowList.
+	// It is swapped atomically so it can change while tokens are validated.
+	subjectAllowList atomic.Pointer[map[string]struct{}]
 }
 
+// DefaultMaxTokenSize is the maximum accepted token length unless MaxTokenSize is set.
+const DefaultMaxTokenSize = 8 * 1024
+
 // JwtClaim adds email as a claim to the token.
 type JwtClaim struct {
 	ID    string `json:"ID"`
 	Email string `json:"Email"`
+	// Fingerprint binds the token to a client, e.g. a hashed device ID.
+	Fingerprint string `json:"fpt,omitempty"`
+	// Scope holds the granted OAuth scopes, space-delimited. Use Scopes to read them.
+	Scope string `json:"scope,omitempty"`
+	// Custom holds the claims not mapped to a field. Numbers are decoded as
+	// json.Number to keep their precision; read them with Int64Claim and
+	// Float64Claim.
+	Custom map[string]interface{} `json:"-"`
+	// KeyID is the "kid" header of the validated token, if any. It is not
+	// used to select the verification key; it is exposed so callers can audit
+	// which key tokens claim to be signed with, e.g. during a key rotation.
+	KeyID string `json:"-"`
 	jwt.RegisteredClaims
 }
 
 // NewJwtWrapper creates a new JwtWrapper object.
+// It is a shorthand for New with WithSecret, WithIssuer and WithExpirationHours.
 func NewJwtWrapper(secretKey, issuer string, expirationHours int64) (*JwtWrapper, error) {
-	if secretKey == "" {
-		return nil, errors.New("secret key must be set")
-	}
+	return New(
+		WithSecret(secretKey),
+		WithIssuer(issuer),
+		WithExpirationHours(expirationHours),
+	)
+}
+
+// NewEdDSAJwtWrapper creates a new JwtWrapper signing tokens with the Ed25519
+// private key and verifying them with the public key. The private key may be
+// nil for services that only validate tokens. It is a shorthand for New with
+// WithEdDSAKeys, WithIssuer and WithExpirationHours.
+func NewEdDSAJwtWrapper(privateKey ed25519.PrivateKey, publicKey ed25519.PublicKey, issuer string, expirationHours int64) (*JwtWrapper, error) {
+	return New(
+		WithEdDSAKeys(privateKey, publicKey),
+		WithIssuer(issuer),
+		WithExpirationHours(expirationHours),
+	)
+}
+
+// GenerateToken generates a jwt token.
+func (j *JwtWrapper) GenerateToken(ctx context.Context, uuid, email string) (string, error) {
+	return j.generateToken(ctx, &JwtClaim{ID: uuid, Email: email}, j.expiration())
+}
 
-	if issuer == "" {
-		return nil, errors.New("issuer must be set")
+// GenerateTokenWithFingerprint generates a jwt token bound to the given client
+// fingerprint, e.g. a hashed device ID. Validate it with ValidateTokenWithFingerprint.
+func (j *JwtWrapper) GenerateTokenWithFingerprint(ctx context.Context, uuid, email, fingerprint string) (string, error) {
+	if fingerprint == "" {
+		return "", errors.New("fingerprint must be set")
 	}
+	claims := &JwtClaim{ID: uuid, Email: email, Fingerprint: fingerprint}
+	return j.generateToken(ctx, claims, j.expiration())
+}
+
+// GenerateTokenWithScopes generates a jwt token granting the given scopes,
+// joined with spaces into the scope claim as in OAuth 2.0.
+func (j *JwtWrapper) GenerateTokenWithScopes(ctx context.Context, uuid, email string, scopes []string) (string, error) {
+	claims := &JwtClaim{ID: uuid, Email: email, Scope: strings.Join(scopes, " ")}
+	return j.generateToken(ctx, claims, j.expiration())
+}
 
-	if expirationHours == 0 {
-		return nil, errors.New("expiration hours must be greater than 0")
+// GenerateTokenWithTTL generates a jwt token expiring after ttl instead of the
+// wrapper's ExpirationHours, e.g. for short-lived password-reset tokens.
+func (j *JwtWrapper) GenerateTokenWithTTL(ctx context.Context, uuid, email string, ttl time.Duration) (string, error) {
+	if ttl <= 0 {
+		return "", errors.New("ttl must be greater than 0")
 	}
-	return &JwtWrapper{
-		SecretKey:       secretKey,
-		Issuer:          issuer,
-		ExpirationHours: expirationHours,
-	}, nil
+	return j.generateToken(ctx, &JwtClaim{ID: uuid, Email: email}, ttl)
 }
 
-// GenerateToken generates a jwt token.
-func (j *JwtWrapper) GenerateToken(ctx context.Context, uuid, email string) (string, error) {
-	claims := &JwtClaim{
-		ID:    uuid,
-		Email: email,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Local().Add(time.Hour * time.Duration(j.ExpirationHours))),
-			Issuer:    j.Issuer,
-		},
+// generateToken signs a token for the given claims expiring after ttl.
+func (j *JwtWrapper) generateToken(ctx context.Context, claims *JwtClaim, ttl time.Duration) (string, error) {
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(j.now().Local().Add(ttl)),
+		Issuer:    j.Issuer,
+		ID:        j.newTokenID(),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	method, key, err := j.signingMethodAndKey(ctx)
+	if err != nil {
+		return "", err
+	}
 
-	signedToken, err := token.SignedString([]byte(j.SecretKey))
+	token := jwt.NewWithClaims(method, claims)
+	if j.CompressClaims {
+		return signCompressed(token, key)
+	}
+
+	signedToken, err := token.SignedString(key)
 	if err != nil {
 		return "", err
 	}
@@ -67,19 +219,352 @@ func (j *JwtWrapper) GenerateToken(ctx context.Context, uuid, email string) (str
 	return signedToken, nil
 }
 
-// ValidateToken validates the jwt token.
+// signingMethodAndKey returns the method and key signing the wrapper's tokens.
+func (j *JwtWrapper) signingMethodAndKey(ctx context.Context) (jwt.SigningMethod, interface{}, error) {
+	if j.KeyProvider != nil {
+		key, err := j.KeyProvider.SigningKey(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("fetching signing key: %w", err)
+		}
+		return j.hmacMethod(), key, nil
+	}
+	if !j.usesEdDSA() {
+		return j.hmacMethod(), []byte(j.SecretKey), nil
+	}
+	if j.EdDSAPrivateKey == nil {
+		return nil, nil, ErrMissingSigningKey
+	}
+	return jwt.SigningMethodEdDSA, j.EdDSAPrivateKey, nil
+}
+
+// ValidateToken validates the jwt token. The validation is timed and reported
+// to the ValidationRecorder, if set.
 func (j *JwtWrapper) ValidateToken(ctx context.Context, signedToken string) (*JwtClaim, error) {
-	token, err := jwt.ParseWithClaims(
-		signedToken,
-		&JwtClaim{},
-		func(token *jwt.Token) (interface{}, error) {
-			// Validate the signing method to prevent algorithm confusion attacks
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	if j.ValidationRecorder == nil {
+		return j.validateToken(ctx, signedToken)
+	}
+
+	start := time.Now()
+	claims, err := j.validateToken(ctx, signedToken)
+	j.ValidationRecorder(ctx, time.Since(start), err)
+	return claims, err
+}
+
+// validateToken validates the jwt token without recording the validation.
+func (j *JwtWrapper) validateToken(ctx context.Context, signedToken string) (*JwtClaim, error) {
+	if err := j.checkTokenSize(signedToken); err != nil {
+		return nil, err
+	}
+
+	signedToken, err := normalizeToken(signedToken)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := j.verifyToken(ctx, signedToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := j.checkClaims(ctx, claims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// ValidateTokenWithFingerprint validates the jwt token like ValidateToken and
+// additionally requires it to be bound to the expected client fingerprint,
+// returning ErrFingerprintMismatch otherwise. An empty expected fingerprint
+// behaves exactly like ValidateToken.
+func (j *JwtWrapper) ValidateTokenWithFingerprint(ctx context.Context, signedToken, fingerprint string) (*JwtClaim, error) {
+	claims, err := j.ValidateToken(ctx, signedToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if fingerprint != "" && subtle.ConstantTimeCompare([]byte(claims.Fingerprint), []byte(fingerprint)) != 1 {
+		return nil, ErrFingerprintMismatch
+	}
+
+	return claims, nil
+}
+
+// ValidateTokenMultiKey validates the jwt token against each key in order and
+// returns the claims of the first successful validation, or the last error if
+// every key fails. It is meant for secret rotations where tokens signed with
+// either the old or the new key are accepted during an overlap period.
+func (j *JwtWrapper) ValidateTokenMultiKey(ctx context.Context, signedToken string, keys ...[]byte) (*JwtClaim, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("at least one key must be provided")
+	}
+
+	if err := j.checkTokenSize(signedToken); err != nil {
+		return nil, err
+	}
+
+	signedToken, err := normalizeToken(signedToken)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := j.parseTokenWithKeys(signedToken, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := j.checkClaims(ctx, claims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// parseTokenWithKeys parses the token with each HMAC key in order and returns
+// the claims of the first successful parse, or the last error if every key fails.
+func (j *JwtWrapper) parseTokenWithKeys(signedToken string, keys [][]byte) (*JwtClaim, error) {
+	var lastErr error
+	for _, key := range keys {
+		claims, err := j.parseToken(signedToken, key)
+		if err == nil {
+			return claims, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// SetSubjectAllowList restricts valid tokens to those whose subject (the ID
+// claim) is one of subjects, replacing any previous list. It is meant as a
+// short-term lever during a security incident and is safe to call while tokens
+// are validated; call it without subjects to allow all subjects again.
+func (j *JwtWrapper) SetSubjectAllowList(subjects ...string) {
+	allowList := make(map[string]struct{}, len(subjects))
+	for _, subject := range subjects {
+		allowList[subject] = struct{}{}
+	}
+	j.subjectAllowList.Store(&allowList)
+}
+
+// SubjectAllowList returns the sorted subjects set by SetSubjectAllowList, or
+// nil when all subjects are allowed.
+func (j *JwtWrapper) SubjectAllowList() []string {
+	allowList := j.subjectAllowList.Load()
+	if allowList == nil || len(*allowList) == 0 {
+		return nil
+	}
+
+	subjects := make([]string, 0, len(*allowList))
+	for subject := range *allowList {
+		subjects = append(subjects, subject)
+	}
+	sort.Strings(subjects)
+	return subjects
+}
+
+// checkClaims applies the allow-list, revocation and custom rules to verified claims.
+func (j *JwtWrapper) checkClaims(ctx context.Context, claims *JwtClaim) error {
+	if allowList := j.subjectAllowList.Load(); allowList != nil && len(*allowList) > 0 {
+		if _, ok := (*allowList)[claims.ID]; !ok {
+			return ErrSubjectNotAllowed
+		}
+	}
+
+	if j.Revoker != nil {
+		revoked, err := j.Revoker.IsRevoked(ctx, claims)
+		if err != nil {
+			if !j.RevocationFailOpen {
+				return fmt.Errorf("checking revocation: %w", err)
+			}
+			if logger := j.logger(ctx); logger != nil {
+				goctx.Warn(ctx, logger, "revocation check failed, accepting token", map[string]interface{}{
+					"error": err.Error(),
+				})
 			}
-			return []byte(j.SecretKey), nil
-		},
+		}
+		if revoked {
+			return ErrTokenRevoked
+		}
+	}
+
+	if j.ValidateFunc != nil {
+		if err := j.ValidateFunc(ctx, claims); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkTokenSize rejects tokens longer than the wrapper's MaxTokenSize so
+// oversized tokens are never parsed.
+func (j *JwtWrapper) checkTokenSize(signedToken string) error {
+	maxSize := j.MaxTokenSize
+	if maxSize == 0 {
+		maxSize = DefaultMaxTokenSize
+	}
+	if len(signedToken) > maxSize {
+		return ErrTokenTooLarge
+	}
+	return nil
+}
+
+// normalizeToken trims the stray whitespace and newlines clients sometimes
+// send around tokens, returning ErrMalformedToken when nothing is left.
+func normalizeToken(signedToken string) (string, error) {
+	signedToken = strings.TrimSpace(signedToken)
+	if signedToken == "" {
+		return "", ErrMalformedToken
+	}
+	return signedToken, nil
+}
+
+// verifyToken returns the claims of a token with a verified signature and expiry,
+// serving them from the cache when one is configured.
+func (j *JwtWrapper) verifyToken(ctx context.Context, signedToken string) (*JwtClaim, error) {
+	if j.Cache != nil {
+		if claims, ok := j.Cache.get(signedToken, j.now()); ok {
+			return claims, nil
+		}
+	}
+
+	var (
+		claims *JwtClaim
+		err    error
 	)
+	if j.KeyProvider != nil {
+		claims, err = j.parseWithProvidedKeys(ctx, signedToken)
+	} else {
+		claims, err = j.parseToken(signedToken, j.verificationKey())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if j.Cache != nil {
+		j.Cache.add(signedToken, claims, j.now())
+	}
+
+	return claims, nil
+}
+
+// parseWithProvidedKeys parses the token with the verification keys of the
+// wrapper's KeyProvider.
+func (j *JwtWrapper) parseWithProvidedKeys(ctx context.Context, signedToken string) (*JwtClaim, error) {
+	keys, err := j.KeyProvider.VerificationKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetching verification keys: %w", err)
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("key provider returned no verification keys")
+	}
+	return j.parseTokenWithKeys(signedToken, keys)
+}
+
+// validateTimeClaims checks the exp, iat and nbf claims against now, tolerating
+// the given leeway for clock skew. Errors mirror those of the jwt package.
+func validateTimeClaims(claims *JwtClaim, now time.Time, leeway time.Duration) error {
+	if !claims.VerifyExpiresAt(now.Add(-leeway), false) {
+		return jwt.NewValidationError("token is expired", jwt.ValidationErrorExpired)
+	}
+
+	if !claims.VerifyIssuedAt(now.Add(leeway), false) {
+		return jwt.NewValidationError("token used before issued", jwt.ValidationErrorIssuedAt)
+	}
+
+	if !claims.VerifyNotBefore(now.Add(leeway), false) {
+		return jwt.NewValidationError("token is not valid yet", jwt.ValidationErrorNotValidYet)
+	}
+
+	return nil
+}
+
+// logger returns the context logger, falling back to the wrapper's Logger.
+func (j *JwtWrapper) logger(ctx context.Context) goctx.Logger {
+	if logger, err := goctx.GetLoggerFromContext(ctx); err == nil {
+		return logger
+	}
+	return j.Logger
+}
+
+// now returns the current time from the wrapper's Clock, if set.
+func (j *JwtWrapper) now() time.Time {
+	if j.Clock != nil {
+		return j.Clock()
+	}
+	return time.Now()
+}
+
+// newTokenID returns a new unique token ID from the wrapper's IDGenerator, if set.
+func (j *JwtWrapper) newTokenID() string {
+	if j.IDGenerator != nil {
+		return j.IDGenerator()
+	}
+	// NewRequestID generates a random UUID v4
+	return goctx.NewRequestID()
+}
+
+// expiration returns the default token lifetime.
+func (j *JwtWrapper) expiration() time.Duration {
+	if j.Expiration != 0 {
+		return j.Expiration
+	}
+	return time.Hour * time.Duration(j.ExpirationHours)
+}
+
+// hmacMethod returns the HMAC variant signing the wrapper's tokens.
+func (j *JwtWrapper) hmacMethod() *jwt.SigningMethodHMAC {
+	if j.SigningMethod != nil {
+		return j.SigningMethod
+	}
+	return jwt.SigningMethodHS256
+}
+
+// usesEdDSA reports whether the wrapper signs and verifies tokens with EdDSA.
+func (j *JwtWrapper) usesEdDSA() bool {
+	return j.EdDSAPublicKey != nil
+}
+
+// verificationKey returns the key verifying the wrapper's tokens.
+func (j *JwtWrapper) verificationKey() interface{} {
+	if j.usesEdDSA() {
+		return j.EdDSAPublicKey
+	}
+	return []byte(j.SecretKey)
+}
+
+// parseToken parses the token, verifying its signature and expiry with key,
+// either an HMAC secret as a []byte or an ed25519.PublicKey.
+func (j *JwtWrapper) parseToken(signedToken string, key interface{}) (*JwtClaim, error) {
+	config := newValidationConfig(j.ValidationOptions...)
+
+	// The time based claims are validated below so leeway and clock apply,
+	// unless claims validation was explicitly disabled through the parser options.
+	parser := jwt.NewParser(config.parserOptions...)
+	validateTimes := !parser.SkipClaimsValidation
+	parser.SkipClaimsValidation = true
+
+	keyFunc := func(token *jwt.Token) (interface{}, error) {
+		// Validate the signing method to prevent algorithm confusion attacks
+		if !signingMethodMatches(token.Method, key) {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		// Reject other JOSE types to prevent confusion with tokens meant for other uses
+		if !config.acceptsType(token.Header["typ"]) {
+			return nil, ErrUnexpectedTokenType
+		}
+		return key, nil
+	}
+
+	var (
+		token *jwt.Token
+		err   error
+	)
+	if isCompressed(signedToken) {
+		token, err = parseCompressed(parser, signedToken, keyFunc, j.maxClaimsSize())
+	} else {
+		token, err = parser.ParseWithClaims(signedToken, &JwtClaim{}, keyFunc)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -88,11 +573,36 @@ func (j *JwtWrapper) ValidateToken(ctx context.Context, signedToken string) (*Jw
 	if !ok {
 		return nil, errors.New("couldn't parse claims")
 	}
+	claims.KeyID, _ = token.Header["kid"].(string)
+
+	now := j.now()
+
+	if validateTimes {
+		if err := validateTimeClaims(claims, now, j.Leeway); err != nil {
+			return nil, err
+		}
+	}
 
-	// Check expiration using the new time handling
-	if claims.ExpiresAt != nil && claims.ExpiresAt.Before(time.Now()) {
-		return nil, errors.New("jwt is expired")
+	if err := config.validate(claims); err != nil {
+		return nil, err
+	}
+
+	// Safety net in case claims validation was disabled through the parser options
+	if claims.ExpiresAt != nil && claims.ExpiresAt.Before(now.Add(-j.Leeway)) {
+		return nil, jwt.NewValidationError("jwt is expired", jwt.ValidationErrorExpired)
 	}
 
 	return claims, nil
-}
\ No newline at end of file
+}
+
+// signingMethodMatches reports whether a token signed with method can be
+// verified with key, so that e.g. an HMAC token is never checked against a
+// public key.
+func signingMethodMatches(method jwt.SigningMethod, key interface{}) bool {
+	if _, ok := key.(ed25519.PublicKey); ok {
+		_, ok := method.(*jwt.SigningMethodEd25519)
+		return ok
+	}
+	_, ok := method.(*jwt.SigningMethodHMAC)
+	return ok
+}
@@ -3,8 +3,12 @@ package auth_test
 import (
 	"context"
 	"errors"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/golang-jwt/jwt/v4"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/junkd0g/go-microservice-commons/auth"
@@ -191,4 +195,384 @@ func Test_ValidateToken(t *testing.T) {
 		assert.Equal(t, "65ff15f55c04488f1005008d", claims.ID)
 		assert.Equal(t, "test@example.com", claims.Email)
 	})
-}
\ No newline at end of file
+}
+func Test_ValidateTokenWithValidateFunc(t *testing.T) {
+	errSuspended := errors.New("tenant is suspended")
+
+	jwtWrapper, err := auth.NewJwtWrapper("some-secret-key", "some-issuer", 1)
+	assert.NoError(t, err)
+
+	jwtWrapper.ValidateFunc = func(ctx context.Context, claims *auth.JwtClaim) error {
+		if claims.Email == "suspended@example.com" {
+			return errSuspended
+		}
+		return nil
+	}
+
+	t.Run("should reject a token refused by the callback", func(t *testing.T) {
+		ctx := context.Background()
+
+		token, err := jwtWrapper.GenerateToken(ctx, "some-uuid", "suspended@example.com")
+		assert.NoError(t, err)
+
+		claims, err := jwtWrapper.ValidateToken(ctx, token)
+		assert.ErrorIs(t, err, errSuspended)
+		assert.Nil(t, claims)
+	})
+
+	t.Run("should accept a token allowed by the callback", func(t *testing.T) {
+		ctx := context.Background()
+
+		token, err := jwtWrapper.GenerateToken(ctx, "some-uuid", "active@example.com")
+		assert.NoError(t, err)
+
+		claims, err := jwtWrapper.ValidateToken(ctx, token)
+		assert.NoError(t, err)
+		assert.Equal(t, "active@example.com", claims.Email)
+	})
+}
+
+func Test_ValidateTokenWithSubjectAllowList(t *testing.T) {
+	ctx := context.Background()
+
+	jwtWrapper, err := auth.NewJwtWrapper("some-secret-key", "some-issuer", 1)
+	assert.NoError(t, err)
+
+	allowed, err := jwtWrapper.GenerateToken(ctx, "allowed-uuid", "some-email")
+	assert.NoError(t, err)
+
+	other, err := jwtWrapper.GenerateToken(ctx, "other-uuid", "some-email")
+	assert.NoError(t, err)
+
+	t.Run("should allow every subject when the allow-list is empty", func(t *testing.T) {
+		claims, err := jwtWrapper.ValidateToken(ctx, other)
+		assert.NoError(t, err)
+		assert.NotNil(t, claims)
+	})
+
+	jwtWrapper.SetSubjectAllowList("allowed-uuid")
+
+	t.Run("should accept a subject in the allow-list", func(t *testing.T) {
+		claims, err := jwtWrapper.ValidateToken(ctx, allowed)
+		assert.NoError(t, err)
+		assert.Equal(t, "allowed-uuid", claims.ID)
+	})
+
+	t.Run("should reject a subject not in the allow-list", func(t *testing.T) {
+		claims, err := jwtWrapper.ValidateToken(ctx, other)
+		assert.ErrorIs(t, err, auth.ErrSubjectNotAllowed)
+		assert.Nil(t, claims)
+	})
+
+	t.Run("should allow every subject again once the allow-list is cleared", func(t *testing.T) {
+		jwtWrapper.SetSubjectAllowList()
+		assert.Nil(t, jwtWrapper.SubjectAllowList())
+
+		claims, err := jwtWrapper.ValidateToken(ctx, other)
+		assert.NoError(t, err)
+		assert.NotNil(t, claims)
+	})
+
+	t.Run("should be safe to replace while validating", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				_, _ = jwtWrapper.ValidateToken(ctx, allowed)
+			}()
+			go func() {
+				defer wg.Done()
+				jwtWrapper.SetSubjectAllowList("allowed-uuid")
+			}()
+		}
+		wg.Wait()
+	})
+}
+
+func Test_ValidateTokenMultiKey(t *testing.T) {
+	ctx := context.Background()
+
+	oldWrapper, err := auth.NewJwtWrapper("old-secret-key", "some-issuer", 1)
+	assert.NoError(t, err)
+	newWrapper, err := auth.NewJwtWrapper("new-secret-key", "some-issuer", 1)
+	assert.NoError(t, err)
+
+	oldToken, err := oldWrapper.GenerateToken(ctx, "old-uuid", "some-email")
+	assert.NoError(t, err)
+	newToken, err := newWrapper.GenerateToken(ctx, "new-uuid", "some-email")
+	assert.NoError(t, err)
+
+	keys := [][]byte{[]byte("new-secret-key"), []byte("old-secret-key")}
+
+	t.Run("should validate a token signed with the new key", func(t *testing.T) {
+		claims, err := newWrapper.ValidateTokenMultiKey(ctx, newToken, keys...)
+		assert.NoError(t, err)
+		assert.Equal(t, "new-uuid", claims.ID)
+	})
+
+	t.Run("should validate a token signed with the old key", func(t *testing.T) {
+		claims, err := newWrapper.ValidateTokenMultiKey(ctx, oldToken, keys...)
+		assert.NoError(t, err)
+		assert.Equal(t, "old-uuid", claims.ID)
+	})
+
+	t.Run("should fail when no key matches", func(t *testing.T) {
+		claims, err := newWrapper.ValidateTokenMultiKey(ctx, oldToken, []byte("other-secret-key"))
+		assert.Error(t, err)
+		assert.Nil(t, claims)
+	})
+
+	t.Run("should fail without keys", func(t *testing.T) {
+		claims, err := newWrapper.ValidateTokenMultiKey(ctx, newToken)
+		assert.Error(t, err)
+		assert.Nil(t, claims)
+	})
+
+	t.Run("should reject non-HMAC tokens for every key", func(t *testing.T) {
+		// {"alg":"none","typ":"JWT"}.{"ID":"some-uuid"}.
+		noneToken := "eyJhbGciOiJub25lIiwidHlwIjoiSldUIn0.eyJJRCI6InNvbWUtdXVpZCJ9."
+
+		claims, err := newWrapper.ValidateTokenMultiKey(ctx, noneToken, keys...)
+		assert.Error(t, err)
+		assert.Nil(t, claims)
+	})
+}
+
+func Test_GenerateTokenWithTTL(t *testing.T) {
+	ctx := context.Background()
+
+	jwtWrapper, err := auth.NewJwtWrapper("some-secret-key", "some-issuer", 24)
+	assert.NoError(t, err)
+
+	t.Run("should override the default expiration", func(t *testing.T) {
+		token, err := jwtWrapper.GenerateTokenWithTTL(ctx, "some-uuid", "some-email", 15*time.Minute)
+		assert.NoError(t, err)
+
+		claims, err := jwtWrapper.ValidateToken(ctx, token)
+		assert.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(15*time.Minute), claims.ExpiresAt.Time, 2*time.Second)
+	})
+
+	t.Run("should keep the default expiration for GenerateToken", func(t *testing.T) {
+		token, err := jwtWrapper.GenerateToken(ctx, "some-uuid", "some-email")
+		assert.NoError(t, err)
+
+		claims, err := jwtWrapper.ValidateToken(ctx, token)
+		assert.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(24*time.Hour), claims.ExpiresAt.Time, 2*time.Second)
+	})
+
+	t.Run("should fail with a non-positive ttl", func(t *testing.T) {
+		token, err := jwtWrapper.GenerateTokenWithTTL(ctx, "some-uuid", "some-email", 0)
+		assert.EqualError(t, err, "ttl must be greater than 0")
+		assert.Empty(t, token)
+	})
+}
+
+func Test_Fingerprint(t *testing.T) {
+	ctx := context.Background()
+
+	jwtWrapper, err := auth.NewJwtWrapper("some-secret-key", "some-issuer", 1)
+	assert.NoError(t, err)
+
+	bound, err := jwtWrapper.GenerateTokenWithFingerprint(ctx, "some-uuid", "some-email", "device-hash")
+	assert.NoError(t, err)
+
+	unbound, err := jwtWrapper.GenerateToken(ctx, "some-uuid", "some-email")
+	assert.NoError(t, err)
+
+	t.Run("should accept a matching fingerprint", func(t *testing.T) {
+		claims, err := jwtWrapper.ValidateTokenWithFingerprint(ctx, bound, "device-hash")
+		assert.NoError(t, err)
+		assert.Equal(t, "device-hash", claims.Fingerprint)
+	})
+
+	t.Run("should reject a mismatching fingerprint", func(t *testing.T) {
+		claims, err := jwtWrapper.ValidateTokenWithFingerprint(ctx, bound, "other-device-hash")
+		assert.ErrorIs(t, err, auth.ErrFingerprintMismatch)
+		assert.Nil(t, claims)
+	})
+
+	t.Run("should reject an unbound token when a fingerprint is expected", func(t *testing.T) {
+		claims, err := jwtWrapper.ValidateTokenWithFingerprint(ctx, unbound, "device-hash")
+		assert.ErrorIs(t, err, auth.ErrFingerprintMismatch)
+		assert.Nil(t, claims)
+	})
+
+	t.Run("should behave like ValidateToken without an expected fingerprint", func(t *testing.T) {
+		claims, err := jwtWrapper.ValidateTokenWithFingerprint(ctx, unbound, "")
+		assert.NoError(t, err)
+		assert.Empty(t, claims.Fingerprint)
+
+		claims, err = jwtWrapper.ValidateToken(ctx, bound)
+		assert.NoError(t, err)
+		assert.NotNil(t, claims)
+	})
+
+	t.Run("should fail to generate without a fingerprint", func(t *testing.T) {
+		token, err := jwtWrapper.GenerateTokenWithFingerprint(ctx, "some-uuid", "some-email", "")
+		assert.Error(t, err)
+		assert.Empty(t, token)
+	})
+}
+
+func Test_ValidateTokenWhitespace(t *testing.T) {
+	ctx := context.Background()
+
+	jwtWrapper, err := auth.NewJwtWrapper("some-secret-key", "some-issuer", 1)
+	assert.NoError(t, err)
+
+	token, err := jwtWrapper.GenerateToken(ctx, "some-uuid", "some-email")
+	assert.NoError(t, err)
+
+	t.Run("should accept a token wrapped in spaces", func(t *testing.T) {
+		claims, err := jwtWrapper.ValidateToken(ctx, "  "+token+"  ")
+		assert.NoError(t, err)
+		assert.Equal(t, "some-uuid", claims.ID)
+	})
+
+	t.Run("should accept a token with a trailing newline", func(t *testing.T) {
+		claims, err := jwtWrapper.ValidateToken(ctx, token+"\n")
+		assert.NoError(t, err)
+		assert.Equal(t, "some-uuid", claims.ID)
+	})
+
+	t.Run("should reject a token made of whitespace only", func(t *testing.T) {
+		claims, err := jwtWrapper.ValidateToken(ctx, " \t\n")
+		assert.ErrorIs(t, err, auth.ErrMalformedToken)
+		assert.Nil(t, claims)
+
+		claims, err = jwtWrapper.ValidateTokenMultiKey(ctx, "", []byte("some-secret-key"))
+		assert.ErrorIs(t, err, auth.ErrMalformedToken)
+		assert.Nil(t, claims)
+	})
+}
+
+func Test_MaxTokenSize(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should reject a multi-megabyte token before parsing", func(t *testing.T) {
+		jwtWrapper, err := auth.NewJwtWrapper("some-secret-key", "some-issuer", 1)
+		assert.NoError(t, err)
+
+		huge := strings.Repeat("a", 4<<20) + "." + strings.Repeat("b", 4<<20) + ".c"
+
+		claims, err := jwtWrapper.ValidateToken(ctx, huge)
+		assert.ErrorIs(t, err, auth.ErrTokenTooLarge)
+		assert.Nil(t, claims)
+
+		_, err = jwtWrapper.ValidateTokenMultiKey(ctx, huge, []byte("some-secret-key"))
+		assert.ErrorIs(t, err, auth.ErrTokenTooLarge)
+	})
+
+	t.Run("should apply a configured limit", func(t *testing.T) {
+		jwtWrapper, err := auth.NewJwtWrapper("some-secret-key", "some-issuer", 1)
+		assert.NoError(t, err)
+
+		token, err := jwtWrapper.GenerateToken(ctx, "some-uuid", "some-email")
+		assert.NoError(t, err)
+
+		limited, err := auth.New(
+			auth.WithSecret("some-secret-key"),
+			auth.WithIssuer("some-issuer"),
+			auth.WithExpirationHours(1),
+			auth.WithMaxTokenSize(len(token)-1),
+		)
+		assert.NoError(t, err)
+
+		_, err = limited.ValidateToken(ctx, token)
+		assert.ErrorIs(t, err, auth.ErrTokenTooLarge)
+	})
+}
+
+func Test_KeyID(t *testing.T) {
+	ctx := context.Background()
+
+	jwtWrapper, err := auth.NewJwtWrapper("some-secret-key", "some-issuer", 1)
+	assert.NoError(t, err)
+
+	t.Run("should expose the kid header of the token", func(t *testing.T) {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, &auth.JwtClaim{ID: "some-uuid"})
+		token.Header["kid"] = "key-2024"
+		signedToken, err := token.SignedString([]byte("some-secret-key"))
+		assert.NoError(t, err)
+
+		claims, err := jwtWrapper.ValidateToken(ctx, signedToken)
+		assert.NoError(t, err)
+		assert.Equal(t, "key-2024", claims.KeyID)
+	})
+
+	t.Run("should leave the key ID empty without a kid header", func(t *testing.T) {
+		signedToken, err := jwtWrapper.GenerateToken(ctx, "some-uuid", "some-email")
+		assert.NoError(t, err)
+
+		claims, err := jwtWrapper.ValidateToken(ctx, signedToken)
+		assert.NoError(t, err)
+		assert.Empty(t, claims.KeyID)
+	})
+}
+
+func Test_SigningMethod(t *testing.T) {
+	ctx := context.Background()
+	secret := strings.Repeat("s", 64)
+
+	for _, method := range []*jwt.SigningMethodHMAC{jwt.SigningMethodHS256, jwt.SigningMethodHS384, jwt.SigningMethodHS512} {
+		t.Run("should round-trip "+method.Alg(), func(t *testing.T) {
+			jwtWrapper, err := auth.New(
+				auth.WithSecret(secret),
+				auth.WithSigningMethod(method),
+				auth.WithIssuer("some-issuer"),
+				auth.WithExpirationHours(1),
+			)
+			assert.NoError(t, err)
+
+			signedToken, err := jwtWrapper.GenerateToken(ctx, "some-uuid", "some-email")
+			assert.NoError(t, err)
+
+			token, _, err := jwt.NewParser().ParseUnverified(signedToken, &auth.JwtClaim{})
+			assert.NoError(t, err)
+			assert.Equal(t, method.Alg(), token.Header["alg"])
+
+			claims, err := jwtWrapper.ValidateToken(ctx, signedToken)
+			assert.NoError(t, err)
+			assert.Equal(t, "some-uuid", claims.ID)
+		})
+	}
+
+	t.Run("should default to HS256 and accept the other HMAC variants", func(t *testing.T) {
+		hs512Wrapper, err := auth.New(auth.WithSecret(secret), auth.WithSigningMethod(jwt.SigningMethodHS512), auth.WithIssuer("some-issuer"), auth.WithExpirationHours(1))
+		assert.NoError(t, err)
+		hs256Wrapper, err := auth.NewJwtWrapper(secret, "some-issuer", 1)
+		assert.NoError(t, err)
+
+		hs256Token, err := hs256Wrapper.GenerateToken(ctx, "some-uuid", "some-email")
+		assert.NoError(t, err)
+		token, _, err := jwt.NewParser().ParseUnverified(hs256Token, &auth.JwtClaim{})
+		assert.NoError(t, err)
+		assert.Equal(t, "HS256", token.Header["alg"])
+
+		_, err = hs512Wrapper.ValidateToken(ctx, hs256Token)
+		assert.NoError(t, err)
+	})
+
+	t.Run("should reject a secret shorter than the hash", func(t *testing.T) {
+		_, err := auth.New(
+			auth.WithSecret(strings.Repeat("s", 48)),
+			auth.WithSigningMethod(jwt.SigningMethodHS512),
+			auth.WithIssuer("some-issuer"),
+			auth.WithExpirationHours(1),
+		)
+		assert.EqualError(t, err, "secret is 48 bytes, HS512 requires at least 64 bytes")
+	})
+
+	t.Run("should still reject non-HMAC tokens", func(t *testing.T) {
+		jwtWrapper, err := auth.New(auth.WithSecret(secret), auth.WithSigningMethod(jwt.SigningMethodHS384), auth.WithIssuer("some-issuer"), auth.WithExpirationHours(1))
+		assert.NoError(t, err)
+
+		// {"alg":"none","typ":"JWT"}.{"ID":"some-uuid"}.
+		noneToken := "eyJhbGciOiJub25lIiwidHlwIjoiSldUIn0.eyJJRCI6InNvbWUtdXVpZCJ9."
+		_, err = jwtWrapper.ValidateToken(ctx, noneToken)
+		assert.Error(t, err)
+	})
+}
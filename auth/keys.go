@@ -0,0 +1,37 @@
+package auth
+
+import "context"
+
+// KeyProvider supplies the HMAC keys of a JwtWrapper, e.g. from a vault, so
+// keys can be rotated while the wrapper is running. It is consulted on every
+// generation and validation and should therefore cache its keys.
+type KeyProvider interface {
+	// SigningKey returns the key signing new tokens.
+	SigningKey(ctx context.Context) ([]byte, error)
+	// VerificationKeys returns the keys accepted when validating tokens, tried
+	// in order. During a rotation it typically returns the current signing key
+	// followed by the previous one.
+	VerificationKeys(ctx context.Context) ([][]byte, error)
+}
+
+// StaticKeyProvider is a KeyProvider always returning the same secret, for
+// code written against KeyProvider when the secret never changes.
+type StaticKeyProvider struct {
+	key []byte
+}
+
+// NewStaticKeyProvider initializes a new instance of StaticKeyProvider
+// signing and verifying tokens with the given secret.
+func NewStaticKeyProvider(secretKey string) *StaticKeyProvider {
+	return &StaticKeyProvider{key: []byte(secretKey)}
+}
+
+// SigningKey returns the secret.
+func (p *StaticKeyProvider) SigningKey(ctx context.Context) ([]byte, error) {
+	return p.key, nil
+}
+
+// VerificationKeys returns the secret as the only verification key.
+func (p *StaticKeyProvider) VerificationKeys(ctx context.Context) ([][]byte, error) {
+	return [][]byte{p.key}, nil
+}
@@ -0,0 +1,118 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/junkd0g/go-microservice-commons/auth"
+)
+
+// rotatingKeyProvider is a KeyProvider whose keys can be rotated by tests.
+type rotatingKeyProvider struct {
+	sync.Mutex
+	current, previous []byte
+	err               error
+}
+
+func (p *rotatingKeyProvider) rotate(key string) {
+	p.Lock()
+	defer p.Unlock()
+	p.previous, p.current = p.current, []byte(key)
+}
+
+func (p *rotatingKeyProvider) SigningKey(ctx context.Context) ([]byte, error) {
+	p.Lock()
+	defer p.Unlock()
+	return p.current, p.err
+}
+
+func (p *rotatingKeyProvider) VerificationKeys(ctx context.Context) ([][]byte, error) {
+	p.Lock()
+	defer p.Unlock()
+	keys := [][]byte{p.current}
+	if p.previous != nil {
+		keys = append(keys, p.previous)
+	}
+	return keys, p.err
+}
+
+func Test_KeyProvider(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should rotate keys without rebuilding the wrapper", func(t *testing.T) {
+		provider := &rotatingKeyProvider{current: []byte("first-secret-key")}
+		jwtWrapper, err := auth.New(auth.WithKeyProvider(provider), auth.WithIssuer("some-issuer"), auth.WithExpirationHours(1))
+		assert.NoError(t, err)
+
+		firstToken, err := jwtWrapper.GenerateToken(ctx, "some-uuid", "some-email")
+		assert.NoError(t, err)
+
+		provider.rotate("second-secret-key")
+		secondToken, err := jwtWrapper.GenerateToken(ctx, "some-uuid", "some-email")
+		assert.NoError(t, err)
+
+		// The second token is signed with the new key only
+		_, err = jwtWrapper.ValidateTokenMultiKey(ctx, secondToken, []byte("first-secret-key"))
+		assert.Error(t, err)
+
+		// Tokens signed with the previous key are accepted during the overlap
+		for _, token := range []string{firstToken, secondToken} {
+			claims, err := jwtWrapper.ValidateToken(ctx, token)
+			assert.NoError(t, err)
+			assert.Equal(t, "some-uuid", claims.ID)
+		}
+
+		provider.rotate("third-secret-key")
+		_, err = jwtWrapper.ValidateToken(ctx, firstToken)
+		assert.Error(t, err)
+	})
+
+	t.Run("should return the provider errors", func(t *testing.T) {
+		errVault := errors.New("vault unavailable")
+		provider := &rotatingKeyProvider{current: []byte("first-secret-key")}
+		jwtWrapper, err := auth.New(auth.WithKeyProvider(provider), auth.WithIssuer("some-issuer"), auth.WithExpirationHours(1))
+		assert.NoError(t, err)
+		token, err := jwtWrapper.GenerateToken(ctx, "some-uuid", "some-email")
+		assert.NoError(t, err)
+
+		provider.err = errVault
+
+		_, err = jwtWrapper.GenerateToken(ctx, "some-uuid", "some-email")
+		assert.ErrorIs(t, err, errVault)
+		claims, err := jwtWrapper.ValidateToken(ctx, token)
+		assert.ErrorIs(t, err, errVault)
+		assert.Nil(t, claims)
+	})
+
+	t.Run("should accept tokens of the secret with a static provider", func(t *testing.T) {
+		jwtWrapper, err := auth.NewJwtWrapper("some-secret-key", "some-issuer", 1)
+		assert.NoError(t, err)
+		token, err := jwtWrapper.GenerateToken(ctx, "some-uuid", "some-email")
+		assert.NoError(t, err)
+
+		providerWrapper, err := auth.New(
+			auth.WithKeyProvider(auth.NewStaticKeyProvider("some-secret-key")),
+			auth.WithIssuer("some-issuer"),
+			auth.WithExpirationHours(1),
+		)
+		assert.NoError(t, err)
+
+		claims, err := providerWrapper.ValidateToken(ctx, token)
+		assert.NoError(t, err)
+		assert.Equal(t, "some-uuid", claims.ID)
+	})
+
+	t.Run("should reject a provider combined with EdDSA keys", func(t *testing.T) {
+		_, err := auth.New(
+			auth.WithKeyProvider(auth.NewStaticKeyProvider("some-secret-key")),
+			auth.WithEdDSAKeys(nil, make([]byte, 32)),
+			auth.WithIssuer("some-issuer"),
+			auth.WithExpirationHours(1),
+		)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,251 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+)
+
+const (
+	// DefaultTokenHeader is the header the middleware reads the token from by default.
+	DefaultTokenHeader = "Authorization"
+
+	// BearerScheme is the authorization scheme expected in the default header.
+	BearerScheme = "Bearer"
+
+	// HeaderWWWAuthenticate is the header challenging the clients rejected by
+	// the middleware, see BearerChallenge.
+	HeaderWWWAuthenticate = "WWW-Authenticate"
+
+	// HeaderForwardedProto is the header set by proxies terminating TLS to the
+	// protocol of the original request.
+	HeaderForwardedProto = "X-Forwarded-Proto"
+)
+
+// MiddlewareOption configures the authentication middleware.
+type MiddlewareOption func(*middlewareConfig)
+
+// middlewareConfig holds the settings collected from MiddlewareOptions.
+type middlewareConfig struct {
+	header              string
+	scheme              string
+	requireTLS          bool
+	optional            bool
+	trustForwardedProto bool
+	audit               bool
+	auditClaimKeys      []string
+}
+
+// WithTokenHeader sets the header the token is read from and the scheme that
+// prefixes it. An empty scheme means the whole header value is the raw token,
+// e.g. WithTokenHeader("X-Auth-Token", "") for clients and proxies forwarding
+// the token in a custom header.
+func WithTokenHeader(header, scheme string) MiddlewareOption {
+	return func(c *middlewareConfig) {
+		c.header = header
+		c.scheme = scheme
+	}
+}
+
+// WithRequireTLS makes the middleware reject requests that did not arrive over
+// HTTPS with 403 Forbidden before reading the token, preventing tokens from
+// being accepted over cleartext. When trustForwardedProto is set, a request is
+// also considered secure when the X-Forwarded-Proto header is "https"; only
+// enable it behind a proxy that sets the header.
+func WithRequireTLS(trustForwardedProto bool) MiddlewareOption {
+	return func(c *middlewareConfig) {
+		c.requireTLS = true
+		c.trustForwardedProto = trustForwardedProto
+	}
+}
+
+// WithOptionalAuth lets requests without a token through unauthenticated, with
+// no claims in their context, instead of rejecting them with 401 Unauthorized.
+// Tokens that are present are still validated, and rejected when invalid. Use
+// IsAuthenticated in handlers to tell authenticated requests apart.
+func WithOptionalAuth() MiddlewareOption {
+	return func(c *middlewareConfig) {
+		c.optional = true
+	}
+}
+
+// defaultAuditClaimKeys are the claims logged by WithAuditLog when none are given.
+var defaultAuditClaimKeys = []string{ClaimKeyID, ClaimKeyEmail, ClaimKeyIssuer, ClaimKeyExpiresAt}
+
+// WithAuditLog makes the middleware keep an audit trail of authentications
+// through the context logger: successful validations are logged at info level
+// as "token validated" with the given claims (see the ClaimKey constants),
+// defaulting to the ID, email, issuer and expiry, and rejected tokens as
+// "token validation failed" warnings with the reason. The token itself is
+// never logged.
+func WithAuditLog(claimKeys ...string) MiddlewareOption {
+	return func(c *middlewareConfig) {
+		c.audit = true
+		c.auditClaimKeys = claimKeys
+		if len(claimKeys) == 0 {
+			c.auditClaimKeys = defaultAuditClaimKeys
+		}
+	}
+}
+
+// auditSuccess logs a successful validation when auditing is enabled.
+func (c *middlewareConfig) auditSuccess(ctx context.Context, claims *JwtClaim) {
+	if !c.audit {
+		return
+	}
+	if logger, err := goctx.GetLoggerFromContext(ctx); err == nil {
+		logger.Info(ctx, "token validated", claims.SafeMap(c.auditClaimKeys...))
+	}
+}
+
+// auditFailure logs a rejected token when auditing is enabled.
+func (c *middlewareConfig) auditFailure(ctx context.Context, reason error) {
+	if !c.audit {
+		return
+	}
+	if logger, err := goctx.GetLoggerFromContext(ctx); err == nil {
+		goctx.Warn(ctx, logger, "token validation failed", map[string]interface{}{
+			"reason": reason.Error(),
+		})
+	}
+}
+
+// isSecure reports whether the request arrived over HTTPS.
+func isSecure(r *http.Request, trustForwardedProto bool) bool {
+	if r.TLS != nil {
+		return true
+	}
+	if !trustForwardedProto {
+		return false
+	}
+	// Proxies chaining the header append to it, the first value is the client's
+	proto, _, _ := strings.Cut(r.Header.Get(HeaderForwardedProto), ",")
+	return strings.EqualFold(strings.TrimSpace(proto), "https")
+}
+
+// ExtractToken reads the token from the given request header. When scheme is
+// set, the header value must be "<scheme> <token>" (the scheme is matched
+// case-insensitively); when scheme is empty, the whole value is the token.
+func ExtractToken(r *http.Request, header, scheme string) (string, error) {
+	value := strings.TrimSpace(r.Header.Get(header))
+	if value == "" {
+		return "", ErrMissingToken
+	}
+
+	if scheme == "" {
+		return value, nil
+	}
+
+	prefix, token, found := strings.Cut(value, " ")
+	if !found || !strings.EqualFold(prefix, scheme) {
+		return "", ErrInvalidAuthHeader
+	}
+
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return "", ErrMissingToken
+	}
+
+	return token, nil
+}
+
+// Middleware validates the token of every request with the given validator and
+// stores the claims in the request context, retrievable with ClaimsFromContext.
+// Requests without a valid token are rejected with 401 Unauthorized, or 400
+// Bad Request when the header does not use the expected scheme, and a
+// WWW-Authenticate challenge (see Challenge), unless WithOptionalAuth lets
+// those without a token through.
+// By default the token is read from the Authorization header using the Bearer
+// scheme, and plaintext requests are accepted unless WithRequireTLS is used.
+func Middleware(validator Validator, opts ...MiddlewareOption) func(http.Handler) http.Handler {
+	config := &middlewareConfig{
+		header: DefaultTokenHeader,
+		scheme: BearerScheme,
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if config.requireTLS && !isSecure(r, config.trustForwardedProto) {
+				http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+				return
+			}
+
+			token, err := ExtractToken(r, config.header, config.scheme)
+			if errors.Is(err, ErrMissingToken) && config.optional {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if err != nil {
+				config.auditFailure(r.Context(), err)
+				reject(w, config.scheme, err)
+				return
+			}
+
+			ctx, claims, err := Authenticate(r.Context(), validator, token)
+			if err != nil {
+				config.auditFailure(r.Context(), err)
+				reject(w, config.scheme, err)
+				return
+			}
+			config.auditSuccess(ctx, claims)
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// BearerChallenge returns the WWW-Authenticate challenge answering a request
+// rejected with err, as defined by RFC 6750: a bare "Bearer" when the token is
+// missing, with error="invalid_request" when the header is malformed,
+// error="expired_token" when the token is expired and error="invalid_token"
+// otherwise. The descriptions are fixed so no validation details leak.
+func BearerChallenge(err error) string {
+	return Challenge(BearerScheme, err)
+}
+
+// Challenge behaves like BearerChallenge for the given authorization scheme,
+// e.g. the one set with WithTokenHeader. An empty scheme, used for raw tokens,
+// falls back to Bearer.
+func Challenge(scheme string, err error) string {
+	if scheme == "" {
+		scheme = BearerScheme
+	}
+
+	switch {
+	case errors.Is(err, ErrMissingToken):
+		return scheme
+	case errors.Is(err, ErrInvalidAuthHeader):
+		return challenge(scheme, "invalid_request", "the authorization header is malformed")
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return challenge(scheme, "expired_token", "the token is expired")
+	default:
+		return challenge(scheme, "invalid_token", "the token is invalid")
+	}
+}
+
+// challenge formats a challenge of the scheme with the error code and description.
+func challenge(scheme, code, description string) string {
+	return fmt.Sprintf(`%s error=%q, error_description=%q`, scheme, code, description)
+}
+
+// reject answers the request rejected with err with the matching
+// WWW-Authenticate challenge, and 400 Bad Request for a malformed header, as
+// RFC 6750 requires for invalid_request, or 401 Unauthorized otherwise.
+func reject(w http.ResponseWriter, scheme string, err error) {
+	status := http.StatusUnauthorized
+	if errors.Is(err, ErrInvalidAuthHeader) {
+		status = http.StatusBadRequest
+	}
+
+	w.Header().Set(HeaderWWWAuthenticate, Challenge(scheme, err))
+	http.Error(w, http.StatusText(status), status)
+}
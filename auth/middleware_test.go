@@ -0,0 +1,426 @@
+package auth_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/junkd0g/go-microservice-commons/auth"
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+	"github.com/junkd0g/go-microservice-commons/logger"
+)
+
+func Test_ExtractToken(t *testing.T) {
+	type testCase struct {
+		name          string
+		header        string
+		scheme        string
+		headers       map[string]string
+		expectedToken string
+		expectedError error
+	}
+
+	testCases := []testCase{
+		{
+			name:          "bearer token in authorization header",
+			header:        "Authorization",
+			scheme:        "Bearer",
+			headers:       map[string]string{"Authorization": "Bearer some-token"},
+			expectedToken: "some-token",
+		},
+		{
+			name:          "scheme is matched case-insensitively",
+			header:        "Authorization",
+			scheme:        "Bearer",
+			headers:       map[string]string{"Authorization": "bearer some-token"},
+			expectedToken: "some-token",
+		},
+		{
+			name:          "missing header",
+			header:        "Authorization",
+			scheme:        "Bearer",
+			expectedError: auth.ErrMissingToken,
+		},
+		{
+			name:          "wrong scheme",
+			header:        "Authorization",
+			scheme:        "Bearer",
+			headers:       map[string]string{"Authorization": "Basic some-token"},
+			expectedError: auth.ErrInvalidAuthHeader,
+		},
+		{
+			name:          "scheme without token",
+			header:        "Authorization",
+			scheme:        "Bearer",
+			headers:       map[string]string{"Authorization": "Bearer "},
+			expectedError: auth.ErrInvalidAuthHeader,
+		},
+		{
+			name:          "raw token in custom header",
+			header:        "X-Auth-Token",
+			headers:       map[string]string{"X-Auth-Token": "some-token"},
+			expectedToken: "some-token",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+
+			token, err := auth.ExtractToken(req, tc.header, tc.scheme)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				assert.Empty(t, token)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedToken, token)
+			}
+		})
+	}
+}
+
+func Test_Middleware(t *testing.T) {
+	ctx := context.Background()
+
+	jwtWrapper, err := auth.NewJwtWrapper("some-secret-key", "some-issuer", 1)
+	assert.NoError(t, err)
+
+	token, err := jwtWrapper.GenerateToken(ctx, "some-uuid", "some-email")
+	assert.NoError(t, err)
+
+	var gotID string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, err := auth.ClaimsFromContext(r.Context())
+		assert.NoError(t, err)
+		gotID = claims.ID
+	})
+
+	t.Run("should authenticate a bearer token from the authorization header", func(t *testing.T) {
+		gotID = ""
+		handler := auth.Middleware(jwtWrapper)(next)
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "some-uuid", gotID)
+	})
+
+	t.Run("should authenticate a raw token from a custom header", func(t *testing.T) {
+		gotID = ""
+		handler := auth.Middleware(jwtWrapper, auth.WithTokenHeader("X-Auth-Token", ""))(next)
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Auth-Token", token)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "some-uuid", gotID)
+	})
+
+	t.Run("should ignore the authorization header when a custom header is configured", func(t *testing.T) {
+		handler := auth.Middleware(jwtWrapper, auth.WithTokenHeader("X-Auth-Token", ""))(next)
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("should reject an invalid token", func(t *testing.T) {
+		handler := auth.Middleware(jwtWrapper)(next)
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Bearer invalid-token")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+}
+
+func Test_MiddlewareWithOptionalAuth(t *testing.T) {
+	ctx := context.Background()
+
+	jwtWrapper, err := auth.NewJwtWrapper("some-secret-key", "some-issuer", 1)
+	assert.NoError(t, err)
+
+	token, err := jwtWrapper.GenerateToken(ctx, "some-uuid", "some-email")
+	assert.NoError(t, err)
+
+	var called, authenticated bool
+	handler := auth.Middleware(jwtWrapper, auth.WithOptionalAuth())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		authenticated = auth.IsAuthenticated(r.Context())
+	}))
+
+	testCases := []struct {
+		name                  string
+		authorization         string
+		expectedStatus        int
+		expectedCalled        bool
+		expectedAuthenticated bool
+	}{
+		{
+			name:                  "should authenticate a valid token",
+			authorization:         "Bearer " + token,
+			expectedStatus:        http.StatusOK,
+			expectedCalled:        true,
+			expectedAuthenticated: true,
+		},
+		{
+			name:           "should let a request without a token through unauthenticated",
+			expectedStatus: http.StatusOK,
+			expectedCalled: true,
+		},
+		{
+			name:           "should reject an invalid token",
+			authorization:  "Bearer invalid-token",
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			called, authenticated = false, false
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.authorization != "" {
+				req.Header.Set("Authorization", tc.authorization)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assert.Equal(t, tc.expectedCalled, called)
+			assert.Equal(t, tc.expectedAuthenticated, authenticated)
+		})
+	}
+}
+
+func Test_MiddlewareWWWAuthenticate(t *testing.T) {
+	ctx := context.Background()
+
+	now := time.Now()
+	jwtWrapper, err := auth.New(
+		auth.WithSecret("some-secret-key"),
+		auth.WithIssuer("some-issuer"),
+		auth.WithExpiration(time.Hour),
+		auth.WithClock(func() time.Time { return now }),
+	)
+	assert.NoError(t, err)
+
+	expired, err := jwtWrapper.GenerateTokenWithTTL(ctx, "some-uuid", "some-email", time.Minute)
+	assert.NoError(t, err)
+	now = now.Add(2 * time.Minute)
+
+	handler := auth.Middleware(jwtWrapper)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	testCases := []struct {
+		name           string
+		authorization  string
+		expectedStatus int
+		expected       string
+	}{
+		{
+			name:           "missing token",
+			expectedStatus: http.StatusUnauthorized,
+			expected:       `Bearer`,
+		},
+		{
+			name:           "malformed header",
+			authorization:  "Basic c29tZTp1c2Vy",
+			expectedStatus: http.StatusBadRequest,
+			expected:       `Bearer error="invalid_request", error_description="the authorization header is malformed"`,
+		},
+		{
+			name:           "invalid token",
+			authorization:  "Bearer invalid-token",
+			expectedStatus: http.StatusUnauthorized,
+			expected:       `Bearer error="invalid_token", error_description="the token is invalid"`,
+		},
+		{
+			name:           "expired token",
+			authorization:  "Bearer " + expired,
+			expectedStatus: http.StatusUnauthorized,
+			expected:       `Bearer error="expired_token", error_description="the token is expired"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.authorization != "" {
+				req.Header.Set("Authorization", tc.authorization)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assert.Equal(t, tc.expected, rec.Header().Get(auth.HeaderWWWAuthenticate))
+		})
+	}
+
+	t.Run("custom scheme", func(t *testing.T) {
+		handler := auth.Middleware(jwtWrapper, auth.WithTokenHeader("Authorization", "Token"))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Token invalid-token")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Equal(t, `Token error="invalid_token", error_description="the token is invalid"`, rec.Header().Get(auth.HeaderWWWAuthenticate))
+	})
+}
+
+func Test_MiddlewareWithRequireTLS(t *testing.T) {
+	ctx := context.Background()
+
+	jwtWrapper, err := auth.NewJwtWrapper("some-secret-key", "some-issuer", 1)
+	assert.NoError(t, err)
+
+	token, err := jwtWrapper.GenerateToken(ctx, "some-uuid", "some-email")
+	assert.NoError(t, err)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	type testCase struct {
+		name                string
+		target              string
+		forwardedProto      string
+		trustForwardedProto bool
+		expectedStatus      int
+	}
+
+	testCases := []testCase{
+		{
+			name:           "direct TLS",
+			target:         "https://example.com/",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:                "forwarded HTTPS from a trusted proxy",
+			target:              "/",
+			forwardedProto:      "https",
+			trustForwardedProto: true,
+			expectedStatus:      http.StatusOK,
+		},
+		{
+			name:           "forwarded HTTPS without trusting the proxy",
+			target:         "/",
+			forwardedProto: "https",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:                "forwarded plaintext",
+			target:              "/",
+			forwardedProto:      "http, https",
+			trustForwardedProto: true,
+			expectedStatus:      http.StatusForbidden,
+		},
+		{
+			name:           "plaintext",
+			target:         "/",
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := auth.Middleware(jwtWrapper, auth.WithRequireTLS(tc.trustForwardedProto))(next)
+
+			req := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			if tc.forwardedProto != "" {
+				req.Header.Set(auth.HeaderForwardedProto, tc.forwardedProto)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func Test_MiddlewareWithAuditLog(t *testing.T) {
+	ctx := context.Background()
+
+	jwtWrapper, err := auth.NewJwtWrapper("some-secret-key", "some-issuer", 1)
+	assert.NoError(t, err)
+
+	token, err := jwtWrapper.GenerateToken(ctx, "some-uuid", "some-email")
+	assert.NoError(t, err)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	serve := func(t *testing.T, token string, opts ...auth.MiddlewareOption) []observer.LoggedEntry {
+		log, err := logger.NewLogger()
+		assert.NoError(t, err)
+		core, recorded := observer.New(zapcore.InfoLevel)
+		log.SetCore(core)
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req = req.WithContext(goctx.AddLoggerToContex(req.Context(), log))
+		req.Header.Set("Authorization", "Bearer "+token)
+		auth.Middleware(jwtWrapper, opts...)(next).ServeHTTP(httptest.NewRecorder(), req)
+
+		for _, entry := range recorded.All() {
+			for _, value := range entry.ContextMap() {
+				assert.NotContains(t, value, token, "the raw token must never be logged")
+			}
+		}
+		return recorded.All()
+	}
+
+	t.Run("should log successful validations with the default claims", func(t *testing.T) {
+		entries := serve(t, token, auth.WithAuditLog())
+
+		assert.Len(t, entries, 1)
+		assert.Equal(t, zapcore.InfoLevel, entries[0].Level)
+		assert.Equal(t, "token validated", entries[0].Message)
+
+		fields := entries[0].ContextMap()
+		assert.Equal(t, "some-uuid", fields[auth.ClaimKeyID])
+		assert.Equal(t, "some-email", fields[auth.ClaimKeyEmail])
+		assert.Equal(t, "some-issuer", fields[auth.ClaimKeyIssuer])
+		assert.Contains(t, fields, auth.ClaimKeyExpiresAt)
+	})
+
+	t.Run("should log only the configured claims", func(t *testing.T) {
+		entries := serve(t, token, auth.WithAuditLog(auth.ClaimKeyID))
+
+		assert.Len(t, entries, 1)
+		fields := entries[0].ContextMap()
+		assert.Equal(t, "some-uuid", fields[auth.ClaimKeyID])
+		assert.NotContains(t, fields, auth.ClaimKeyEmail)
+	})
+
+	t.Run("should log failures with the reason", func(t *testing.T) {
+		entries := serve(t, token+"tampered", auth.WithAuditLog())
+
+		assert.Len(t, entries, 1)
+		assert.Equal(t, zapcore.WarnLevel, entries[0].Level)
+		assert.Equal(t, "token validation failed", entries[0].Message)
+		assert.NotEmpty(t, entries[0].ContextMap()["reason"])
+	})
+
+	t.Run("should not log without the option", func(t *testing.T) {
+		assert.Empty(t, serve(t, token))
+	})
+}
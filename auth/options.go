@@ -0,0 +1,273 @@
+package auth
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+)
+
+// Option configures the JwtWrapper built by New.
+type Option func(*JwtWrapper)
+
+// WithSecret sets the HMAC secret used to sign and verify tokens. Required unless
+// WithEdDSAKeys or WithKeyProvider is used.
+func WithSecret(secretKey string) Option {
+	return func(j *JwtWrapper) {
+		j.SecretKey = secretKey
+	}
+}
+
+// WithEdDSAKeys switches the wrapper from HS256 with a secret to EdDSA, signing
+// tokens with the Ed25519 private key and verifying them with the public key.
+// The private key may be nil for services that only validate tokens. Use
+// ParseEd25519PrivateKey and ParseEd25519PublicKey to load PEM or OpenSSH keys.
+func WithEdDSAKeys(privateKey ed25519.PrivateKey, publicKey ed25519.PublicKey) Option {
+	return func(j *JwtWrapper) {
+		j.EdDSAPrivateKey = privateKey
+		j.EdDSAPublicKey = publicKey
+	}
+}
+
+// WithSigningMethod sets the HMAC variant signing generated tokens, one of
+// jwt.SigningMethodHS256, jwt.SigningMethodHS384 or jwt.SigningMethodHS512.
+// New rejects secrets shorter than the output of the method's hash.
+func WithSigningMethod(method *jwt.SigningMethodHMAC) Option {
+	return func(j *JwtWrapper) {
+		j.SigningMethod = method
+	}
+}
+
+// WithKeyProvider makes the wrapper fetch its HMAC keys from the provider on
+// every generation and validation instead of using a fixed secret, so keys
+// rotated in e.g. a vault are picked up without rebuilding the wrapper.
+func WithKeyProvider(provider KeyProvider) Option {
+	return func(j *JwtWrapper) {
+		j.KeyProvider = provider
+	}
+}
+
+// WithIssuer sets the issuer of generated tokens. Required.
+func WithIssuer(issuer string) Option {
+	return func(j *JwtWrapper) {
+		j.Issuer = issuer
+	}
+}
+
+// WithExpiration sets the default lifetime of generated tokens.
+// Either WithExpiration or WithExpirationHours is required.
+func WithExpiration(expiration time.Duration) Option {
+	return func(j *JwtWrapper) {
+		j.Expiration = expiration
+	}
+}
+
+// WithExpirationHours sets the default lifetime of generated tokens in hours.
+func WithExpirationHours(hours int64) Option {
+	return func(j *JwtWrapper) {
+		j.ExpirationHours = hours
+	}
+}
+
+// WithLeeway sets the clock skew tolerated when validating the time based claims.
+func WithLeeway(leeway time.Duration) Option {
+	return func(j *JwtWrapper) {
+		j.Leeway = leeway
+	}
+}
+
+// WithClock sets the function returning the current time, mostly useful in tests.
+func WithClock(clock func() time.Time) Option {
+	return func(j *JwtWrapper) {
+		j.Clock = clock
+	}
+}
+
+// WithIDGenerator sets the generator of token IDs (jti), e.g. a ULID or snowflake generator.
+func WithIDGenerator(generator func() string) Option {
+	return func(j *JwtWrapper) {
+		j.IDGenerator = generator
+	}
+}
+
+// WithValidationOptions adds declarative validation rules, see ValidationOption.
+func WithValidationOptions(opts ...ValidationOption) Option {
+	return func(j *JwtWrapper) {
+		j.ValidationOptions = append(j.ValidationOptions, opts...)
+	}
+}
+
+// WithValidateFunc sets the hook for custom business rules, see JwtWrapper.ValidateFunc.
+func WithValidateFunc(fn func(ctx context.Context, claims *JwtClaim) error) Option {
+	return func(j *JwtWrapper) {
+		j.ValidateFunc = fn
+	}
+}
+
+// WithRevoker sets the Revoker consulted on every validation.
+func WithRevoker(revoker Revoker) Option {
+	return func(j *JwtWrapper) {
+		j.Revoker = revoker
+	}
+}
+
+// WithRevocationFailOpen accepts tokens whose revocation cannot be checked
+// because the Revoker fails, logging a warning, instead of rejecting them.
+func WithRevocationFailOpen() Option {
+	return func(j *JwtWrapper) {
+		j.RevocationFailOpen = true
+	}
+}
+
+// WithCache sets the cache of verified tokens.
+func WithCache(cache *TokenCache) Option {
+	return func(j *JwtWrapper) {
+		j.Cache = cache
+	}
+}
+
+// WithNearExpiryThreshold sets the threshold under which IntrospectToken flags tokens as near expiry.
+func WithNearExpiryThreshold(threshold time.Duration) Option {
+	return func(j *JwtWrapper) {
+		j.NearExpiryThreshold = threshold
+	}
+}
+
+// WithSubjectAllowList restricts valid tokens to the given subjects, adding to
+// those of previous calls, see JwtWrapper.SetSubjectAllowList.
+func WithSubjectAllowList(subjects ...string) Option {
+	return func(j *JwtWrapper) {
+		j.SetSubjectAllowList(append(j.SubjectAllowList(), subjects...)...)
+	}
+}
+
+// WithLogger sets the logger warned by New when the secret is weak, and during
+// validation when the context has no logger.
+func WithLogger(logger goctx.Logger) Option {
+	return func(j *JwtWrapper) {
+		j.Logger = logger
+	}
+}
+
+// WithRefreshStore sets the store of refresh tokens, enabling IssueRefreshToken and RefreshToken.
+func WithRefreshStore(store RefreshStore) Option {
+	return func(j *JwtWrapper) {
+		j.RefreshStore = store
+	}
+}
+
+// WithRefreshExpiration sets the lifetime of refresh tokens.
+func WithRefreshExpiration(expiration time.Duration) Option {
+	return func(j *JwtWrapper) {
+		j.RefreshExpiration = expiration
+	}
+}
+
+// WithMaxTokenSize sets the length in bytes above which tokens are rejected with ErrTokenTooLarge.
+func WithMaxTokenSize(size int) Option {
+	return func(j *JwtWrapper) {
+		j.MaxTokenSize = size
+	}
+}
+
+// WithValidationRecorder sets the function recording the duration and outcome
+// of every ValidateToken, e.g. to a metrics histogram.
+func WithValidationRecorder(recorder func(ctx context.Context, duration time.Duration, err error)) Option {
+	return func(j *JwtWrapper) {
+		j.ValidationRecorder = recorder
+	}
+}
+
+// WithClaimsCompression DEFLATE-compresses the claims of generated tokens, for
+// tokens carrying large claim sets such as permission lists.
+func WithClaimsCompression() Option {
+	return func(j *JwtWrapper) {
+		j.CompressClaims = true
+	}
+}
+
+// WithMaxClaimsSize sets the decompressed size in bytes above which compressed
+// tokens are rejected with ErrTokenTooLarge.
+func WithMaxClaimsSize(size int) Option {
+	return func(j *JwtWrapper) {
+		j.MaxClaimsSize = size
+	}
+}
+
+// New creates a new JwtWrapper configured by the given options. The secret (or
+// a key provider), the issuer and an expiration are required. A secret failing
+// ValidateSecretStrength is accepted, but reported to the logger if one is set.
+func New(opts ...Option) (*JwtWrapper, error) {
+	j := &JwtWrapper{}
+	for _, opt := range opts {
+		opt(j)
+	}
+
+	if j.usesEdDSA() {
+		if j.KeyProvider != nil {
+			return nil, errors.New("key provider cannot be combined with EdDSA keys")
+		}
+		if err := validateEdDSAKeys(j.EdDSAPrivateKey, j.EdDSAPublicKey); err != nil {
+			return nil, err
+		}
+	} else if j.SecretKey == "" && j.KeyProvider == nil {
+		return nil, errors.New("secret key must be set")
+	}
+
+	if j.SigningMethod != nil {
+		if j.usesEdDSA() {
+			return nil, errors.New("signing method cannot be combined with EdDSA keys")
+		}
+		if j.SecretKey != "" {
+			if err := validateSecretForMethod(j.SecretKey, j.SigningMethod); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if j.Issuer == "" {
+		return nil, errors.New("issuer must be set")
+	}
+
+	if j.Expiration < 0 {
+		return nil, errors.New("expiration must be greater than 0")
+	}
+
+	if j.Expiration == 0 && j.ExpirationHours == 0 {
+		return nil, errors.New("expiration hours must be greater than 0")
+	}
+
+	if j.Leeway < 0 {
+		return nil, errors.New("leeway must not be negative")
+	}
+
+	if err := newValidationConfig(j.ValidationOptions...).err; err != nil {
+		return nil, err
+	}
+
+	if j.MaxTokenSize < 0 {
+		return nil, errors.New("max token size must not be negative")
+	}
+
+	if j.MaxClaimsSize < 0 {
+		return nil, errors.New("max claims size must not be negative")
+	}
+
+	if j.RefreshExpiration < 0 {
+		return nil, errors.New("refresh expiration must not be negative")
+	}
+
+	if !j.usesEdDSA() && j.KeyProvider == nil && j.Logger != nil {
+		if err := ValidateSecretStrength(j.SecretKey); err != nil {
+			goctx.Warn(context.Background(), j.Logger, "weak jwt secret", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+	}
+
+	return j, nil
+}
@@ -0,0 +1,182 @@
+package auth_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/junkd0g/go-microservice-commons/auth"
+)
+
+func Test_New(t *testing.T) {
+	type testCase struct {
+		name          string
+		opts          []auth.Option
+		expectedError string
+	}
+
+	testCases := []testCase{
+		{
+			name:          "missing secret",
+			opts:          []auth.Option{auth.WithIssuer("some-issuer"), auth.WithExpiration(time.Hour)},
+			expectedError: "secret key must be set",
+		},
+		{
+			name:          "missing issuer",
+			opts:          []auth.Option{auth.WithSecret("some-secret-key"), auth.WithExpiration(time.Hour)},
+			expectedError: "issuer must be set",
+		},
+		{
+			name:          "missing expiration",
+			opts:          []auth.Option{auth.WithSecret("some-secret-key"), auth.WithIssuer("some-issuer")},
+			expectedError: "expiration hours must be greater than 0",
+		},
+		{
+			name:          "negative expiration",
+			opts:          []auth.Option{auth.WithSecret("some-secret-key"), auth.WithIssuer("some-issuer"), auth.WithExpiration(-time.Hour)},
+			expectedError: "expiration must be greater than 0",
+		},
+		{
+			name: "negative leeway",
+			opts: []auth.Option{
+				auth.WithSecret("some-secret-key"), auth.WithIssuer("some-issuer"), auth.WithExpiration(time.Hour),
+				auth.WithLeeway(-time.Second),
+			},
+			expectedError: "leeway must not be negative",
+		},
+		{
+			name: "valid options",
+			opts: []auth.Option{
+				auth.WithSecret("some-secret-key"), auth.WithIssuer("some-issuer"), auth.WithExpiration(time.Hour),
+				auth.WithLeeway(time.Second), auth.WithSubjectAllowList("some-uuid"), auth.WithRevoker(auth.NewMemoryRevoker()),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			wrapper, err := auth.New(tc.opts...)
+
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				assert.Nil(t, wrapper)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, time.Second, wrapper.Leeway)
+				assert.Contains(t, wrapper.SubjectAllowList(), "some-uuid")
+				assert.NotNil(t, wrapper.Revoker)
+			}
+		})
+	}
+}
+
+func Test_NewWithExpiration(t *testing.T) {
+	ctx := context.Background()
+
+	wrapper, err := auth.New(auth.WithSecret("some-secret-key"), auth.WithIssuer("some-issuer"), auth.WithExpiration(10*time.Minute))
+	assert.NoError(t, err)
+
+	token, err := wrapper.GenerateToken(ctx, "some-uuid", "some-email")
+	assert.NoError(t, err)
+
+	claims, err := wrapper.ValidateToken(ctx, token)
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(10*time.Minute), claims.ExpiresAt.Time, 2*time.Second)
+}
+
+func Test_NewWithLeewayAndClock(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	token := signToken(t, "some-secret-key", &auth.JwtClaim{
+		ID: "some-uuid",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(-5 * time.Second)),
+		},
+	})
+
+	t.Run("should reject a recently expired token without leeway", func(t *testing.T) {
+		wrapper, err := auth.New(auth.WithSecret("some-secret-key"), auth.WithIssuer("some-issuer"), auth.WithExpiration(time.Hour))
+		assert.NoError(t, err)
+
+		_, err = wrapper.ValidateToken(ctx, token)
+		assert.ErrorContains(t, err, "expired")
+	})
+
+	t.Run("should accept a recently expired token within the leeway", func(t *testing.T) {
+		wrapper, err := auth.New(
+			auth.WithSecret("some-secret-key"), auth.WithIssuer("some-issuer"), auth.WithExpiration(time.Hour),
+			auth.WithLeeway(30*time.Second),
+		)
+		assert.NoError(t, err)
+
+		claims, err := wrapper.ValidateToken(ctx, token)
+		assert.NoError(t, err)
+		assert.Equal(t, "some-uuid", claims.ID)
+	})
+
+	t.Run("should validate against the configured clock", func(t *testing.T) {
+		wrapper, err := auth.New(
+			auth.WithSecret("some-secret-key"), auth.WithIssuer("some-issuer"), auth.WithExpiration(time.Hour),
+			auth.WithClock(func() time.Time { return now.Add(-time.Minute) }),
+		)
+		assert.NoError(t, err)
+
+		claims, err := wrapper.ValidateToken(ctx, token)
+		assert.NoError(t, err)
+		assert.Equal(t, "some-uuid", claims.ID)
+	})
+
+	t.Run("should reject a token not valid yet", func(t *testing.T) {
+		wrapper, err := auth.New(auth.WithSecret("some-secret-key"), auth.WithIssuer("some-issuer"), auth.WithExpiration(time.Hour))
+		assert.NoError(t, err)
+
+		notYetValid := signToken(t, "some-secret-key", &auth.JwtClaim{
+			RegisteredClaims: jwt.RegisteredClaims{NotBefore: jwt.NewNumericDate(now.Add(time.Hour))},
+		})
+
+		_, err = wrapper.ValidateToken(ctx, notYetValid)
+		assert.ErrorContains(t, err, "not valid yet")
+	})
+}
+
+func Test_IDGenerator(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should use the configured generator for the token id", func(t *testing.T) {
+		wrapper, err := auth.New(
+			auth.WithSecret("some-secret-key"), auth.WithIssuer("some-issuer"), auth.WithExpiration(time.Hour),
+			auth.WithIDGenerator(func() string { return "fixed-jti" }),
+		)
+		assert.NoError(t, err)
+
+		token, err := wrapper.GenerateToken(ctx, "some-uuid", "some-email")
+		assert.NoError(t, err)
+
+		claims, err := wrapper.ValidateToken(ctx, token)
+		assert.NoError(t, err)
+		assert.Equal(t, "fixed-jti", claims.RegisteredClaims.ID)
+		assert.Equal(t, "some-uuid", claims.ID)
+	})
+
+	t.Run("should default to unique uuid token ids", func(t *testing.T) {
+		wrapper, err := auth.NewJwtWrapper("some-secret-key", "some-issuer", 1)
+		assert.NoError(t, err)
+
+		first, err := wrapper.GenerateToken(ctx, "some-uuid", "some-email")
+		assert.NoError(t, err)
+		second, err := wrapper.GenerateToken(ctx, "some-uuid", "some-email")
+		assert.NoError(t, err)
+
+		firstClaims, err := wrapper.ValidateToken(ctx, first)
+		assert.NoError(t, err)
+		secondClaims, err := wrapper.ValidateToken(ctx, second)
+		assert.NoError(t, err)
+
+		assert.Len(t, firstClaims.RegisteredClaims.ID, 36)
+		assert.NotEqual(t, firstClaims.RegisteredClaims.ID, secondClaims.RegisteredClaims.ID)
+	})
+}
@@ -0,0 +1,221 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultRefreshExpiration is the lifetime of refresh tokens unless WithRefreshExpiration is used.
+const DefaultRefreshExpiration = 30 * 24 * time.Hour
+
+// refreshTokenBytes is the number of random bytes of an opaque refresh token.
+const refreshTokenBytes = 32
+
+// RefreshRecord is the server side state of a refresh token. Stores only ever
+// see the SHA-256 hash of the token, never the token itself.
+type RefreshRecord struct {
+	// ID is the hex encoded SHA-256 hash of the refresh token.
+	ID        string
+	UserID    string
+	Email     string
+	ExpiresAt time.Time
+	// Revoked is set once the token has been rotated or revoked.
+	Revoked bool
+}
+
+// RefreshStore persists refresh tokens so they can be rotated and revoked.
+type RefreshStore interface {
+	// Save stores a new refresh record.
+	Save(ctx context.Context, record RefreshRecord) error
+	// Get returns the record with the given ID, or ErrRefreshTokenNotFound.
+	Get(ctx context.Context, id string) (RefreshRecord, error)
+	// Rotate atomically revokes the record with the given ID and saves next in
+	// its place. It returns ErrRefreshTokenRevoked if the record was already
+	// revoked, so a token can only be rotated once.
+	Rotate(ctx context.Context, id string, next RefreshRecord) error
+	// RevokeAllForUser atomically revokes every record of the user, e.g. to log
+	// them out everywhere.
+	RevokeAllForUser(ctx context.Context, userID string) error
+}
+
+// IssueRefreshToken issues an opaque refresh token for the user and saves it in
+// the wrapper's RefreshStore. Exchange it for new tokens with RefreshToken.
+func (j *JwtWrapper) IssueRefreshToken(ctx context.Context, uuid, email string) (string, error) {
+	if j.RefreshStore == nil {
+		return "", errors.New("refresh store must be set")
+	}
+
+	token, record, err := j.newRefreshToken(uuid, email)
+	if err != nil {
+		return "", err
+	}
+
+	if err := j.RefreshStore.Save(ctx, record); err != nil {
+		return "", fmt.Errorf("saving refresh token: %w", err)
+	}
+
+	return token, nil
+}
+
+// RefreshToken exchanges a refresh token for a new access token and a new
+// refresh token, rotating the old one so it can't be used again. Unknown,
+// expired and revoked tokens are rejected. Presenting an already rotated token,
+// including one rotated concurrently by another request, signals it may have
+// been stolen, so every refresh token of the user is revoked as well.
+func (j *JwtWrapper) RefreshToken(ctx context.Context, refreshToken string) (accessToken, newRefreshToken string, err error) {
+	if j.RefreshStore == nil {
+		return "", "", errors.New("refresh store must be set")
+	}
+
+	refreshToken, err = normalizeToken(refreshToken)
+	if err != nil {
+		return "", "", err
+	}
+
+	id := refreshTokenID(refreshToken)
+
+	record, err := j.RefreshStore.Get(ctx, id)
+	if err != nil {
+		return "", "", err
+	}
+
+	if record.Revoked {
+		return "", "", j.revokeReusedToken(ctx, record)
+	}
+
+	if !j.now().Before(record.ExpiresAt) {
+		return "", "", ErrRefreshTokenExpired
+	}
+
+	// The access token is generated first, so a failure leaves the refresh
+	// token usable rather than rotated away
+	accessToken, err = j.GenerateToken(ctx, record.UserID, record.Email)
+	if err != nil {
+		return "", "", err
+	}
+
+	newRefreshToken, next, err := j.newRefreshToken(record.UserID, record.Email)
+	if err != nil {
+		return "", "", err
+	}
+
+	if err := j.RefreshStore.Rotate(ctx, id, next); err != nil {
+		// Another request rotated the token concurrently, which is reuse as well
+		if errors.Is(err, ErrRefreshTokenRevoked) {
+			return "", "", j.revokeReusedToken(ctx, record)
+		}
+		return "", "", err
+	}
+
+	return accessToken, newRefreshToken, nil
+}
+
+// revokeReusedToken revokes every refresh token of the record's user after its
+// already rotated token was presented, and returns ErrRefreshTokenRevoked.
+func (j *JwtWrapper) revokeReusedToken(ctx context.Context, record RefreshRecord) error {
+	if err := j.RefreshStore.RevokeAllForUser(ctx, record.UserID); err != nil {
+		return fmt.Errorf("revoking refresh tokens: %w", err)
+	}
+	return ErrRefreshTokenRevoked
+}
+
+// newRefreshToken returns a new random refresh token and its record.
+func (j *JwtWrapper) newRefreshToken(uuid, email string) (string, RefreshRecord, error) {
+	b := make([]byte, refreshTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", RefreshRecord{}, fmt.Errorf("generating refresh token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	expiration := j.RefreshExpiration
+	if expiration == 0 {
+		expiration = DefaultRefreshExpiration
+	}
+
+	return token, RefreshRecord{
+		ID:        refreshTokenID(token),
+		UserID:    uuid,
+		Email:     email,
+		ExpiresAt: j.now().Add(expiration),
+	}, nil
+}
+
+// refreshTokenID returns the ID under which a refresh token is stored.
+func refreshTokenID(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// MemoryRefreshStore is an in-memory RefreshStore. It is safe for concurrent
+// use and mostly useful for tests and single instance services.
+type MemoryRefreshStore struct {
+	sync.RWMutex
+	records map[string]RefreshRecord
+}
+
+// NewMemoryRefreshStore initializes a new instance of MemoryRefreshStore.
+func NewMemoryRefreshStore() *MemoryRefreshStore {
+	return &MemoryRefreshStore{
+		records: make(map[string]RefreshRecord),
+	}
+}
+
+// Save stores a new refresh record.
+func (s *MemoryRefreshStore) Save(ctx context.Context, record RefreshRecord) error {
+	s.Lock()
+	defer s.Unlock()
+	s.records[record.ID] = record
+	return nil
+}
+
+// Get returns the record with the given ID, or ErrRefreshTokenNotFound.
+func (s *MemoryRefreshStore) Get(ctx context.Context, id string) (RefreshRecord, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	record, ok := s.records[id]
+	if !ok {
+		return RefreshRecord{}, ErrRefreshTokenNotFound
+	}
+	return record, nil
+}
+
+// Rotate revokes the record with the given ID and saves next in its place.
+func (s *MemoryRefreshStore) Rotate(ctx context.Context, id string, next RefreshRecord) error {
+	s.Lock()
+	defer s.Unlock()
+
+	record, ok := s.records[id]
+	if !ok {
+		return ErrRefreshTokenNotFound
+	}
+	if record.Revoked {
+		return ErrRefreshTokenRevoked
+	}
+
+	record.Revoked = true
+	s.records[id] = record
+	s.records[next.ID] = next
+	return nil
+}
+
+// RevokeAllForUser revokes every record of the user.
+func (s *MemoryRefreshStore) RevokeAllForUser(ctx context.Context, userID string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	for id, record := range s.records {
+		if record.UserID == userID {
+			record.Revoked = true
+			s.records[id] = record
+		}
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/junkd0g/go-microservice-commons/auth"
+)
+
+func Test_RefreshToken(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should rotate the refresh token and issue an access token", func(t *testing.T) {
+		jwtWrapper := newTestWrapper(t, auth.WithRefreshStore(auth.NewMemoryRefreshStore()))
+
+		refreshToken, err := jwtWrapper.IssueRefreshToken(ctx, "some-uuid", "some-email")
+		assert.NoError(t, err)
+
+		accessToken, newRefreshToken, err := jwtWrapper.RefreshToken(ctx, refreshToken)
+		assert.NoError(t, err)
+		assert.NotEqual(t, refreshToken, newRefreshToken)
+
+		claims, err := jwtWrapper.ValidateToken(ctx, accessToken)
+		assert.NoError(t, err)
+		assert.Equal(t, "some-uuid", claims.ID)
+		assert.Equal(t, "some-email", claims.Email)
+
+		_, _, err = jwtWrapper.RefreshToken(ctx, newRefreshToken)
+		assert.NoError(t, err)
+	})
+
+	t.Run("should revoke all tokens of the user when a rotated token is reused", func(t *testing.T) {
+		jwtWrapper := newTestWrapper(t, auth.WithRefreshStore(auth.NewMemoryRefreshStore()))
+
+		refreshToken, err := jwtWrapper.IssueRefreshToken(ctx, "some-uuid", "some-email")
+		assert.NoError(t, err)
+		otherSession, err := jwtWrapper.IssueRefreshToken(ctx, "some-uuid", "some-email")
+		assert.NoError(t, err)
+
+		_, newRefreshToken, err := jwtWrapper.RefreshToken(ctx, refreshToken)
+		assert.NoError(t, err)
+
+		_, _, err = jwtWrapper.RefreshToken(ctx, refreshToken)
+		assert.ErrorIs(t, err, auth.ErrRefreshTokenRevoked)
+
+		_, _, err = jwtWrapper.RefreshToken(ctx, newRefreshToken)
+		assert.ErrorIs(t, err, auth.ErrRefreshTokenRevoked)
+		_, _, err = jwtWrapper.RefreshToken(ctx, otherSession)
+		assert.ErrorIs(t, err, auth.ErrRefreshTokenRevoked)
+	})
+
+	t.Run("should revoke all tokens of the user when a token is rotated concurrently", func(t *testing.T) {
+		store := staleRefreshStore{auth.NewMemoryRefreshStore()}
+		jwtWrapper := newTestWrapper(t, auth.WithRefreshStore(store))
+
+		refreshToken, err := jwtWrapper.IssueRefreshToken(ctx, "some-uuid", "some-email")
+		assert.NoError(t, err)
+
+		_, newRefreshToken, err := jwtWrapper.RefreshToken(ctx, refreshToken)
+		assert.NoError(t, err)
+
+		// The store still reports the token as valid, so only Rotate sees the reuse
+		_, _, err = jwtWrapper.RefreshToken(ctx, refreshToken)
+		assert.ErrorIs(t, err, auth.ErrRefreshTokenRevoked)
+
+		record, err := store.MemoryRefreshStore.Get(ctx, refreshTokenID(newRefreshToken))
+		assert.NoError(t, err)
+		assert.True(t, record.Revoked)
+	})
+
+	t.Run("should refuse tokens revoked for the user", func(t *testing.T) {
+		store := auth.NewMemoryRefreshStore()
+		jwtWrapper := newTestWrapper(t, auth.WithRefreshStore(store))
+
+		refreshToken, err := jwtWrapper.IssueRefreshToken(ctx, "some-uuid", "some-email")
+		assert.NoError(t, err)
+		otherUser, err := jwtWrapper.IssueRefreshToken(ctx, "other-uuid", "other-email")
+		assert.NoError(t, err)
+
+		assert.NoError(t, store.RevokeAllForUser(ctx, "some-uuid"))
+
+		_, _, err = jwtWrapper.RefreshToken(ctx, refreshToken)
+		assert.ErrorIs(t, err, auth.ErrRefreshTokenRevoked)
+		_, _, err = jwtWrapper.RefreshToken(ctx, otherUser)
+		assert.NoError(t, err)
+	})
+
+	t.Run("should refuse expired and unknown tokens", func(t *testing.T) {
+		now := time.Now()
+		jwtWrapper := newTestWrapper(t,
+			auth.WithRefreshStore(auth.NewMemoryRefreshStore()),
+			auth.WithRefreshExpiration(time.Hour),
+			auth.WithClock(func() time.Time { return now }),
+		)
+
+		refreshToken, err := jwtWrapper.IssueRefreshToken(ctx, "some-uuid", "some-email")
+		assert.NoError(t, err)
+
+		now = now.Add(2 * time.Hour)
+		_, _, err = jwtWrapper.RefreshToken(ctx, refreshToken)
+		assert.ErrorIs(t, err, auth.ErrRefreshTokenExpired)
+
+		_, _, err = jwtWrapper.RefreshToken(ctx, "unknown-token")
+		assert.ErrorIs(t, err, auth.ErrRefreshTokenNotFound)
+	})
+
+	t.Run("should fail without a store", func(t *testing.T) {
+		jwtWrapper, err := auth.NewJwtWrapper("some-secret-key", "some-issuer", 1)
+		assert.NoError(t, err)
+
+		_, err = jwtWrapper.IssueRefreshToken(ctx, "some-uuid", "some-email")
+		assert.EqualError(t, err, "refresh store must be set")
+
+		_, _, err = jwtWrapper.RefreshToken(ctx, "some-token")
+		assert.EqualError(t, err, "refresh store must be set")
+	})
+}
+
+// staleRefreshStore is a RefreshStore whose Get never reports records as
+// revoked, as if another request rotated them in the meantime.
+type staleRefreshStore struct {
+	*auth.MemoryRefreshStore
+}
+
+// Get returns the record as not yet revoked.
+func (s staleRefreshStore) Get(ctx context.Context, id string) (auth.RefreshRecord, error) {
+	record, err := s.MemoryRefreshStore.Get(ctx, id)
+	record.Revoked = false
+	return record, err
+}
+
+// refreshTokenID returns the ID under which a refresh token is stored.
+func refreshTokenID(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"context"
+	"sync"
+)
+
+// Revoker reports whether the token carrying the given claims has been revoked.
+type Revoker interface {
+	IsRevoked(ctx context.Context, claims *JwtClaim) (bool, error)
+}
+
+// RevokerFunc adapts an ordinary function to the Revoker interface.
+type RevokerFunc func(ctx context.Context, claims *JwtClaim) (bool, error)
+
+// IsRevoked calls f(ctx, claims).
+func (f RevokerFunc) IsRevoked(ctx context.Context, claims *JwtClaim) (bool, error) {
+	return f(ctx, claims)
+}
+
+// MemoryRevoker is an in-memory Revoker keyed by the subject ID claim.
+// It is safe for concurrent use and mostly useful for tests and single instance services.
+type MemoryRevoker struct {
+	sync.RWMutex
+	revoked map[string]struct{}
+}
+
+// NewMemoryRevoker initializes a new instance of MemoryRevoker.
+func NewMemoryRevoker() *MemoryRevoker {
+	return &MemoryRevoker{
+		revoked: make(map[string]struct{}),
+	}
+}
+
+// Revoke marks all tokens issued for the given ID as revoked.
+func (r *MemoryRevoker) Revoke(id string) {
+	r.Lock()
+	defer r.Unlock()
+	r.revoked[id] = struct{}{}
+}
+
+// IsRevoked reports whether the ID of the claims has been revoked.
+func (r *MemoryRevoker) IsRevoked(ctx context.Context, claims *JwtClaim) (bool, error) {
+	r.RLock()
+	defer r.RUnlock()
+	_, ok := r.revoked[claims.ID]
+	return ok, nil
+}
@@ -0,0 +1,104 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/junkd0g/go-microservice-commons/auth"
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+	"github.com/junkd0g/go-microservice-commons/logger"
+)
+
+func Test_ValidateTokenWithRevoker(t *testing.T) {
+	ctx := context.Background()
+
+	jwtWrapper, err := auth.NewJwtWrapper("some-secret-key", "some-issuer", 1)
+	assert.NoError(t, err)
+
+	revoker := auth.NewMemoryRevoker()
+	jwtWrapper.Revoker = revoker
+
+	token, err := jwtWrapper.GenerateToken(ctx, "some-uuid", "some-email")
+	assert.NoError(t, err)
+
+	t.Run("should accept a token that is not revoked", func(t *testing.T) {
+		claims, err := jwtWrapper.ValidateToken(ctx, token)
+		assert.NoError(t, err)
+		assert.NotNil(t, claims)
+	})
+
+	t.Run("should reject a revoked token", func(t *testing.T) {
+		revoker.Revoke("some-uuid")
+
+		claims, err := jwtWrapper.ValidateToken(ctx, token)
+		assert.ErrorIs(t, err, auth.ErrTokenRevoked)
+		assert.Nil(t, claims)
+	})
+
+	t.Run("should fail when the revoker fails", func(t *testing.T) {
+		errStore := errors.New("store unavailable")
+		jwtWrapper.Revoker = auth.RevokerFunc(func(ctx context.Context, claims *auth.JwtClaim) (bool, error) {
+			return false, errStore
+		})
+
+		claims, err := jwtWrapper.ValidateToken(ctx, token)
+		assert.ErrorIs(t, err, errStore)
+		assert.Nil(t, claims)
+	})
+}
+
+func Test_RevocationFailOpen(t *testing.T) {
+	ctx := context.Background()
+
+	errStore := errors.New("store unavailable")
+	failingRevoker := auth.RevokerFunc(func(ctx context.Context, claims *auth.JwtClaim) (bool, error) {
+		return false, errStore
+	})
+
+	t.Run("should reject tokens when failing closed by default", func(t *testing.T) {
+		jwtWrapper := newTestWrapper(t, auth.WithRevoker(failingRevoker))
+		token, err := jwtWrapper.GenerateToken(ctx, "some-uuid", "some-email")
+		assert.NoError(t, err)
+
+		claims, err := jwtWrapper.ValidateToken(ctx, token)
+		assert.ErrorIs(t, err, errStore)
+		assert.Nil(t, claims)
+	})
+
+	t.Run("should accept tokens and warn when failing open", func(t *testing.T) {
+		log, err := logger.NewLogger()
+		assert.NoError(t, err)
+		core, recorded := observer.New(zapcore.InfoLevel)
+		log.SetCore(core)
+
+		jwtWrapper := newTestWrapper(t, auth.WithRevoker(failingRevoker), auth.WithRevocationFailOpen())
+		token, err := jwtWrapper.GenerateToken(ctx, "some-uuid", "some-email")
+		assert.NoError(t, err)
+
+		claims, err := jwtWrapper.ValidateToken(goctx.AddLoggerToContex(ctx, log), token)
+		assert.NoError(t, err)
+		assert.Equal(t, "some-uuid", claims.ID)
+
+		entries := recorded.All()
+		assert.Len(t, entries, 1)
+		assert.Equal(t, zapcore.WarnLevel, entries[0].Level)
+		assert.Equal(t, "revocation check failed, accepting token", entries[0].Message)
+		assert.Equal(t, "store unavailable", entries[0].ContextMap()["error"])
+	})
+
+	t.Run("should still reject revoked tokens when failing open", func(t *testing.T) {
+		revoker := auth.NewMemoryRevoker()
+		revoker.Revoke("some-uuid")
+		jwtWrapper := newTestWrapper(t, auth.WithRevocationFailOpen(), auth.WithRevoker(revoker))
+		token, err := jwtWrapper.GenerateToken(ctx, "some-uuid", "some-email")
+		assert.NoError(t, err)
+
+		_, err = jwtWrapper.ValidateToken(ctx, token)
+		assert.ErrorIs(t, err, auth.ErrTokenRevoked)
+	})
+}
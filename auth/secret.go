@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// MinSecretLength is the minimum length in bytes of a safe HMAC secret, matching
+// the output size of SHA-256 as recommended by RFC 7518 for HS256.
+const MinSecretLength = 32
+
+// GenerateSecret returns a base64 encoded secret made of the given number of
+// cryptographically random bytes, suitable as the wrapper's SecretKey. It
+// rejects sizes below MinSecretLength.
+func GenerateSecret(bytes int) (string, error) {
+	if bytes < MinSecretLength {
+		return "", fmt.Errorf("secret must be at least %d bytes", MinSecretLength)
+	}
+
+	secret := make([]byte, bytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("generating secret: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(secret), nil
+}
+
+// ValidateSecretStrength returns an error when the secret is shorter than
+// MinSecretLength bytes and therefore too weak to sign tokens with HMAC.
+func ValidateSecretStrength(secret string) error {
+	if len(secret) < MinSecretLength {
+		return fmt.Errorf("secret is %d bytes, must be at least %d bytes", len(secret), MinSecretLength)
+	}
+	return nil
+}
+
+// validateSecretForMethod returns an error when the secret is shorter than the
+// output size of the method's hash, the minimum required by RFC 7518.
+func validateSecretForMethod(secret string, method *jwt.SigningMethodHMAC) error {
+	if minLength := method.Hash.Size(); len(secret) < minLength {
+		return fmt.Errorf("secret is %d bytes, %s requires at least %d bytes", len(secret), method.Alg(), minLength)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package auth_test
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/junkd0g/go-microservice-commons/auth"
+)
+
+// warnLogger records the messages of warnings.
+type warnLogger struct {
+	warnings []string
+}
+
+func (l *warnLogger) Info(ctx context.Context, msg string, fields ...map[string]interface{}) {}
+
+func (l *warnLogger) Error(ctx context.Context, msg string, fields ...map[string]interface{}) {}
+
+func (l *warnLogger) Warn(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.warnings = append(l.warnings, msg)
+}
+
+func Test_GenerateSecret(t *testing.T) {
+	t.Run("should generate a random base64 secret", func(t *testing.T) {
+		secret, err := auth.GenerateSecret(auth.MinSecretLength)
+		assert.NoError(t, err)
+
+		decoded, err := base64.StdEncoding.DecodeString(secret)
+		assert.NoError(t, err)
+		assert.Len(t, decoded, auth.MinSecretLength)
+		assert.NoError(t, auth.ValidateSecretStrength(secret))
+
+		other, err := auth.GenerateSecret(auth.MinSecretLength)
+		assert.NoError(t, err)
+		assert.NotEqual(t, secret, other)
+	})
+
+	t.Run("should reject short secrets", func(t *testing.T) {
+		_, err := auth.GenerateSecret(16)
+		assert.EqualError(t, err, "secret must be at least 32 bytes")
+	})
+}
+
+func Test_ValidateSecretStrength(t *testing.T) {
+	assert.EqualError(t, auth.ValidateSecretStrength("some-secret-key"), "secret is 15 bytes, must be at least 32 bytes")
+	assert.NoError(t, auth.ValidateSecretStrength("a-secret-key-of-at-least-32-bytes"))
+}
+
+func Test_NewWeakSecretWarning(t *testing.T) {
+	logger := &warnLogger{}
+
+	_, err := auth.New(
+		auth.WithSecret("a-secret-key-of-at-least-32-bytes"),
+		auth.WithIssuer("some-issuer"),
+		auth.WithExpirationHours(1),
+		auth.WithLogger(logger),
+	)
+	assert.NoError(t, err)
+	assert.Empty(t, logger.warnings)
+
+	_, err = auth.New(
+		auth.WithSecret("some-secret-key"),
+		auth.WithIssuer("some-issuer"),
+		auth.WithExpirationHours(1),
+		auth.WithLogger(logger),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"weak jwt secret"}, logger.warnings)
+}
@@ -0,0 +1,164 @@
+package auth
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// DefaultTokenTypes are the typ header values accepted unless WithAcceptedTypes
+// is used. Tokens without a typ are accepted as well, see WithTypeRequired.
+var DefaultTokenTypes = []string{"JWT"}
+
+// ValidationOption declares a rule applied by ValidateToken.
+type ValidationOption func(*validationConfig)
+
+// validationConfig holds the rules collected from ValidationOptions.
+type validationConfig struct {
+	issuers            []string
+	issuerPattern      *regexp.Regexp
+	audience           string
+	expirationRequired bool
+	parserOptions      []jwt.ParserOption
+	acceptedTypes      []string
+	typeRequired       bool
+	err                error
+}
+
+// WithExpectedIssuer rejects tokens whose iss claim is not issuer with ErrInvalidIssuer.
+func WithExpectedIssuer(issuer string) ValidationOption {
+	return WithExpectedIssuers(issuer)
+}
+
+// WithExpectedIssuers rejects tokens whose iss claim is not one of issuers with
+// ErrInvalidIssuer. Combined with WithIssuerPattern, matching either is enough.
+func WithExpectedIssuers(issuers ...string) ValidationOption {
+	return func(c *validationConfig) {
+		c.issuers = issuers
+	}
+}
+
+// WithIssuerPattern rejects tokens whose iss claim doesn't fully match the
+// regular expression with ErrInvalidIssuer, for dynamic issuers such as
+// per-tenant subdomains: `tenant-\d+\.auth\.example\.com`. The pattern is
+// anchored at both ends and compiled once; New fails if it is invalid.
+// Combined with WithExpectedIssuers, matching either is enough.
+func WithIssuerPattern(pattern string) ValidationOption {
+	issuerPattern, err := regexp.Compile(`^(?:` + pattern + `)$`)
+	return func(c *validationConfig) {
+		if err != nil {
+			c.err = fmt.Errorf("invalid issuer pattern: %w", err)
+			return
+		}
+		c.issuerPattern = issuerPattern
+	}
+}
+
+// WithExpectedAudience rejects tokens whose aud claim does not contain audience with ErrInvalidAudience.
+func WithExpectedAudience(audience string) ValidationOption {
+	return func(c *validationConfig) {
+		c.audience = audience
+	}
+}
+
+// WithExpirationRequired rejects tokens without an exp claim with ErrNoExpiration.
+func WithExpirationRequired() ValidationOption {
+	return func(c *validationConfig) {
+		c.expirationRequired = true
+	}
+}
+
+// WithParserOptions passes options through to the underlying jwt parser,
+// e.g. jwt.WithValidMethods or jwt.WithJSONNumber.
+func WithParserOptions(opts ...jwt.ParserOption) ValidationOption {
+	return func(c *validationConfig) {
+		c.parserOptions = append(c.parserOptions, opts...)
+	}
+}
+
+// WithAcceptedTypes sets the typ header values accepted, e.g. "JWT" and
+// "at+jwt", instead of DefaultTokenTypes. Tokens with another typ are rejected
+// with ErrUnexpectedTokenType. Values are compared case-insensitively. Tokens
+// without a typ, which RFC 7519 makes optional, are accepted unless
+// WithTypeRequired is used.
+func WithAcceptedTypes(types ...string) ValidationOption {
+	return func(c *validationConfig) {
+		c.acceptedTypes = types
+	}
+}
+
+// WithTypeRequired rejects tokens without a typ header with
+// ErrUnexpectedTokenType, e.g. when the issuer always sets it.
+func WithTypeRequired() ValidationOption {
+	return func(c *validationConfig) {
+		c.typeRequired = true
+	}
+}
+
+// newValidationConfig collects the given options into a validationConfig.
+func newValidationConfig(opts ...ValidationOption) *validationConfig {
+	config := &validationConfig{acceptedTypes: DefaultTokenTypes}
+	for _, opt := range opts {
+		opt(config)
+	}
+	return config
+}
+
+// validIssuer reports whether the issuer is expected, always true when no
+// issuer rule is declared.
+func (c *validationConfig) validIssuer(issuer string) bool {
+	if len(c.issuers) == 0 && c.issuerPattern == nil {
+		return true
+	}
+	if issuer == "" {
+		return false
+	}
+	for _, expected := range c.issuers {
+		if issuer == expected {
+			return true
+		}
+	}
+	return c.issuerPattern != nil && c.issuerPattern.MatchString(issuer)
+}
+
+// acceptsType reports whether the typ header value is one of the accepted
+// types, or absent while no type is required.
+func (c *validationConfig) acceptsType(typ interface{}) bool {
+	if typ == nil {
+		return !c.typeRequired
+	}
+	value, ok := typ.(string)
+	if !ok {
+		return false
+	}
+	for _, accepted := range c.acceptedTypes {
+		if strings.EqualFold(value, accepted) {
+			return true
+		}
+	}
+	return false
+}
+
+// validate applies the declared rules to parsed claims.
+func (c *validationConfig) validate(claims *JwtClaim) error {
+	if c.err != nil {
+		return c.err
+	}
+
+	if !c.validIssuer(claims.Issuer) {
+		return ErrInvalidIssuer
+	}
+
+	if c.audience != "" && !claims.VerifyAudience(c.audience, true) {
+		return ErrInvalidAudience
+	}
+
+	// The expiry itself is checked with the wrapper's leeway by the caller
+	if c.expirationRequired && claims.ExpiresAt == nil {
+		return ErrNoExpiration
+	}
+
+	return nil
+}
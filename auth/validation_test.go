@@ -0,0 +1,173 @@
+package auth_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/junkd0g/go-microservice-commons/auth"
+)
+
+func Test_ValidationOptions(t *testing.T) {
+	ctx := context.Background()
+
+	expiresAt := jwt.NewNumericDate(time.Now().Add(time.Hour))
+
+	t.Run("expected issuer", func(t *testing.T) {
+		jwtWrapper := newTestWrapper(t, auth.WithValidationOptions(auth.WithExpectedIssuer("some-issuer")))
+
+		valid := signToken(t, "some-secret-key", &auth.JwtClaim{RegisteredClaims: jwt.RegisteredClaims{Issuer: "some-issuer", ExpiresAt: expiresAt}})
+		_, err := jwtWrapper.ValidateToken(ctx, valid)
+		assert.NoError(t, err)
+
+		invalid := signToken(t, "some-secret-key", &auth.JwtClaim{RegisteredClaims: jwt.RegisteredClaims{Issuer: "other-issuer", ExpiresAt: expiresAt}})
+		_, err = jwtWrapper.ValidateToken(ctx, invalid)
+		assert.ErrorIs(t, err, auth.ErrInvalidIssuer)
+	})
+
+	t.Run("issuer pattern", func(t *testing.T) {
+		jwtWrapper := newTestWrapper(t, auth.WithValidationOptions(auth.WithIssuerPattern(`tenant-\d+\.auth\.example\.com`)))
+
+		for _, issuer := range []string{"tenant-123.auth.example.com", "tenant-7.auth.example.com"} {
+			valid := signToken(t, "some-secret-key", &auth.JwtClaim{RegisteredClaims: jwt.RegisteredClaims{Issuer: issuer, ExpiresAt: expiresAt}})
+			_, err := jwtWrapper.ValidateToken(ctx, valid)
+			assert.NoError(t, err, issuer)
+		}
+
+		for _, issuer := range []string{"tenant-abc.auth.example.com", "evil.com/tenant-1.auth.example.com", "tenant-1.auth.example.com.evil.com", ""} {
+			invalid := signToken(t, "some-secret-key", &auth.JwtClaim{RegisteredClaims: jwt.RegisteredClaims{Issuer: issuer, ExpiresAt: expiresAt}})
+			_, err := jwtWrapper.ValidateToken(ctx, invalid)
+			assert.ErrorIs(t, err, auth.ErrInvalidIssuer, issuer)
+		}
+	})
+
+	t.Run("issuer list or pattern", func(t *testing.T) {
+		jwtWrapper := newTestWrapper(t, auth.WithValidationOptions(
+			auth.WithExpectedIssuers("legacy-issuer", "some-issuer"),
+			auth.WithIssuerPattern(`tenant-\d+\.auth\.example\.com`),
+		))
+
+		for _, issuer := range []string{"legacy-issuer", "some-issuer", "tenant-1.auth.example.com"} {
+			valid := signToken(t, "some-secret-key", &auth.JwtClaim{RegisteredClaims: jwt.RegisteredClaims{Issuer: issuer, ExpiresAt: expiresAt}})
+			_, err := jwtWrapper.ValidateToken(ctx, valid)
+			assert.NoError(t, err, issuer)
+		}
+
+		invalid := signToken(t, "some-secret-key", &auth.JwtClaim{RegisteredClaims: jwt.RegisteredClaims{Issuer: "other-issuer", ExpiresAt: expiresAt}})
+		_, err := jwtWrapper.ValidateToken(ctx, invalid)
+		assert.ErrorIs(t, err, auth.ErrInvalidIssuer)
+	})
+
+	t.Run("invalid issuer pattern", func(t *testing.T) {
+		_, err := auth.New(
+			auth.WithSecret("some-secret-key"),
+			auth.WithIssuer("some-issuer"),
+			auth.WithExpirationHours(1),
+			auth.WithValidationOptions(auth.WithIssuerPattern(`tenant-(\d+`)),
+		)
+		assert.ErrorContains(t, err, "invalid issuer pattern")
+	})
+
+	t.Run("expected audience", func(t *testing.T) {
+		jwtWrapper := newTestWrapper(t, auth.WithValidationOptions(auth.WithExpectedAudience("some-api")))
+
+		valid := signToken(t, "some-secret-key", &auth.JwtClaim{RegisteredClaims: jwt.RegisteredClaims{Audience: jwt.ClaimStrings{"other-api", "some-api"}, ExpiresAt: expiresAt}})
+		_, err := jwtWrapper.ValidateToken(ctx, valid)
+		assert.NoError(t, err)
+
+		invalid := signToken(t, "some-secret-key", &auth.JwtClaim{RegisteredClaims: jwt.RegisteredClaims{Audience: jwt.ClaimStrings{"other-api"}, ExpiresAt: expiresAt}})
+		_, err = jwtWrapper.ValidateToken(ctx, invalid)
+		assert.ErrorIs(t, err, auth.ErrInvalidAudience)
+
+		missing := signToken(t, "some-secret-key", &auth.JwtClaim{RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: expiresAt}})
+		_, err = jwtWrapper.ValidateToken(ctx, missing)
+		assert.ErrorIs(t, err, auth.ErrInvalidAudience)
+	})
+
+	t.Run("expiration required", func(t *testing.T) {
+		withoutExpiration := signToken(t, "some-secret-key", &auth.JwtClaim{ID: "some-uuid"})
+		withExpiration := signToken(t, "some-secret-key", &auth.JwtClaim{ID: "some-uuid", RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: expiresAt}})
+		justExpired := signToken(t, "some-secret-key", &auth.JwtClaim{ID: "some-uuid", RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(-10 * time.Second)),
+		}})
+		required := auth.WithValidationOptions(auth.WithExpirationRequired())
+
+		testCases := []struct {
+			name          string
+			opts          []auth.Option
+			token         string
+			expectedError error
+		}{
+			{name: "missing expiration allowed by default", token: withoutExpiration},
+			{name: "missing expiration rejected when required", opts: []auth.Option{required}, token: withoutExpiration, expectedError: auth.ErrNoExpiration},
+			{name: "expiration allowed by default", token: withExpiration},
+			{name: "expiration accepted when required", opts: []auth.Option{required}, token: withExpiration},
+			{name: "expired token rejected when required", opts: []auth.Option{required}, token: justExpired, expectedError: jwt.ErrTokenExpired},
+			{name: "expiration within the leeway accepted when required", opts: []auth.Option{required, auth.WithLeeway(time.Minute)}, token: justExpired},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				_, err := newTestWrapper(t, tc.opts...).ValidateToken(ctx, tc.token)
+				if tc.expectedError != nil {
+					assert.ErrorIs(t, err, tc.expectedError)
+					return
+				}
+				assert.NoError(t, err)
+			})
+		}
+	})
+
+	t.Run("parser options", func(t *testing.T) {
+		jwtWrapper := newTestWrapper(t, auth.WithValidationOptions(auth.WithParserOptions(jwt.WithValidMethods([]string{"HS512"}))))
+
+		token := signToken(t, "some-secret-key", &auth.JwtClaim{RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: expiresAt}})
+		_, err := jwtWrapper.ValidateToken(ctx, token)
+		assert.Error(t, err)
+	})
+
+	t.Run("expiry safety net with claims validation disabled", func(t *testing.T) {
+		jwtWrapper := newTestWrapper(t, auth.WithValidationOptions(auth.WithParserOptions(jwt.WithoutClaimsValidation())))
+
+		expired := signToken(t, "some-secret-key", &auth.JwtClaim{RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute))}})
+		_, err := jwtWrapper.ValidateToken(ctx, expired)
+		assert.EqualError(t, err, "jwt is expired")
+	})
+
+	t.Run("token type", func(t *testing.T) {
+		signTyped := func(t *testing.T, typ interface{}) string {
+			token := jwt.NewWithClaims(jwt.SigningMethodHS256, &auth.JwtClaim{RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: expiresAt}})
+			if typ == nil {
+				delete(token.Header, "typ")
+			} else {
+				token.Header["typ"] = typ
+			}
+			signed, err := token.SignedString([]byte("some-secret-key"))
+			assert.NoError(t, err)
+			return signed
+		}
+
+		_, err := newTestWrapper(t).ValidateToken(ctx, signTyped(t, "jwt"))
+		assert.NoError(t, err)
+
+		for _, typ := range []interface{}{"none", "at+jwt", 1} {
+			_, err = newTestWrapper(t).ValidateToken(ctx, signTyped(t, typ))
+			assert.ErrorIs(t, err, auth.ErrUnexpectedTokenType, "typ %v", typ)
+		}
+
+		// The typ header is optional unless required
+		_, err = newTestWrapper(t).ValidateToken(ctx, signTyped(t, nil))
+		assert.NoError(t, err)
+		_, err = newTestWrapper(t, auth.WithValidationOptions(auth.WithTypeRequired())).ValidateToken(ctx, signTyped(t, nil))
+		assert.ErrorIs(t, err, auth.ErrUnexpectedTokenType)
+
+		jwtWrapper := newTestWrapper(t, auth.WithValidationOptions(auth.WithAcceptedTypes("JWT", "at+jwt")))
+		_, err = jwtWrapper.ValidateToken(ctx, signTyped(t, "at+jwt"))
+		assert.NoError(t, err)
+		_, err = jwtWrapper.ValidateToken(ctx, signTyped(t, "none"))
+		assert.ErrorIs(t, err, auth.ErrUnexpectedTokenType)
+	})
+}
@@ -0,0 +1,30 @@
+package auth
+
+import "context"
+
+// Claims is the normalized set of claims shared by every Validator.
+type Claims = JwtClaim
+
+// Validator abstracts token verification so callers such as the middleware
+// can swap validation backends (JWT, introspection) without code changes.
+type Validator interface {
+	Validate(ctx context.Context, token string) (*Claims, error)
+}
+
+// Validate implements Validator by calling ValidateToken.
+func (j *JwtWrapper) Validate(ctx context.Context, token string) (*Claims, error) {
+	return j.ValidateToken(ctx, token)
+}
+
+// Validate implements Validator by calling ValidateToken.
+func (v *IntrospectionValidator) Validate(ctx context.Context, token string) (*Claims, error) {
+	return v.ValidateToken(ctx, token)
+}
+
+// ValidatorFunc adapts an ordinary function to the Validator interface.
+type ValidatorFunc func(ctx context.Context, token string) (*Claims, error)
+
+// Validate calls f(ctx, token).
+func (f ValidatorFunc) Validate(ctx context.Context, token string) (*Claims, error) {
+	return f(ctx, token)
+}
@@ -0,0 +1,67 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/junkd0g/go-microservice-commons/auth"
+)
+
+var (
+	_ auth.Validator = (*auth.JwtWrapper)(nil)
+	_ auth.Validator = (*auth.IntrospectionValidator)(nil)
+	_ auth.Validator = auth.ValidatorFunc(nil)
+)
+
+func Test_Validator(t *testing.T) {
+	t.Run("should validate tokens through the JwtWrapper", func(t *testing.T) {
+		ctx := context.Background()
+
+		jwtWrapper, err := auth.NewJwtWrapper("some-secret-key", "some-issuer", 1)
+		assert.NoError(t, err)
+
+		token, err := jwtWrapper.GenerateToken(ctx, "some-uuid", "some-email")
+		assert.NoError(t, err)
+
+		var validator auth.Validator = jwtWrapper
+		claims, err := validator.Validate(ctx, token)
+		assert.NoError(t, err)
+		assert.Equal(t, "some-uuid", claims.ID)
+	})
+
+	t.Run("should let the middleware use any validator backend", func(t *testing.T) {
+		validator := auth.ValidatorFunc(func(ctx context.Context, token string) (*auth.Claims, error) {
+			if token != "opaque-token" {
+				return nil, errors.New("unknown token")
+			}
+			return &auth.Claims{ID: "some-uuid"}, nil
+		})
+
+		var gotID string
+		handler := auth.Middleware(validator)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, err := auth.ClaimsFromContext(r.Context())
+			assert.NoError(t, err)
+			gotID = claims.ID
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Bearer opaque-token")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "some-uuid", gotID)
+
+		req = httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Bearer other-token")
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+}
@@ -0,0 +1,215 @@
+/*
+Package breaker provides a circuit breaker that stops calling a failing
+dependency for a while instead of hammering it.
+*/
+package breaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+)
+
+const (
+	// DefaultFailureThreshold is the number of consecutive failures opening the breaker.
+	DefaultFailureThreshold = 5
+
+	// DefaultResetTimeout is how long the breaker stays open before a trial call.
+	DefaultResetTimeout = 30 * time.Second
+)
+
+// ErrOpen is the error returned without calling the operation while the breaker is open.
+var ErrOpen = errors.New("circuit breaker is open")
+
+// State is the state of a Breaker.
+type State int
+
+const (
+	// StateClosed lets every call through, counting consecutive failures.
+	StateClosed State = iota
+	// StateOpen rejects every call with ErrOpen until the reset timeout elapses.
+	StateOpen
+	// StateHalfOpen lets a single trial call through: its success closes the
+	// breaker and its failure opens it again.
+	StateHalfOpen
+)
+
+// String returns the name of the state.
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// Option configures the Breaker built by New.
+type Option func(*Breaker)
+
+// WithFailureThreshold sets the number of consecutive failures opening the breaker.
+func WithFailureThreshold(threshold int) Option {
+	return func(b *Breaker) {
+		b.threshold = threshold
+	}
+}
+
+// WithResetTimeout sets how long the breaker stays open before a trial call.
+func WithResetTimeout(timeout time.Duration) Option {
+	return func(b *Breaker) {
+		b.resetTimeout = timeout
+	}
+}
+
+// WithClock sets the function returning the current time, mostly useful in tests.
+func WithClock(clock func() time.Time) Option {
+	return func(b *Breaker) {
+		b.clock = clock
+	}
+}
+
+// Breaker is a circuit breaker guarding calls to a dependency. It is safe for
+// concurrent use.
+type Breaker struct {
+	name         string
+	threshold    int
+	resetTimeout time.Duration
+	clock        func() time.Time
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	trial    bool
+}
+
+// New creates a closed Breaker for the named dependency, the name being logged
+// on state changes.
+func New(name string, opts ...Option) *Breaker {
+	b := &Breaker{
+		name:         name,
+		threshold:    DefaultFailureThreshold,
+		resetTimeout: DefaultResetTimeout,
+		clock:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	b.threshold = max(b.threshold, 1)
+	return b
+}
+
+// State returns the current state of the breaker.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.currentState()
+}
+
+// Do calls op unless the breaker is open, in which case it returns ErrOpen.
+// The outcome of op drives the breaker and its error is returned unchanged.
+// A panicking op counts as a failure and the panic is propagated. State
+// changes are logged through the context logger, if any.
+func (b *Breaker) Do(ctx context.Context, op func() error) (err error) {
+	if err := b.allow(ctx); err != nil {
+		return err
+	}
+
+	success := false
+	defer func() {
+		b.record(ctx, success)
+	}()
+
+	err = op()
+	success = err == nil
+	return err
+}
+
+// allow reports whether a call may proceed, moving an open breaker whose reset
+// timeout elapsed to half-open for a single trial call.
+func (b *Breaker) allow(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.currentState() {
+	case StateOpen:
+		return ErrOpen
+	case StateHalfOpen:
+		if b.trial {
+			return ErrOpen
+		}
+		if b.state == StateOpen {
+			b.transition(ctx, StateHalfOpen)
+		}
+		b.trial = true
+	}
+	return nil
+}
+
+// record updates the breaker with the outcome of a call.
+func (b *Breaker) record(ctx context.Context, success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateHalfOpen {
+		b.trial = false
+		if success {
+			b.transition(ctx, StateClosed)
+		} else {
+			b.transition(ctx, StateOpen)
+		}
+		return
+	}
+
+	if success {
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == StateClosed && b.failures >= b.threshold {
+		b.transition(ctx, StateOpen)
+	}
+}
+
+// currentState returns the state, reporting an open breaker whose reset
+// timeout elapsed as half-open. The caller must hold mu.
+func (b *Breaker) currentState() State {
+	if b.state == StateOpen && !b.clock().Before(b.openedAt.Add(b.resetTimeout)) {
+		return StateHalfOpen
+	}
+	return b.state
+}
+
+// transition moves the breaker to the given state and logs the change. The
+// caller must hold mu.
+func (b *Breaker) transition(ctx context.Context, to State) {
+	from := b.state
+	b.state = to
+	b.failures = 0
+	if to == StateOpen {
+		b.openedAt = b.clock()
+	}
+
+	logger, err := goctx.GetLoggerFromContext(ctx)
+	if err != nil {
+		return
+	}
+
+	fields := map[string]interface{}{
+		"breaker": b.name,
+		"from":    from.String(),
+		"to":      to.String(),
+	}
+	if to == StateOpen {
+		goctx.Warn(ctx, logger, "circuit breaker state changed", fields)
+		return
+	}
+	logger.Info(ctx, "circuit breaker state changed", fields)
+}
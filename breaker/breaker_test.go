@@ -0,0 +1,127 @@
+package breaker_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/junkd0g/go-microservice-commons/breaker"
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+	"github.com/junkd0g/go-microservice-commons/logger"
+)
+
+func Test_Breaker(t *testing.T) {
+	log, err := logger.NewLogger()
+	assert.NoError(t, err)
+	core, recorded := observer.New(zapcore.InfoLevel)
+	log.SetCore(core)
+	ctx := goctx.AddLoggerToContex(context.Background(), log)
+
+	now := time.Now()
+	b := breaker.New("users-api",
+		breaker.WithFailureThreshold(2),
+		breaker.WithResetTimeout(time.Minute),
+		breaker.WithClock(func() time.Time { return now }),
+	)
+
+	errUnavailable := errors.New("service unavailable")
+	calls := 0
+	fail := func() error { calls++; return errUnavailable }
+	succeed := func() error { calls++; return nil }
+
+	// Closed: failures are returned until the threshold is reached
+	assert.Equal(t, breaker.StateClosed, b.State())
+	assert.ErrorIs(t, b.Do(ctx, fail), errUnavailable)
+	assert.NoError(t, b.Do(ctx, succeed))
+	assert.ErrorIs(t, b.Do(ctx, fail), errUnavailable)
+	assert.Equal(t, breaker.StateClosed, b.State(), "a success resets the failure count")
+	assert.ErrorIs(t, b.Do(ctx, fail), errUnavailable)
+	assert.Equal(t, breaker.StateOpen, b.State())
+
+	// Open: calls are rejected without running the operation
+	calls = 0
+	assert.ErrorIs(t, b.Do(ctx, succeed), breaker.ErrOpen)
+	assert.Equal(t, 0, calls)
+
+	// Half-open: a failed trial opens the breaker again
+	now = now.Add(time.Minute)
+	assert.Equal(t, breaker.StateHalfOpen, b.State())
+	assert.ErrorIs(t, b.Do(ctx, fail), errUnavailable)
+	assert.Equal(t, breaker.StateOpen, b.State())
+	assert.ErrorIs(t, b.Do(ctx, succeed), breaker.ErrOpen)
+
+	// Half-open: a successful trial closes the breaker
+	now = now.Add(time.Minute)
+	assert.NoError(t, b.Do(ctx, succeed))
+	assert.Equal(t, breaker.StateClosed, b.State())
+
+	transitions := recorded.FilterMessage("circuit breaker state changed").All()
+	expected := [][2]string{
+		{"closed", "open"},
+		{"open", "half-open"},
+		{"half-open", "open"},
+		{"open", "half-open"},
+		{"half-open", "closed"},
+	}
+	assert.Len(t, transitions, len(expected))
+	for i, entry := range transitions {
+		fields := entry.ContextMap()
+		assert.Equal(t, "users-api", fields["breaker"])
+		assert.Equal(t, expected[i][0], fields["from"])
+		assert.Equal(t, expected[i][1], fields["to"])
+	}
+	assert.Equal(t, zapcore.WarnLevel, transitions[0].Level)
+}
+
+func Test_BreakerSingleTrial(t *testing.T) {
+	now := time.Now()
+	b := breaker.New("users-api",
+		breaker.WithFailureThreshold(1),
+		breaker.WithClock(func() time.Time { return now }),
+	)
+
+	assert.Error(t, b.Do(context.Background(), func() error { return errors.New("failed") }))
+	now = now.Add(breaker.DefaultResetTimeout)
+
+	// While the trial call runs, other calls are rejected
+	err := b.Do(context.Background(), func() error {
+		assert.ErrorIs(t, b.Do(context.Background(), func() error { return nil }), breaker.ErrOpen)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, breaker.StateClosed, b.State())
+}
+
+func Test_BreakerPanickingTrial(t *testing.T) {
+	now := time.Now()
+	b := breaker.New("users-api",
+		breaker.WithFailureThreshold(1),
+		breaker.WithClock(func() time.Time { return now }),
+	)
+
+	assert.Error(t, b.Do(context.Background(), func() error { return errors.New("failed") }))
+	now = now.Add(breaker.DefaultResetTimeout)
+
+	// A panicking trial is propagated and counts as a failure
+	assert.PanicsWithValue(t, "boom", func() {
+		_ = b.Do(context.Background(), func() error { panic("boom") })
+	})
+	assert.Equal(t, breaker.StateOpen, b.State())
+
+	// The next trial is allowed once the reset timeout elapses again
+	now = now.Add(breaker.DefaultResetTimeout)
+	assert.NoError(t, b.Do(context.Background(), func() error { return nil }))
+	assert.Equal(t, breaker.StateClosed, b.State())
+}
+
+func Test_StateString(t *testing.T) {
+	assert.Equal(t, "closed", breaker.StateClosed.String())
+	assert.Equal(t, "open", breaker.StateOpen.String())
+	assert.Equal(t, "half-open", breaker.StateHalfOpen.String())
+	assert.Equal(t, "unknown", breaker.State(42).String())
+}
@@ -0,0 +1,177 @@
+/*
+Package cache provides a generic, size-bounded LRU cache with optional
+expiry, meant to back the caches of validators and handlers such as token,
+key set or introspection caches.
+*/
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Option configures the LRU built by NewLRU.
+type Option func(*config)
+
+// config holds the settings collected from Options.
+type config struct {
+	ttl    time.Duration
+	clock  func() time.Time
+	onHit  func()
+	onMiss func()
+}
+
+// WithTTL sets the lifetime of the entries added with Set. Entries never
+// expire by default.
+func WithTTL(ttl time.Duration) Option {
+	return func(c *config) {
+		c.ttl = ttl
+	}
+}
+
+// WithClock sets the function returning the current time used for expiry,
+// mostly useful in tests.
+func WithClock(clock func() time.Time) Option {
+	return func(c *config) {
+		c.clock = clock
+	}
+}
+
+// WithMetrics sets the hooks called on every Get that finds a live entry
+// (onHit) or not (onMiss), e.g. to increment hit and miss counters. Either
+// may be nil. Hooks are called with the cache lock released.
+func WithMetrics(onHit, onMiss func()) Option {
+	return func(c *config) {
+		c.onHit = onHit
+		c.onMiss = onMiss
+	}
+}
+
+// LRU is a size-bounded cache evicting the least recently used entry when
+// full, regardless of expiry. Entries may also expire, in which case they are
+// dropped when looked up; until then they count towards the size. It is safe
+// for concurrent use.
+type LRU[K comparable, V any] struct {
+	mu      sync.Mutex
+	size    int
+	config  config
+	order   *list.List
+	entries map[K]*list.Element
+}
+
+// entry is a single cached value. A zero expiresAt means it never expires.
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+}
+
+// NewLRU creates an LRU holding at most size entries. A size of zero or less
+// makes a cache that stores nothing.
+func NewLRU[K comparable, V any](size int, opts ...Option) *LRU[K, V] {
+	c := config{clock: time.Now}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	return &LRU[K, V]{
+		size:    size,
+		config:  c,
+		order:   list.New(),
+		entries: make(map[K]*list.Element, max(size, 0)),
+	}
+}
+
+// Get returns the value cached for the key, if present and not expired, and
+// marks it as the most recently used.
+func (l *LRU[K, V]) Get(key K) (V, bool) {
+	value, ok := l.get(key)
+	if ok && l.config.onHit != nil {
+		l.config.onHit()
+	}
+	if !ok && l.config.onMiss != nil {
+		l.config.onMiss()
+	}
+	return value, ok
+}
+
+// get looks the key up under the lock.
+func (l *LRU[K, V]) get(key K) (V, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var zero V
+	element, ok := l.entries[key]
+	if !ok {
+		return zero, false
+	}
+
+	e := element.Value.(*entry[K, V])
+	if l.expired(e) {
+		l.remove(element)
+		return zero, false
+	}
+
+	l.order.MoveToFront(element)
+	return e.value, true
+}
+
+// Set caches the value for the key with the TTL set by WithTTL, replacing any
+// previous value, and evicts the least recently used entry when full.
+func (l *LRU[K, V]) Set(key K, value V) {
+	l.SetWithTTL(key, value, l.config.ttl)
+}
+
+// SetWithTTL caches the value for the key for ttl, e.g. until the expiry of
+// the cached token. A ttl of zero or less means the entry never expires.
+func (l *LRU[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	if l.size <= 0 {
+		return
+	}
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = l.config.clock().Add(ttl)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if element, ok := l.entries[key]; ok {
+		l.remove(element)
+	}
+	l.entries[key] = l.order.PushFront(&entry[K, V]{key: key, value: value, expiresAt: expiresAt})
+
+	for l.order.Len() > l.size {
+		l.remove(l.order.Back())
+	}
+}
+
+// Delete removes the key from the cache, if present.
+func (l *LRU[K, V]) Delete(key K) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if element, ok := l.entries[key]; ok {
+		l.remove(element)
+	}
+}
+
+// Len returns the number of cached entries, including expired ones not yet evicted.
+func (l *LRU[K, V]) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.order.Len()
+}
+
+// expired reports whether the entry has expired. The caller must hold the lock.
+func (l *LRU[K, V]) expired(e *entry[K, V]) bool {
+	return !e.expiresAt.IsZero() && !l.config.clock().Before(e.expiresAt)
+}
+
+// remove deletes the element from the cache. The caller must hold the lock.
+func (l *LRU[K, V]) remove(element *list.Element) {
+	l.order.Remove(element)
+	delete(l.entries, element.Value.(*entry[K, V]).key)
+}
@@ -0,0 +1,156 @@
+package cache_test
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/junkd0g/go-microservice-commons/cache"
+)
+
+func Test_LRUEvictionOrder(t *testing.T) {
+	lru := cache.NewLRU[string, int](2)
+
+	lru.Set("a", 1)
+	lru.Set("b", 2)
+
+	// Reading "a" makes "b" the least recently used
+	value, ok := lru.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+
+	lru.Set("c", 3)
+	assert.Equal(t, 2, lru.Len())
+
+	_, ok = lru.Get("b")
+	assert.False(t, ok)
+	value, ok = lru.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+	value, ok = lru.Get("c")
+	assert.True(t, ok)
+	assert.Equal(t, 3, value)
+
+	// Overwriting an entry refreshes it
+	lru.Set("a", 10)
+	lru.Set("d", 4)
+	_, ok = lru.Get("c")
+	assert.False(t, ok)
+	value, ok = lru.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 10, value)
+}
+
+func Test_LRUTTL(t *testing.T) {
+	now := time.Now()
+	lru := cache.NewLRU[string, string](10,
+		cache.WithTTL(time.Minute),
+		cache.WithClock(func() time.Time { return now }),
+	)
+
+	lru.Set("default", "value")
+	lru.SetWithTTL("short", "value", time.Second)
+	lru.SetWithTTL("forever", "value", 0)
+
+	now = now.Add(time.Second)
+	_, ok := lru.Get("short")
+	assert.False(t, ok)
+	_, ok = lru.Get("default")
+	assert.True(t, ok)
+
+	now = now.Add(time.Minute)
+	_, ok = lru.Get("default")
+	assert.False(t, ok)
+	_, ok = lru.Get("forever")
+	assert.True(t, ok)
+
+	// Expired entries are evicted when looked up
+	assert.Equal(t, 1, lru.Len())
+}
+
+func Test_LRUMetrics(t *testing.T) {
+	var hits, misses int
+	lru := cache.NewLRU[int, int](1, cache.WithMetrics(func() { hits++ }, func() { misses++ }))
+
+	lru.Set(1, 1)
+	lru.Get(1)
+	lru.Get(2)
+	lru.Set(2, 2)
+	lru.Get(1)
+
+	assert.Equal(t, 1, hits)
+	assert.Equal(t, 2, misses)
+}
+
+func Test_LRUDelete(t *testing.T) {
+	lru := cache.NewLRU[string, int](2)
+
+	lru.Set("a", 1)
+	lru.Delete("a")
+	lru.Delete("missing")
+
+	_, ok := lru.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, lru.Len())
+}
+
+func Test_LRUZeroSize(t *testing.T) {
+	lru := cache.NewLRU[string, int](0)
+
+	lru.Set("a", 1)
+
+	_, ok := lru.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, lru.Len())
+}
+
+func Test_LRUConcurrent(t *testing.T) {
+	lru := cache.NewLRU[int, int](50)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				lru.Set(i*1000+j, j)
+				lru.Get(i*1000 + j/2)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 50, lru.Len())
+}
+
+func BenchmarkLRUGet(b *testing.B) {
+	lru := cache.NewLRU[string, int](1000)
+	keys := make([]string, 1000)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+		lru.Set(keys[i], i)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		lru.Get(keys[i%len(keys)])
+	}
+}
+
+func BenchmarkLRUSet(b *testing.B) {
+	lru := cache.NewLRU[string, int](1000, cache.WithTTL(time.Minute))
+	keys := make([]string, 2000)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		lru.Set(keys[i%len(keys)], i)
+	}
+}
@@ -0,0 +1,159 @@
+package context
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Limits of the W3C baggage header parsed by WithBaggageHeader.
+const (
+	maxBaggageMembers = 180
+	maxBaggageBytes   = 8192
+)
+
+// contextKeyBaggage is the context key under which the baggage is stored.
+var contextKeyBaggage = contextKey("baggage")
+
+// SetBaggage returns a copy of ctx carrying the key/value in its baggage,
+// replacing any previous value of the key. Baggage is meant for the small
+// set of metadata propagated between services, such as a tenant tier or an
+// experiment, see BaggageHeader. The baggage of ctx is left untouched. Keys
+// must be HTTP tokens, as required by the W3C baggage header; ctx is returned
+// unchanged for other keys, such as "a=b,role", which would otherwise inject
+// members into the header.
+func SetBaggage(ctx context.Context, key, value string) context.Context {
+	if !isBaggageKey(key) {
+		return ctx
+	}
+	current := baggage(ctx)
+
+	// Copy on write, so contexts sharing the previous map never see the change
+	updated := make(map[string]string, len(current)+1)
+	for k, v := range current {
+		updated[k] = v
+	}
+	updated[key] = value
+
+	return context.WithValue(ctx, contextKeyBaggage, updated)
+}
+
+// GetBaggage retrieves the value of the key in the baggage of ctx.
+// The boolean is false when the key is not set.
+func GetBaggage(ctx context.Context, key string) (string, bool) {
+	value, ok := baggage(ctx)[key]
+	return value, ok
+}
+
+// Baggage returns a copy of all the key/values in the baggage of ctx.
+func Baggage(ctx context.Context) map[string]string {
+	current := baggage(ctx)
+	all := make(map[string]string, len(current))
+	for k, v := range current {
+		all[k] = v
+	}
+	return all
+}
+
+// BaggageHeader serializes the baggage of ctx into the W3C baggage header
+// format, e.g. "tier=gold,experiment=checkout-v2", with keys sorted and
+// values percent-encoded. Keys that are not HTTP tokens are skipped. It
+// returns "" when the baggage is empty.
+func BaggageHeader(ctx context.Context) string {
+	current := baggage(ctx)
+	keys := make([]string, 0, len(current))
+	for key := range current {
+		if isBaggageKey(key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	members := make([]string, 0, len(keys))
+	for _, key := range keys {
+		members = append(members, key+"="+escapeBaggageValue(current[key]))
+	}
+	return strings.Join(members, ",")
+}
+
+// WithBaggageHeader returns a copy of ctx with the key/values of a W3C baggage
+// header, as written by BaggageHeader, added to its baggage. Member
+// properties are dropped, malformed members and members whose key is not an
+// HTTP token are skipped, and headers beyond the W3C limits of 8192 bytes or
+// 180 members are truncated.
+func WithBaggageHeader(ctx context.Context, header string) context.Context {
+	if len(header) > maxBaggageBytes {
+		header = header[:maxBaggageBytes]
+	}
+
+	current := baggage(ctx)
+	updated := make(map[string]string, len(current))
+	for k, v := range current {
+		updated[k] = v
+	}
+
+	for i, member := range strings.Split(header, ",") {
+		if i == maxBaggageMembers {
+			break
+		}
+
+		// Properties such as ";metadata" are not supported
+		member, _, _ = strings.Cut(member, ";")
+		key, value, ok := strings.Cut(member, "=")
+		key = strings.TrimSpace(key)
+		if !ok || !isBaggageKey(key) {
+			continue
+		}
+		value, err := url.PathUnescape(strings.TrimSpace(value))
+		if err != nil {
+			continue
+		}
+		updated[key] = value
+	}
+
+	return context.WithValue(ctx, contextKeyBaggage, updated)
+}
+
+// baggage returns the baggage map of ctx, which must not be modified.
+func baggage(ctx context.Context) map[string]string {
+	current, _ := ctx.Value(contextKeyBaggage).(map[string]string)
+	return current
+}
+
+// isBaggageKey reports whether the key is a non-empty HTTP token (RFC 9110),
+// the syntax of W3C baggage keys.
+func isBaggageKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		case strings.IndexByte("!#$%&'*+-.^_`|~", c) >= 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// escapeBaggageValue percent-encodes the bytes of value not allowed unencoded
+// in a W3C baggage value: controls, whitespace, '"', ',', ';', '\' and '%'.
+func escapeBaggageValue(value string) string {
+	const hex = "0123456789ABCDEF"
+
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c <= ' ' || c >= 0x7f || c == '"' || c == ',' || c == ';' || c == '\\' || c == '%' {
+			b.WriteByte('%')
+			b.WriteByte(hex[c>>4])
+			b.WriteByte(hex[c&0x0f])
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
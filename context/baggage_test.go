@@ -0,0 +1,95 @@
+package context_test
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+)
+
+func Test_Baggage(t *testing.T) {
+	t.Run("Set successfully a value and retrieve it", func(t *testing.T) {
+		ctx := goctx.SetBaggage(context.Background(), "tier", "gold")
+
+		value, ok := goctx.GetBaggage(ctx, "tier")
+		assert.True(t, ok)
+		assert.Equal(t, "gold", value)
+	})
+
+	t.Run("No value found", func(t *testing.T) {
+		value, ok := goctx.GetBaggage(context.Background(), "tier")
+		assert.False(t, ok)
+		assert.Empty(t, value)
+		assert.Empty(t, goctx.Baggage(context.Background()))
+	})
+
+	t.Run("Override a value without changing the parent context", func(t *testing.T) {
+		parent := goctx.SetBaggage(context.Background(), "tier", "gold")
+		parent = goctx.SetBaggage(parent, "region", "eu")
+		child := goctx.SetBaggage(parent, "tier", "silver")
+
+		value, _ := goctx.GetBaggage(child, "tier")
+		assert.Equal(t, "silver", value)
+		value, _ = goctx.GetBaggage(parent, "tier")
+		assert.Equal(t, "gold", value)
+
+		assert.Equal(t, map[string]string{"tier": "silver", "region": "eu"}, goctx.Baggage(child))
+	})
+
+	t.Run("Keys that are not tokens are rejected", func(t *testing.T) {
+		ctx := goctx.SetBaggage(context.Background(), "tier", "gold")
+
+		for _, key := range []string{"a=b,role", "", "has space", "semi;colon"} {
+			assert.Equal(t, ctx, goctx.SetBaggage(ctx, key, "admin"))
+		}
+		assert.Equal(t, "tier=gold", goctx.BaggageHeader(ctx))
+	})
+
+	t.Run("Baggage returns a copy", func(t *testing.T) {
+		ctx := goctx.SetBaggage(context.Background(), "tier", "gold")
+
+		goctx.Baggage(ctx)["tier"] = "silver"
+
+		value, _ := goctx.GetBaggage(ctx, "tier")
+		assert.Equal(t, "gold", value)
+	})
+}
+
+func Test_BaggageHeader(t *testing.T) {
+	t.Run("Round-trip the baggage through the header", func(t *testing.T) {
+		ctx := goctx.SetBaggage(context.Background(), "tier", "gold")
+		ctx = goctx.SetBaggage(ctx, "experiment", "checkout v2; beta,50%")
+
+		header := goctx.BaggageHeader(ctx)
+		assert.Equal(t, "experiment=checkout%20v2%3B%20beta%2C50%25,tier=gold", header)
+
+		parsed := goctx.WithBaggageHeader(context.Background(), header)
+		assert.Equal(t, goctx.Baggage(ctx), goctx.Baggage(parsed))
+	})
+
+	t.Run("Empty baggage serializes to an empty header", func(t *testing.T) {
+		assert.Empty(t, goctx.BaggageHeader(context.Background()))
+	})
+
+	t.Run("Parse a header skipping properties and malformed members", func(t *testing.T) {
+		ctx := goctx.SetBaggage(context.Background(), "tier", "bronze")
+		ctx = goctx.WithBaggageHeader(ctx, " tier = gold;ttl=60 , invalid, =empty-key,bad=%zz,user=a%2Bb")
+
+		assert.Equal(t, map[string]string{"tier": "gold", "user": "a+b"}, goctx.Baggage(ctx))
+	})
+
+	t.Run("Parse at most 180 members", func(t *testing.T) {
+		members := make([]string, 200)
+		for i := range members {
+			members[i] = "k" + strconv.Itoa(i) + "=v"
+		}
+
+		ctx := goctx.WithBaggageHeader(context.Background(), strings.Join(members, ","))
+
+		assert.Len(t, goctx.Baggage(ctx), 180)
+	})
+}
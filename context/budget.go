@@ -0,0 +1,41 @@
+package context
+
+import (
+	"context"
+	"time"
+)
+
+// RemainingBudget returns how much time is left before the context deadline.
+// The boolean is false when the context has no deadline. The returned duration
+// is negative once the deadline has passed.
+func RemainingBudget(ctx context.Context) (time.Duration, bool) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return 0, false
+	}
+	return time.Until(deadline), true
+}
+
+// CheckBudget is a checkpoint that logs a warning through the context logger
+// when the remaining budget at the named checkpoint has dropped below threshold.
+// It returns the remaining budget and false when the context has no deadline,
+// in which case nothing is logged.
+func CheckBudget(ctx context.Context, checkpoint string, threshold time.Duration) (time.Duration, bool) {
+	remaining, ok := RemainingBudget(ctx)
+	if !ok || remaining >= threshold {
+		return remaining, ok
+	}
+
+	logger, err := GetLoggerFromContext(ctx)
+	if err != nil {
+		return remaining, ok
+	}
+
+	Warn(ctx, logger, "request time budget nearly exhausted", map[string]interface{}{
+		"checkpoint": checkpoint,
+		"remaining":  remaining,
+		"threshold":  threshold,
+	})
+
+	return remaining, ok
+}
@@ -0,0 +1,89 @@
+package context_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+)
+
+func Test_RemainingBudget(t *testing.T) {
+	t.Run("Return the remaining budget of a context with a deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		remaining, ok := goctx.RemainingBudget(ctx)
+		assert.True(t, ok)
+		assert.InDelta(t, time.Minute, remaining, float64(time.Second))
+	})
+
+	t.Run("Report no budget for a context without a deadline", func(t *testing.T) {
+		remaining, ok := goctx.RemainingBudget(context.Background())
+		assert.False(t, ok)
+		assert.Zero(t, remaining)
+	})
+}
+
+func Test_CheckBudget(t *testing.T) {
+	t.Run("Log a warning when the budget drops below the threshold", func(t *testing.T) {
+		log := &recordingLogger{}
+		ctx := goctx.AddLoggerToContex(context.Background(), log)
+		ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+
+		_, ok := goctx.CheckBudget(ctx, "before-db-call", time.Second)
+		assert.True(t, ok)
+
+		entries := log.Entries()
+		assert.Len(t, entries, 1)
+		assert.Equal(t, "before-db-call", entries[0].fields["checkpoint"])
+	})
+
+	t.Run("Stay silent while the budget is sufficient", func(t *testing.T) {
+		log := &recordingLogger{}
+		ctx := goctx.AddLoggerToContex(context.Background(), log)
+		ctx, cancel := context.WithTimeout(ctx, time.Minute)
+		defer cancel()
+
+		_, ok := goctx.CheckBudget(ctx, "before-db-call", time.Second)
+		assert.True(t, ok)
+		assert.Empty(t, log.Entries())
+	})
+
+	t.Run("Stay silent without a deadline", func(t *testing.T) {
+		log := &recordingLogger{}
+		ctx := goctx.AddLoggerToContex(context.Background(), log)
+
+		_, ok := goctx.CheckBudget(ctx, "before-db-call", time.Second)
+		assert.False(t, ok)
+		assert.Empty(t, log.Entries())
+	})
+}
+
+// warnRecordingLogger is a recordingLogger that also supports the warn level.
+type warnRecordingLogger struct {
+	recordingLogger
+}
+
+func (l *warnRecordingLogger) Warn(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.record("warn", msg, fields)
+}
+
+func Test_Warn(t *testing.T) {
+	t.Run("Use the warn level when supported", func(t *testing.T) {
+		log := &warnRecordingLogger{}
+		goctx.Warn(context.Background(), log, "careful")
+
+		assert.Equal(t, "warn", log.Entries()[0].level)
+	})
+
+	t.Run("Fall back to info otherwise", func(t *testing.T) {
+		log := &recordingLogger{}
+		goctx.Warn(context.Background(), log, "careful")
+
+		assert.Equal(t, "info", log.Entries()[0].level)
+	})
+}
@@ -0,0 +1,32 @@
+package context
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// WithLoggedCancel returns a copy of ctx with a cancel function that records
+// why the context was cancelled. The first call to cancel logs "context
+// cancelled" with the reason and the lifetime of the context, measured from
+// this call, through the context logger, if present, then cancels the context
+// with the reason as its cause, available through context.Cause. Later calls
+// do nothing.
+func WithLoggedCancel(ctx context.Context, reason string) (context.Context, context.CancelFunc) {
+	start := time.Now()
+	ctx, cancel := context.WithCancelCause(ctx)
+
+	var once sync.Once
+	return ctx, func() {
+		once.Do(func() {
+			if logger, err := GetLoggerFromContext(ctx); err == nil {
+				logger.Info(ctx, "context cancelled", map[string]interface{}{
+					"reason":   reason,
+					"lifetime": time.Since(start),
+				})
+			}
+			cancel(errors.New(reason))
+		})
+	}
+}
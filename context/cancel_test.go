@@ -0,0 +1,39 @@
+package context_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+)
+
+func Test_WithLoggedCancel(t *testing.T) {
+	t.Run("Log the reason and lifetime before cancelling", func(t *testing.T) {
+		log := &recordingLogger{}
+		ctx := goctx.AddLoggerToContex(context.Background(), log)
+
+		ctx, cancel := goctx.WithLoggedCancel(ctx, "client disconnected")
+		time.Sleep(5 * time.Millisecond)
+		cancel()
+		cancel()
+
+		assert.ErrorIs(t, ctx.Err(), context.Canceled)
+		assert.EqualError(t, context.Cause(ctx), "client disconnected")
+
+		assert.Len(t, log.entries, 1)
+		assert.Equal(t, "context cancelled", log.entries[0].msg)
+		assert.Equal(t, "client disconnected", log.entries[0].fields["reason"])
+		assert.GreaterOrEqual(t, log.entries[0].fields["lifetime"], 5*time.Millisecond)
+	})
+
+	t.Run("Cancel without a logger", func(t *testing.T) {
+		ctx, cancel := goctx.WithLoggedCancel(context.Background(), "shutdown")
+		cancel()
+
+		assert.ErrorIs(t, ctx.Err(), context.Canceled)
+		assert.EqualError(t, context.Cause(ctx), "shutdown")
+	})
+}
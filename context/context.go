@@ -23,6 +23,10 @@ func (c contextKey) String() string {
 type MutableFields struct {
 	sync.RWMutex
 	fields []map[string]interface{}
+	id     string
+	// userIndex is the position in fields of the map set by SetUserFields plus
+	// one, or zero when there is none.
+	userIndex int
 }
 
 // NewMutableFields initializes a new instance of MutableFields.
@@ -37,6 +41,35 @@ func (mf *MutableFields) AddField(field map[string]interface{}) {
 	mf.fields = append(mf.fields, field)
 }
 
+// SetFields safely replaces all the fields with the given ones in a single
+// step, e.g. at a phase boundary such as an identity upgrade. Every previously
+// added field is discarded; readers see either the old or the new set, never
+// a mix of both.
+func (mf *MutableFields) SetFields(fields []map[string]interface{}) {
+	mf.Lock()
+	defer mf.Unlock()
+	mf.fields = append([]map[string]interface{}(nil), fields...)
+	mf.userIndex = 0
+}
+
+// SetUserFields safely sets the fields describing the authenticated user,
+// replacing the ones of the previous call instead of adding duplicates, e.g.
+// when a long-lived stream re-authenticates. Other fields are kept. Slices
+// previously returned by GetFields are left untouched.
+func (mf *MutableFields) SetUserFields(user map[string]interface{}) {
+	mf.Lock()
+	defer mf.Unlock()
+
+	fields := make([]map[string]interface{}, 0, len(mf.fields)+1)
+	for i, field := range mf.fields {
+		if i != mf.userIndex-1 {
+			fields = append(fields, field)
+		}
+	}
+	mf.fields = append(fields, user)
+	mf.userIndex = len(mf.fields)
+}
+
 // GetFields safely retrieves all fields from the MutableFields.
 func (mf *MutableFields) GetFields() []map[string]interface{} {
 	mf.RLock()
@@ -44,12 +77,58 @@ func (mf *MutableFields) GetFields() []map[string]interface{} {
 	return mf.fields
 }
 
+// Snapshot returns the fields flattened into a single map, later fields
+// overriding earlier ones with the same key. Unlike GetFields the map is an
+// independent copy taken under the read lock, safe to mutate or serialize,
+// e.g. from a debug endpoint.
+func (mf *MutableFields) Snapshot() map[string]interface{} {
+	mf.RLock()
+	defer mf.RUnlock()
+	return flattenFields(mf.fields)
+}
+
+// ID returns an identifier of the fields, generated with NewRequestID the first
+// time it is called and reused afterwards. Contexts sharing the MutableFields,
+// such as those derived from a request context, share the ID, correlating their
+// logs even when no request ID was set.
+func (mf *MutableFields) ID() string {
+	mf.RLock()
+	id := mf.id
+	mf.RUnlock()
+	if id != "" {
+		return id
+	}
+
+	mf.Lock()
+	defer mf.Unlock()
+	if mf.id == "" {
+		mf.id = NewRequestID()
+	}
+	return mf.id
+}
+
 // Logger provides an interface for logging functionalities.
 type Logger interface {
 	Info(ctx context.Context, msg string, fields ...map[string]interface{})
 	Error(ctx context.Context, msg string, fields ...map[string]interface{})
 }
 
+// WarnLogger is implemented by loggers that support the warn level, such as
+// the logger package's Logger.
+type WarnLogger interface {
+	Warn(ctx context.Context, msg string, fields ...map[string]interface{})
+}
+
+// Warn logs a warning through the logger when it implements WarnLogger and
+// falls back to Info otherwise.
+func Warn(ctx context.Context, logger Logger, msg string, fields ...map[string]interface{}) {
+	if warnLogger, ok := logger.(WarnLogger); ok {
+		warnLogger.Warn(ctx, msg, fields...)
+		return
+	}
+	logger.Info(ctx, msg, fields...)
+}
+
 // Predefined context keys for storing logger and fields in the context.
 var (
 	contextKeyLogger       = contextKey("logger")
@@ -0,0 +1,124 @@
+package context
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// redactedValue replaces the values of sensitive fields in DebugDump.
+const redactedValue = "[REDACTED]"
+
+// SensitiveFieldMarkers are the substrings marking a field key as sensitive,
+// matched case-insensitively. DebugDump redacts the fields whose key contains
+// one of them, and other packages, such as the middleware redacting logged
+// bodies, share the list.
+var SensitiveFieldMarkers = []string{"password", "secret", "token", "authorization", "cookie", "email"}
+
+// DebugDump collects the values this package manages in the context into a
+// map suitable for logging when diagnosing issues: whether a logger is set
+// (and its type), the logger fields, the fields ID if assigned, the
+// authenticated user, the baggage, the request ID, job run ID, tenant,
+// locale, current span and deadline. Absent values are left out. Values are
+// strings or string slices, so every logger can encode them: flags are
+// "true" or "false", the deadline is in RFC 3339 format and the fields and
+// baggage are sorted "key=value" pairs. Values whose key looks sensitive, such
+// as "password" or "email", are redacted.
+//
+// Only the keys known to this package are reported; values stored by other
+// packages, e.g. the claims of the auth package beyond the user they set with
+// WithUser, are not. DebugDump is a debugging aid and is not meant for hot
+// paths.
+func DebugDump(ctx context.Context) map[string]interface{} {
+	dump := map[string]interface{}{}
+
+	logger, err := GetLoggerFromContext(ctx)
+	dump["logger"] = strconv.FormatBool(err == nil)
+	if err == nil {
+		dump["logger_type"] = fmt.Sprintf("%T", logger)
+	}
+
+	if mutableFields, ok := MutableFieldsFromContext(ctx); ok {
+		mutableFields.RLock()
+		fields, id := flattenFields(mutableFields.fields), mutableFields.id
+		mutableFields.RUnlock()
+
+		dump["fields"] = redactedPairs(fields)
+		if id != "" {
+			dump["fields_id"] = id
+		}
+	} else if fields := GetFieldsFromContext(ctx); len(fields) > 0 {
+		dump["fields"] = redactedPairs(flattenFields(fields))
+	}
+
+	if user, ok := UserFromContext(ctx); ok {
+		dump["user_id"] = user.ID
+		if user.Email != "" {
+			dump["user_email"] = redactedValue
+		}
+	}
+	if current := baggage(ctx); len(current) > 0 {
+		values := make(map[string]interface{}, len(current))
+		for key, value := range current {
+			values[key] = value
+		}
+		dump["baggage"] = redactedPairs(values)
+	}
+
+	if requestID, ok := RequestIDFromContext(ctx); ok {
+		dump["request_id"] = requestID
+	}
+	if runID, ok := RunIDFromContext(ctx); ok {
+		dump["run_id"] = runID
+	}
+	if tenant, ok := TenantFromContext(ctx); ok {
+		dump["tenant"] = tenant
+	}
+	if locale, ok := LocaleFromContext(ctx); ok {
+		dump["locale"] = locale
+	}
+
+	if current, ok := ctx.Value(contextKeySpan).(*span); ok {
+		dump["span"] = current.name
+		if parents := current.parentNames(); len(parents) > 0 {
+			dump["span_parents"] = parents
+		}
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		dump["deadline"] = deadline.Format(time.RFC3339Nano)
+	}
+	if err := ctx.Err(); err != nil {
+		dump["error"] = err.Error()
+	}
+
+	return dump
+}
+
+// redactedPairs returns the values as sorted "key=value" pairs, with the
+// values of the sensitive keys redacted.
+func redactedPairs(values map[string]interface{}) []string {
+	pairs := make([]string, 0, len(values))
+	for key, value := range values {
+		if isSensitiveField(key) {
+			value = redactedValue
+		}
+		pairs = append(pairs, fmt.Sprintf("%s=%v", key, value))
+	}
+	sort.Strings(pairs)
+	return pairs
+}
+
+// isSensitiveField reports whether the field key looks like it holds sensitive data.
+func isSensitiveField(key string) bool {
+	key = strings.ToLower(key)
+	for _, marker := range SensitiveFieldMarkers {
+		if strings.Contains(key, marker) {
+			return true
+		}
+	}
+	return false
+}
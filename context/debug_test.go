@@ -0,0 +1,101 @@
+package context_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+	"github.com/junkd0g/go-microservice-commons/logger"
+)
+
+func Test_DebugDump(t *testing.T) {
+	t.Run("should report the known values", func(t *testing.T) {
+		deadline := time.Now().Add(time.Minute)
+		ctx, cancel := context.WithDeadline(context.Background(), deadline)
+		defer cancel()
+
+		ctx = goctx.AddLoggerToContex(ctx, &recordingLogger{})
+		ctx = goctx.WithRequestID(ctx, "abc-123")
+		ctx = goctx.WithTenant(ctx, "acme")
+		ctx = goctx.WithLocale(ctx, "en-GB")
+		ctx = goctx.WithUser(ctx, goctx.User{ID: "some-uuid", Email: "alice@example.com"})
+		ctx = goctx.SetBaggage(ctx, "tier", "gold")
+		ctx = goctx.SetBaggage(ctx, "session_token", "secret-value")
+		ctx, _ = goctx.StartSpan(ctx, "handler")
+		ctx, _ = goctx.StartSpan(ctx, "query")
+
+		mutableFields := goctx.NewMutableFields()
+		mutableFields.AddField(map[string]interface{}{"user_id": "some-uuid", "Email": "alice@example.com"})
+		mutableFields.AddField(map[string]interface{}{"access_token": "secret-value"})
+		ctx = goctx.WithMutableFields(ctx, mutableFields)
+
+		assert.Equal(t, map[string]interface{}{
+			"logger":       "true",
+			"logger_type":  "*context_test.recordingLogger",
+			"fields":       []string{"Email=[REDACTED]", "access_token=[REDACTED]", "user_id=some-uuid"},
+			"user_id":      "some-uuid",
+			"user_email":   "[REDACTED]",
+			"baggage":      []string{"session_token=[REDACTED]", "tier=gold"},
+			"request_id":   "abc-123",
+			"tenant":       "acme",
+			"locale":       "en-GB",
+			"span":         "query",
+			"span_parents": []string{"handler"},
+			"deadline":     deadline.Format(time.RFC3339Nano),
+		}, goctx.DebugDump(ctx))
+
+		// The dump is a copy, the fields are left untouched
+		assert.Equal(t, "alice@example.com", mutableFields.Snapshot()["Email"])
+	})
+
+	t.Run("should report the fields ID only once assigned", func(t *testing.T) {
+		mutableFields := goctx.NewMutableFields()
+		ctx := goctx.WithMutableFields(context.Background(), mutableFields)
+
+		assert.NotContains(t, goctx.DebugDump(ctx), "fields_id")
+
+		id := mutableFields.ID()
+		assert.Equal(t, id, goctx.DebugDump(ctx)["fields_id"])
+	})
+
+	t.Run("should report an empty context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		assert.Equal(t, map[string]interface{}{
+			"logger": "false",
+			"error":  "context canceled",
+		}, goctx.DebugDump(ctx))
+	})
+
+	t.Run("should be fully logged by the logger", func(t *testing.T) {
+		log, err := logger.NewLogger()
+		assert.NoError(t, err)
+		core, recorded := observer.New(zapcore.InfoLevel)
+		log.SetCore(core)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		ctx = goctx.AddLoggerToContex(ctx, log)
+		ctx = goctx.WithUser(ctx, goctx.User{ID: "some-uuid"})
+		ctx = goctx.SetBaggage(ctx, "tier", "gold")
+		ctx = goctx.AddFieldsToContext(ctx, []map[string]interface{}{{"attempt": 2}})
+
+		dump := goctx.DebugDump(ctx)
+		log.Info(context.Background(), "context dump", dump)
+
+		entries := recorded.All()
+		assert.Len(t, entries, 1)
+		fields := entries[0].ContextMap()
+		for key := range dump {
+			assert.Contains(t, fields, key)
+		}
+		assert.Equal(t, "true", fields["logger"])
+		assert.Equal(t, []interface{}{"attempt=2"}, fields["fields"])
+	})
+}
@@ -0,0 +1,78 @@
+package context
+
+import "context"
+
+// WithMutableFields associates a MutableFields with a context so the logger
+// includes its fields in every entry logged with that context.
+func WithMutableFields(ctx context.Context, fields *MutableFields) context.Context {
+	return context.WithValue(ctx, ContextKeyLoggerFields, fields)
+}
+
+// MutableFieldsFromContext retrieves the MutableFields associated with a context.
+func MutableFieldsFromContext(ctx context.Context) (*MutableFields, bool) {
+	fields, ok := ctx.Value(ContextKeyLoggerFields).(*MutableFields)
+	return fields, ok && fields != nil
+}
+
+// SetUserFields sets the user fields of the MutableFields in the context,
+// typically derived from validated claims with auth.JwtClaim.SafeMap, replacing
+// those set by a previous call. It reports whether the context had
+// MutableFields to update.
+func SetUserFields(ctx context.Context, claims map[string]interface{}) bool {
+	fields, ok := MutableFieldsFromContext(ctx)
+	if !ok {
+		return false
+	}
+	fields.SetUserFields(claims)
+	return true
+}
+
+// MergeFields copies the logger fields of src into the MutableFields of dst,
+// attaching a new MutableFields to dst if it has none. Keys are deduplicated:
+// when both contexts define a key, the value from src wins. The returned
+// context must be used in place of dst.
+func MergeFields(dst, src context.Context) context.Context {
+	srcFields, ok := MutableFieldsFromContext(src)
+	if !ok {
+		return dst
+	}
+	incoming := flattenFields(srcFields.GetFields())
+
+	dstFields, ok := MutableFieldsFromContext(dst)
+	if !ok {
+		dstFields = NewMutableFields()
+		dst = WithMutableFields(dst, dstFields)
+	}
+
+	if dstFields == srcFields {
+		return dst
+	}
+
+	dstFields.Lock()
+	defer dstFields.Unlock()
+
+	merged := flattenFields(dstFields.fields)
+	for k, v := range incoming {
+		merged[k] = v
+	}
+	dstFields.fields = []map[string]interface{}{merged}
+	dstFields.userIndex = 0
+
+	return dst
+}
+
+// flattenFields merges the field maps into a new map, later maps overriding earlier ones.
+func flattenFields(fields []map[string]interface{}) map[string]interface{} {
+	size := 0
+	for _, field := range fields {
+		size += len(field)
+	}
+
+	flat := make(map[string]interface{}, size)
+	for _, field := range fields {
+		for k, v := range field {
+			flat[k] = v
+		}
+	}
+	return flat
+}
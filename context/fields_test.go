@@ -0,0 +1,231 @@
+package context_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+)
+
+// contextWithFields returns a context carrying a MutableFields seeded with the given fields.
+func contextWithFields(fields ...map[string]interface{}) context.Context {
+	mutableFields := goctx.NewMutableFields()
+	for _, field := range fields {
+		mutableFields.AddField(field)
+	}
+	return goctx.WithMutableFields(context.Background(), mutableFields)
+}
+
+// flatFields returns the logger fields of the context merged into a single map.
+func flatFields(t *testing.T, ctx context.Context) map[string]interface{} {
+	t.Helper()
+
+	mutableFields, ok := goctx.MutableFieldsFromContext(ctx)
+	assert.True(t, ok)
+
+	flat := map[string]interface{}{}
+	for _, field := range mutableFields.GetFields() {
+		for k, v := range field {
+			flat[k] = v
+		}
+	}
+	return flat
+}
+
+func Test_MutableFieldsContext(t *testing.T) {
+	t.Run("Add successfully mutable fields and retrieve them", func(t *testing.T) {
+		mutableFields := goctx.NewMutableFields()
+		ctx := goctx.WithMutableFields(context.Background(), mutableFields)
+
+		got, ok := goctx.MutableFieldsFromContext(ctx)
+		assert.True(t, ok)
+		assert.Same(t, mutableFields, got)
+	})
+
+	t.Run("No mutable fields found", func(t *testing.T) {
+		got, ok := goctx.MutableFieldsFromContext(context.Background())
+		assert.False(t, ok)
+		assert.Nil(t, got)
+	})
+}
+
+func Test_Snapshot(t *testing.T) {
+	t.Run("Flatten and deduplicate fields", func(t *testing.T) {
+		mutableFields := goctx.NewMutableFields()
+		mutableFields.AddField(map[string]interface{}{"request_id": "abc-123", "step": "auth"})
+		mutableFields.AddField(map[string]interface{}{"step": "load"})
+
+		assert.Equal(t, map[string]interface{}{"request_id": "abc-123", "step": "load"}, mutableFields.Snapshot())
+	})
+
+	t.Run("Return an independent copy", func(t *testing.T) {
+		field := map[string]interface{}{"request_id": "abc-123"}
+		mutableFields := goctx.NewMutableFields()
+		mutableFields.AddField(field)
+
+		snapshot := mutableFields.Snapshot()
+		snapshot["request_id"] = "changed"
+		snapshot["extra"] = "value"
+		mutableFields.AddField(map[string]interface{}{"later": "value"})
+
+		assert.Equal(t, map[string]interface{}{"request_id": "abc-123"}, field)
+		assert.Len(t, mutableFields.GetFields(), 2)
+		assert.NotContains(t, snapshot, "later")
+	})
+
+	t.Run("Empty fields", func(t *testing.T) {
+		assert.Empty(t, goctx.NewMutableFields().Snapshot())
+	})
+}
+
+func Test_SetFields(t *testing.T) {
+	t.Run("Replace all the fields", func(t *testing.T) {
+		mutableFields := goctx.NewMutableFields()
+		mutableFields.AddField(map[string]interface{}{"user": "anonymous", "step": "auth"})
+
+		mutableFields.SetFields([]map[string]interface{}{{"user": "some-uuid"}})
+
+		assert.Equal(t, map[string]interface{}{"user": "some-uuid"}, mutableFields.Snapshot())
+	})
+
+	t.Run("Swap under read load", func(t *testing.T) {
+		anonymous := []map[string]interface{}{{"user": "anonymous"}, {"phase": "before"}}
+		authenticated := []map[string]interface{}{{"user": "some-uuid"}, {"phase": "after"}}
+
+		mutableFields := goctx.NewMutableFields()
+		mutableFields.SetFields(anonymous)
+
+		stop := make(chan struct{})
+		var readers sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			readers.Add(1)
+			go func() {
+				defer readers.Done()
+				for {
+					select {
+					case <-stop:
+						return
+					default:
+					}
+
+					// Readers must never observe a mix of both sets
+					snapshot := mutableFields.Snapshot()
+					if snapshot["user"] == "anonymous" {
+						assert.Equal(t, "before", snapshot["phase"])
+					} else {
+						assert.Equal(t, "after", snapshot["phase"])
+					}
+				}
+			}()
+		}
+
+		for i := 0; i < 1000; i++ {
+			if i%2 == 0 {
+				mutableFields.SetFields(authenticated)
+			} else {
+				mutableFields.SetFields(anonymous)
+			}
+		}
+		close(stop)
+		readers.Wait()
+
+		assert.Equal(t, map[string]interface{}{"user": "anonymous", "phase": "before"}, mutableFields.Snapshot())
+	})
+}
+
+func Test_SetUserFields(t *testing.T) {
+	t.Run("Replace the user fields on re-authentication", func(t *testing.T) {
+		ctx := contextWithFields(map[string]interface{}{"stream_id": "s-1"})
+
+		assert.True(t, goctx.SetUserFields(ctx, map[string]interface{}{"id": "user-1", "scopes": "read"}))
+		mutableFields, _ := goctx.MutableFieldsFromContext(ctx)
+		mutableFields.AddField(map[string]interface{}{"messages": 10})
+
+		// Re-authenticated as another user without the scopes claim
+		assert.True(t, goctx.SetUserFields(ctx, map[string]interface{}{"id": "user-2"}))
+
+		assert.Len(t, mutableFields.GetFields(), 3)
+		assert.Equal(t, map[string]interface{}{"stream_id": "s-1", "messages": 10, "id": "user-2"}, flatFields(t, ctx))
+	})
+
+	t.Run("Leave previously returned fields untouched", func(t *testing.T) {
+		ctx := contextWithFields()
+		goctx.SetUserFields(ctx, map[string]interface{}{"id": "user-1"})
+		mutableFields, _ := goctx.MutableFieldsFromContext(ctx)
+		before := mutableFields.GetFields()
+
+		goctx.SetUserFields(ctx, map[string]interface{}{"id": "user-2"})
+
+		assert.Equal(t, []map[string]interface{}{{"id": "user-1"}}, before)
+	})
+
+	t.Run("Report a context without fields", func(t *testing.T) {
+		assert.False(t, goctx.SetUserFields(context.Background(), map[string]interface{}{"id": "user-1"}))
+	})
+}
+
+func Test_MutableFieldsID(t *testing.T) {
+	t.Run("Assign the ID lazily and reuse it", func(t *testing.T) {
+		mutableFields := goctx.NewMutableFields()
+
+		id := mutableFields.ID()
+		assert.NotEmpty(t, id)
+		assert.Equal(t, id, mutableFields.ID())
+		assert.NotEqual(t, id, goctx.NewMutableFields().ID())
+	})
+
+	t.Run("Keep the ID when copying values", func(t *testing.T) {
+		src := contextWithFields(map[string]interface{}{"request_id": "abc-123"})
+		srcFields, _ := goctx.MutableFieldsFromContext(src)
+		id := srcFields.ID()
+
+		dst := goctx.CopyValues(context.Background(), src)
+		dstFields, _ := goctx.MutableFieldsFromContext(dst)
+
+		assert.Equal(t, id, dstFields.ID())
+	})
+}
+
+func Test_MergeFields(t *testing.T) {
+	t.Run("Merge disjoint field sets", func(t *testing.T) {
+		dst := contextWithFields(map[string]interface{}{"request_id": "abc-123"})
+		src := contextWithFields(map[string]interface{}{"job": "fan-in"})
+
+		merged := goctx.MergeFields(dst, src)
+
+		assert.Equal(t, map[string]interface{}{"request_id": "abc-123", "job": "fan-in"}, flatFields(t, merged))
+	})
+
+	t.Run("Merge overlapping field sets with src taking precedence", func(t *testing.T) {
+		dst := contextWithFields(
+			map[string]interface{}{"request_id": "abc-123", "step": "first"},
+			map[string]interface{}{"step": "second"},
+		)
+		src := contextWithFields(map[string]interface{}{"step": "third", "job": "fan-in"})
+
+		merged := goctx.MergeFields(dst, src)
+
+		mutableFields, _ := goctx.MutableFieldsFromContext(merged)
+		assert.Len(t, mutableFields.GetFields(), 1)
+		assert.Equal(t, map[string]interface{}{"request_id": "abc-123", "step": "third", "job": "fan-in"}, flatFields(t, merged))
+	})
+
+	t.Run("Create mutable fields on dst when missing", func(t *testing.T) {
+		src := contextWithFields(map[string]interface{}{"job": "fan-in"})
+
+		merged := goctx.MergeFields(context.Background(), src)
+
+		assert.Equal(t, map[string]interface{}{"job": "fan-in"}, flatFields(t, merged))
+	})
+
+	t.Run("Leave dst untouched when src has no fields", func(t *testing.T) {
+		dst := context.Background()
+
+		merged := goctx.MergeFields(dst, context.Background())
+
+		assert.Equal(t, dst, merged)
+	})
+}
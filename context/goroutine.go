@@ -0,0 +1,109 @@
+package context
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+// Detach returns a context that keeps all the values of ctx, such as the
+// logger and logger fields, but is never cancelled and has no deadline.
+// It is meant for work that must outlive the request that started it.
+func Detach(ctx context.Context) context.Context {
+	return context.WithoutCancel(ctx)
+}
+
+// CopyValues returns dst carrying the request scoped values of src, for when a
+// worker context is built from context.Background() instead of derived from the
+// request. The copied values are:
+//
+//   - the logger set with AddLoggerToContex
+//   - the logger fields stored under ContextKeyLoggerFields; a MutableFields is
+//     copied into a new one, keeping its ID if assigned, so fields added by the
+//     worker don't leak back
+//   - the request ID set with WithRequestID
+//
+// Values missing from src are left untouched in dst.
+func CopyValues(dst, src context.Context) context.Context {
+	if logger, err := GetLoggerFromContext(src); err == nil {
+		dst = AddLoggerToContex(dst, logger)
+	}
+
+	switch fields := src.Value(ContextKeyLoggerFields).(type) {
+	case *MutableFields:
+		copied := NewMutableFields()
+		fields.RLock()
+		copied.id = fields.id
+		fields.RUnlock()
+		if snapshot := fields.Snapshot(); len(snapshot) > 0 {
+			copied.AddField(snapshot)
+		}
+		dst = WithMutableFields(dst, copied)
+	case []map[string]interface{}:
+		dst = AddFieldsToContext(dst, fields)
+	}
+
+	if requestID, ok := RequestIDFromContext(src); ok {
+		dst = WithRequestID(dst, requestID)
+	}
+
+	return dst
+}
+
+// Go runs fn in a new goroutine with a detached copy of ctx. A panic in fn is
+// recovered and logged through the context logger instead of crashing the
+// process. The returned channel is closed once fn has returned.
+func Go(ctx context.Context, fn func(ctx context.Context)) <-chan struct{} {
+	done := make(chan struct{})
+	detached := Detach(ctx)
+
+	go func() {
+		defer close(done)
+		defer recoverAndLog(detached)
+
+		fn(detached)
+	}()
+
+	return done
+}
+
+// Group runs functions in goroutines through Go and waits for all of them to finish.
+// The zero value is ready to use.
+type Group struct {
+	wg sync.WaitGroup
+}
+
+// Go runs fn through the package level Go and tracks it in the group.
+func (g *Group) Go(ctx context.Context, fn func(ctx context.Context)) {
+	g.wg.Add(1)
+	done := Go(ctx, fn)
+
+	go func() {
+		<-done
+		g.wg.Done()
+	}()
+}
+
+// Wait blocks until all functions started with the group have returned.
+func (g *Group) Wait() {
+	g.wg.Wait()
+}
+
+// recoverAndLog recovers from a panic and logs it via the context logger, if present.
+func recoverAndLog(ctx context.Context) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	logger, err := GetLoggerFromContext(ctx)
+	if err != nil {
+		return
+	}
+
+	logger.Error(ctx, "recovered from panic in goroutine", map[string]interface{}{
+		"panic": fmt.Sprint(r),
+		"stack": string(debug.Stack()),
+	})
+}
@@ -0,0 +1,176 @@
+package context_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+)
+
+// logEntry is a log call captured by recordingLogger.
+type logEntry struct {
+	level  string
+	msg    string
+	fields map[string]interface{}
+}
+
+// recordingLogger is a goctx.Logger that records every call for assertions.
+type recordingLogger struct {
+	mu      sync.Mutex
+	entries []logEntry
+}
+
+func (l *recordingLogger) Info(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.record("info", msg, fields)
+}
+
+func (l *recordingLogger) Error(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.record("error", msg, fields)
+}
+
+func (l *recordingLogger) record(level, msg string, fields []map[string]interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	merged := map[string]interface{}{}
+	for _, field := range fields {
+		for k, v := range field {
+			merged[k] = v
+		}
+	}
+	l.entries = append(l.entries, logEntry{level: level, msg: msg, fields: merged})
+}
+
+func (l *recordingLogger) Entries() []logEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]logEntry(nil), l.entries...)
+}
+
+func Test_Detach(t *testing.T) {
+	log := &recordingLogger{}
+	ctx, cancel := context.WithCancel(goctx.AddLoggerToContex(context.Background(), log))
+	cancel()
+
+	detached := goctx.Detach(ctx)
+	assert.NoError(t, detached.Err())
+
+	loggerToTest, err := goctx.GetLoggerFromContext(detached)
+	assert.NoError(t, err)
+	assert.Equal(t, log, loggerToTest)
+}
+
+func Test_CopyValues(t *testing.T) {
+	t.Run("Copy the logger, fields and request ID", func(t *testing.T) {
+		log := &recordingLogger{}
+		src := contextWithFields(map[string]interface{}{"request_id": "abc-123"})
+		src = goctx.AddLoggerToContex(src, log)
+		src = goctx.WithRequestID(src, "abc-123")
+
+		dst := goctx.CopyValues(context.Background(), src)
+
+		loggerToTest, err := goctx.GetLoggerFromContext(dst)
+		assert.NoError(t, err)
+		assert.Equal(t, log, loggerToTest)
+
+		requestID, ok := goctx.RequestIDFromContext(dst)
+		assert.True(t, ok)
+		assert.Equal(t, "abc-123", requestID)
+
+		assert.Equal(t, map[string]interface{}{"request_id": "abc-123"}, flatFields(t, dst))
+
+		// Fields added by the worker don't leak back into the request
+		dstFields, _ := goctx.MutableFieldsFromContext(dst)
+		dstFields.AddField(map[string]interface{}{"job": "export"})
+		assert.NotContains(t, flatFields(t, src), "job")
+	})
+
+	t.Run("Keep dst values missing from src", func(t *testing.T) {
+		dst := goctx.WithRequestID(context.Background(), "dst-id")
+
+		dst = goctx.CopyValues(dst, context.Background())
+
+		requestID, ok := goctx.RequestIDFromContext(dst)
+		assert.True(t, ok)
+		assert.Equal(t, "dst-id", requestID)
+		_, err := goctx.GetLoggerFromContext(dst)
+		assert.ErrorIs(t, err, goctx.ErrLoggerNotFound)
+	})
+}
+
+func Test_Go(t *testing.T) {
+	t.Run("should run the function with the context values", func(t *testing.T) {
+		ctx := goctx.WithRequestID(context.Background(), "some-request-id")
+
+		var got string
+		done := goctx.Go(ctx, func(ctx context.Context) {
+			got, _ = goctx.RequestIDFromContext(ctx)
+		})
+		<-done
+
+		assert.Equal(t, "some-request-id", got)
+	})
+
+	t.Run("should recover and log a panic", func(t *testing.T) {
+		log := &recordingLogger{}
+		ctx := goctx.AddLoggerToContex(context.Background(), log)
+
+		done := goctx.Go(ctx, func(ctx context.Context) {
+			panic("boom")
+		})
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("goroutine did not finish")
+		}
+
+		entries := log.Entries()
+		assert.Len(t, entries, 1)
+		assert.Equal(t, "error", entries[0].level)
+		assert.Equal(t, "boom", entries[0].fields["panic"])
+		assert.NotEmpty(t, entries[0].fields["stack"])
+	})
+
+	t.Run("should keep running after the parent context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		var err error
+		done := goctx.Go(ctx, func(ctx context.Context) {
+			cancel()
+			err = ctx.Err()
+		})
+		<-done
+
+		assert.NoError(t, err)
+	})
+}
+
+func Test_Group(t *testing.T) {
+	log := &recordingLogger{}
+	ctx := goctx.AddLoggerToContex(context.Background(), log)
+
+	var (
+		g     goctx.Group
+		mu    sync.Mutex
+		count int
+	)
+	for i := 0; i < 5; i++ {
+		g.Go(ctx, func(ctx context.Context) {
+			mu.Lock()
+			count++
+			mu.Unlock()
+		})
+	}
+	g.Go(ctx, func(ctx context.Context) {
+		panic("boom")
+	})
+	g.Wait()
+
+	assert.Equal(t, 5, count)
+	assert.Len(t, log.Entries(), 1)
+}
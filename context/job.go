@@ -0,0 +1,38 @@
+package context
+
+import "context"
+
+// Keys of the fields seeded by NewJobContext.
+const (
+	FieldJob   = "job"
+	FieldRunID = "run_id"
+)
+
+// contextKeyRunID is the context key under which the run ID of a job is stored.
+var contextKeyRunID = contextKey("runID")
+
+// NewJobContext prepares the context of a background job or cron run, which
+// has no inbound request to seed it: it attaches the logger and a fresh
+// MutableFields seeded with the job name as "job" and a run ID generated with
+// NewRequestID as "run_id", so every entry logged during the run is tied to
+// it. The run ID is also retrievable with RunIDFromContext.
+func NewJobContext(ctx context.Context, jobName string, log Logger) context.Context {
+	runID := NewRequestID()
+
+	fields := NewMutableFields()
+	fields.AddField(map[string]interface{}{
+		FieldJob:   jobName,
+		FieldRunID: runID,
+	})
+
+	ctx = context.WithValue(ctx, contextKeyRunID, runID)
+	ctx = WithMutableFields(ctx, fields)
+	return AddLoggerToContex(ctx, log)
+}
+
+// RunIDFromContext retrieves the run ID of the job prepared by NewJobContext.
+// The boolean is false when the context is not a job context.
+func RunIDFromContext(ctx context.Context) (string, bool) {
+	runID, ok := ctx.Value(contextKeyRunID).(string)
+	return runID, ok && runID != ""
+}
@@ -0,0 +1,58 @@
+package context_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+)
+
+func Test_NewJobContext(t *testing.T) {
+	t.Run("should attach the logger and the job fields", func(t *testing.T) {
+		log := &recordingLogger{}
+		ctx := goctx.NewJobContext(context.Background(), "cleanup-sessions", log)
+
+		got, err := goctx.GetLoggerFromContext(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, log, got)
+
+		runID, ok := goctx.RunIDFromContext(ctx)
+		assert.True(t, ok)
+		assert.NotEmpty(t, runID)
+
+		assert.Equal(t, map[string]interface{}{
+			goctx.FieldJob:   "cleanup-sessions",
+			goctx.FieldRunID: runID,
+		}, flatFields(t, ctx))
+	})
+
+	t.Run("should give every run its own ID and fields", func(t *testing.T) {
+		first := goctx.NewJobContext(context.Background(), "cleanup-sessions", &recordingLogger{})
+		second := goctx.NewJobContext(context.Background(), "cleanup-sessions", &recordingLogger{})
+
+		firstRunID, _ := goctx.RunIDFromContext(first)
+		secondRunID, _ := goctx.RunIDFromContext(second)
+		assert.NotEqual(t, firstRunID, secondRunID)
+
+		firstFields, _ := goctx.MutableFieldsFromContext(first)
+		firstFields.AddField(map[string]interface{}{"processed": 10})
+		assert.NotContains(t, flatFields(t, second), "processed")
+	})
+
+	t.Run("should keep the values of the parent context", func(t *testing.T) {
+		parent, cancel := context.WithCancel(goctx.WithTenant(context.Background(), "acme"))
+		ctx := goctx.NewJobContext(parent, "cleanup-sessions", &recordingLogger{})
+		cancel()
+
+		tenant, _ := goctx.TenantFromContext(ctx)
+		assert.Equal(t, "acme", tenant)
+		assert.Error(t, ctx.Err())
+	})
+
+	t.Run("should not find a run ID outside a job", func(t *testing.T) {
+		_, ok := goctx.RunIDFromContext(context.Background())
+		assert.False(t, ok)
+	})
+}
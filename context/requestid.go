@@ -0,0 +1,40 @@
+package context
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+)
+
+// contextKeyRequestID is the context key under which the request ID is stored.
+var contextKeyRequestID = contextKey("requestID")
+
+// WithRequestID associates a request ID with a context.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, contextKeyRequestID, requestID)
+}
+
+// RequestIDFromContext retrieves the request ID associated with a context.
+// The boolean is false when no request ID has been set.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	requestID, ok := ctx.Value(contextKeyRequestID).(string)
+	if !ok || requestID == "" {
+		return "", false
+	}
+	return requestID, true
+}
+
+// NewRequestID generates a random RFC 4122 version 4 UUID suitable for use as a request ID.
+func NewRequestID() string {
+	var b [16]byte
+	// crypto/rand.Read only fails if the OS entropy source is unavailable,
+	// in which case there is nothing sensible left to do.
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(fmt.Sprintf("failed to generate request id: %v", err))
+	}
+
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // variant 10
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
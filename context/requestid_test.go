@@ -0,0 +1,38 @@
+package context_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+)
+
+func Test_RequestID(t *testing.T) {
+	t.Run("Add successfully a request id and retrieve it", func(t *testing.T) {
+		ctx := goctx.WithRequestID(context.Background(), "some-request-id")
+
+		requestID, ok := goctx.RequestIDFromContext(ctx)
+		assert.True(t, ok)
+		assert.Equal(t, "some-request-id", requestID)
+	})
+
+	t.Run("No request id found", func(t *testing.T) {
+		requestID, ok := goctx.RequestIDFromContext(context.Background())
+		assert.False(t, ok)
+		assert.Empty(t, requestID)
+	})
+
+	t.Run("Generate a uuid v4 request id", func(t *testing.T) {
+		uuidV4 := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+		first := goctx.NewRequestID()
+		second := goctx.NewRequestID()
+
+		assert.Regexp(t, uuidV4, first)
+		assert.Regexp(t, uuidV4, second)
+		assert.NotEqual(t, first, second)
+	})
+}
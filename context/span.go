@@ -0,0 +1,61 @@
+package context
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// contextKeySpan is the context key under which the current span is stored.
+var contextKeySpan = contextKey("span")
+
+// span is a named, timed operation within a request.
+type span struct {
+	name   string
+	parent *span
+}
+
+// parentNames returns the names of the span's ancestors, outermost first.
+func (s *span) parentNames() []string {
+	var names []string
+	for p := s.parent; p != nil; p = p.parent {
+		names = append([]string{p.name}, names...)
+	}
+	return names
+}
+
+// StartSpan starts a lightweight in-process span. Calling the returned func
+// logs the span name and elapsed duration through the context logger at Info
+// level; spans started from the returned context are nested and log their
+// parents' names as "span_parents". The finish func only logs the first time
+// it is called, further calls are no-ops.
+func StartSpan(ctx context.Context, name string) (context.Context, func()) {
+	parent, _ := ctx.Value(contextKeySpan).(*span)
+	current := &span{
+		name:   name,
+		parent: parent,
+	}
+	ctx = context.WithValue(ctx, contextKeySpan, current)
+
+	start := time.Now()
+	var once sync.Once
+
+	return ctx, func() {
+		once.Do(func() {
+			logger, err := GetLoggerFromContext(ctx)
+			if err != nil {
+				return
+			}
+
+			fields := map[string]interface{}{
+				"span":     current.name,
+				"duration": time.Since(start),
+			}
+			if parents := current.parentNames(); len(parents) > 0 {
+				fields["span_parents"] = parents
+			}
+
+			logger.Info(ctx, "span finished", fields)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package context_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+)
+
+func Test_StartSpan(t *testing.T) {
+	t.Run("should log the span name and duration", func(t *testing.T) {
+		log := &recordingLogger{}
+		ctx := goctx.AddLoggerToContex(context.Background(), log)
+
+		_, finish := goctx.StartSpan(ctx, "load-user")
+		finish()
+
+		entries := log.Entries()
+		assert.Len(t, entries, 1)
+		assert.Equal(t, "info", entries[0].level)
+		assert.Equal(t, "load-user", entries[0].fields["span"])
+		assert.IsType(t, time.Duration(0), entries[0].fields["duration"])
+		assert.NotContains(t, entries[0].fields, "span_parents")
+	})
+
+	t.Run("should include parent span names for nested spans", func(t *testing.T) {
+		log := &recordingLogger{}
+		ctx := goctx.AddLoggerToContex(context.Background(), log)
+
+		ctx, finishOuter := goctx.StartSpan(ctx, "handler")
+		ctx, finishMiddle := goctx.StartSpan(ctx, "service")
+		_, finishInner := goctx.StartSpan(ctx, "query")
+		finishInner()
+		finishMiddle()
+		finishOuter()
+
+		entries := log.Entries()
+		assert.Len(t, entries, 3)
+		assert.Equal(t, "query", entries[0].fields["span"])
+		assert.Equal(t, []string{"handler", "service"}, entries[0].fields["span_parents"])
+		assert.Equal(t, []string{"handler"}, entries[1].fields["span_parents"])
+		assert.NotContains(t, entries[2].fields, "span_parents")
+	})
+
+	t.Run("should only log once when finished multiple times", func(t *testing.T) {
+		log := &recordingLogger{}
+		ctx := goctx.AddLoggerToContex(context.Background(), log)
+
+		_, finish := goctx.StartSpan(ctx, "load-user")
+		finish()
+		finish()
+
+		assert.Len(t, log.Entries(), 1)
+	})
+
+	t.Run("should not fail without a logger", func(t *testing.T) {
+		_, finish := goctx.StartSpan(context.Background(), "load-user")
+		assert.NotPanics(t, finish)
+	})
+}
@@ -0,0 +1,76 @@
+package context
+
+import "context"
+
+// Key is a typed context key. Keys are compared by identity, so two keys
+// created with NewKey never collide, even when they share the same name.
+type Key[T any] struct {
+	name string
+}
+
+// NewKey creates a new typed context key. The name is only used for debugging.
+func NewKey[T any](name string) *Key[T] {
+	return &Key[T]{name: name}
+}
+
+// String returns the name of the key.
+func (k *Key[T]) String() string {
+	return k.name
+}
+
+// WithValue associates a typed value with a context under the given key.
+func WithValue[T any](ctx context.Context, key *Key[T], value T) context.Context {
+	return context.WithValue(ctx, key, value)
+}
+
+// Value retrieves the typed value stored under the given key.
+// The boolean is false when no value of that type has been set.
+func Value[T any](ctx context.Context, key *Key[T]) (T, bool) {
+	value, ok := ctx.Value(key).(T)
+	return value, ok
+}
+
+// User identifies the authenticated user of a request.
+type User struct {
+	ID    string
+	Email string
+}
+
+// Predefined typed keys for common request-scoped values.
+var (
+	contextKeyTenant = NewKey[string]("tenant")
+	contextKeyLocale = NewKey[string]("locale")
+	contextKeyUser   = NewKey[User]("user")
+)
+
+// WithTenant associates a tenant ID with a context.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return WithValue(ctx, contextKeyTenant, tenant)
+}
+
+// TenantFromContext retrieves the tenant ID associated with a context.
+func TenantFromContext(ctx context.Context) (string, bool) {
+	return Value(ctx, contextKeyTenant)
+}
+
+// WithLocale associates a locale (e.g. "en-GB") with a context.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return WithValue(ctx, contextKeyLocale, locale)
+}
+
+// LocaleFromContext retrieves the locale associated with a context.
+func LocaleFromContext(ctx context.Context) (string, bool) {
+	return Value(ctx, contextKeyLocale)
+}
+
+// WithUser associates the authenticated user with a context. auth.WithClaims
+// sets it from the validated claims, so packages such as the logger can tag
+// entries with the user without depending on auth.
+func WithUser(ctx context.Context, user User) context.Context {
+	return WithValue(ctx, contextKeyUser, user)
+}
+
+// UserFromContext retrieves the authenticated user associated with a context.
+func UserFromContext(ctx context.Context) (User, bool) {
+	return Value(ctx, contextKeyUser)
+}
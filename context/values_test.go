@@ -0,0 +1,74 @@
+package context_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+)
+
+func Test_Value(t *testing.T) {
+	t.Run("Add successfully a typed value and retrieve it", func(t *testing.T) {
+		key := goctx.NewKey[[]string]("flags")
+		ctx := goctx.WithValue(context.Background(), key, []string{"beta"})
+
+		flags, ok := goctx.Value(ctx, key)
+		assert.True(t, ok)
+		assert.Equal(t, []string{"beta"}, flags)
+	})
+
+	t.Run("Keys with the same name do not collide", func(t *testing.T) {
+		first := goctx.NewKey[string]("name")
+		second := goctx.NewKey[string]("name")
+		ctx := goctx.WithValue(context.Background(), first, "first")
+
+		value, ok := goctx.Value(ctx, second)
+		assert.False(t, ok)
+		assert.Empty(t, value)
+	})
+}
+
+func Test_Tenant(t *testing.T) {
+	t.Run("Add successfully a tenant and retrieve it", func(t *testing.T) {
+		ctx := goctx.WithTenant(context.Background(), "tenant-123")
+
+		tenant, ok := goctx.TenantFromContext(ctx)
+		assert.True(t, ok)
+		assert.Equal(t, "tenant-123", tenant)
+	})
+
+	t.Run("No tenant found", func(t *testing.T) {
+		tenant, ok := goctx.TenantFromContext(context.Background())
+		assert.False(t, ok)
+		assert.Empty(t, tenant)
+	})
+}
+
+func Test_Locale(t *testing.T) {
+	ctx := goctx.WithLocale(context.Background(), "en-GB")
+
+	locale, ok := goctx.LocaleFromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, "en-GB", locale)
+
+	_, ok = goctx.TenantFromContext(ctx)
+	assert.False(t, ok)
+}
+
+func Test_User(t *testing.T) {
+	t.Run("Add successfully a user and retrieve it", func(t *testing.T) {
+		ctx := goctx.WithUser(context.Background(), goctx.User{ID: "some-uuid", Email: "some-email"})
+
+		user, ok := goctx.UserFromContext(ctx)
+		assert.True(t, ok)
+		assert.Equal(t, goctx.User{ID: "some-uuid", Email: "some-email"}, user)
+	})
+
+	t.Run("No user found", func(t *testing.T) {
+		user, ok := goctx.UserFromContext(context.Background())
+		assert.False(t, ok)
+		assert.Empty(t, user)
+	})
+}
@@ -0,0 +1,131 @@
+/*
+Package events provides a minimal in-process publish/subscribe bus decoupling
+the code emitting domain events from their subscribers, such as metrics or
+audit logging.
+*/
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+)
+
+// Handler handles the payload of an event published on a topic it subscribed to.
+type Handler func(ctx context.Context, payload interface{}) error
+
+// Option configures the Bus built by NewBus.
+type Option func(*Bus)
+
+// WithAsync makes Publish return immediately, each subscriber handling the
+// event in its own goroutine with a detached context (see goctx.Detach), so
+// slow subscribers never delay the publisher. Use Wait to let in-flight
+// handlers finish, e.g. on shutdown.
+func WithAsync() Option {
+	return func(b *Bus) {
+		b.async = true
+	}
+}
+
+// Bus dispatches published events to the handlers subscribed to their topic.
+// It is safe for concurrent use.
+type Bus struct {
+	async bool
+
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+
+	inFlight sync.WaitGroup
+}
+
+// NewBus creates a new Bus, synchronous unless WithAsync is given.
+func NewBus(opts ...Option) *Bus {
+	b := &Bus{
+		handlers: make(map[string][]Handler),
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// Subscribe registers handler for the events published on topic. Handlers are
+// called in the order they subscribed.
+func (b *Bus) Subscribe(topic string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[topic] = append(b.handlers[topic], handler)
+}
+
+// Publish dispatches the payload to the handlers subscribed to topic. Handlers
+// are isolated from each other: a handler returning an error or panicking does
+// not prevent the others from running. Failures are logged through the context
+// logger, if present, with the topic and error.
+//
+// A synchronous bus calls the handlers one after the other and returns their
+// errors joined with errors.Join, a panic being returned as an error. An
+// asynchronous bus returns nil right away.
+func (b *Bus) Publish(ctx context.Context, topic string, payload interface{}) error {
+	b.mu.RLock()
+	handlers := b.handlers[topic]
+	b.mu.RUnlock()
+
+	if b.async {
+		detached := goctx.Detach(ctx)
+		for _, handler := range handlers {
+			b.inFlight.Add(1)
+			go func(handler Handler) {
+				defer b.inFlight.Done()
+				_ = dispatch(detached, topic, handler, payload)
+			}(handler)
+		}
+		return nil
+	}
+
+	var errs []error
+	for _, handler := range handlers {
+		errs = append(errs, dispatch(ctx, topic, handler, payload))
+	}
+	return errors.Join(errs...)
+}
+
+// Wait blocks until the handlers started by an asynchronous bus have returned.
+func (b *Bus) Wait() {
+	b.inFlight.Wait()
+}
+
+// dispatch calls the handler, recovering from a panic, and logs its failure.
+func dispatch(ctx context.Context, topic string, handler Handler, payload interface{}) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("event handler panicked: %v", r)
+			logFailure(ctx, "recovered from panic in event handler", map[string]interface{}{
+				"topic": topic,
+				"panic": fmt.Sprint(r),
+				"stack": string(debug.Stack()),
+			})
+		}
+	}()
+
+	if err := handler(ctx, payload); err != nil {
+		logFailure(ctx, "event handler failed", map[string]interface{}{
+			"topic": topic,
+			"error": err.Error(),
+		})
+		return err
+	}
+	return nil
+}
+
+// logFailure logs a handler failure through the context logger, if present.
+func logFailure(ctx context.Context, msg string, fields map[string]interface{}) {
+	logger, err := goctx.GetLoggerFromContext(ctx)
+	if err != nil {
+		return
+	}
+	logger.Error(ctx, msg, fields)
+}
@@ -0,0 +1,119 @@
+package events_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+	"github.com/junkd0g/go-microservice-commons/events"
+	"github.com/junkd0g/go-microservice-commons/logger"
+)
+
+// newLoggedContext returns a context carrying a logger whose entries are recorded.
+func newLoggedContext(t *testing.T) (context.Context, *observer.ObservedLogs) {
+	t.Helper()
+
+	log, err := logger.NewLogger()
+	assert.NoError(t, err)
+
+	core, recorded := observer.New(zapcore.InfoLevel)
+	log.SetCore(core)
+	return goctx.AddLoggerToContex(context.Background(), log), recorded
+}
+
+func Test_BusSync(t *testing.T) {
+	t.Run("should dispatch to every subscriber of the topic", func(t *testing.T) {
+		bus := events.NewBus()
+
+		var got []string
+		bus.Subscribe("user.created", func(ctx context.Context, payload interface{}) error {
+			got = append(got, "metrics:"+payload.(string))
+			return nil
+		})
+		bus.Subscribe("user.created", func(ctx context.Context, payload interface{}) error {
+			got = append(got, "audit:"+payload.(string))
+			return nil
+		})
+		bus.Subscribe("user.deleted", func(ctx context.Context, payload interface{}) error {
+			got = append(got, "deleted:"+payload.(string))
+			return nil
+		})
+
+		err := bus.Publish(context.Background(), "user.created", "some-uuid")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"metrics:some-uuid", "audit:some-uuid"}, got)
+	})
+
+	t.Run("should isolate failing and panicking subscribers", func(t *testing.T) {
+		ctx, recorded := newLoggedContext(t)
+		bus := events.NewBus()
+
+		errAudit := errors.New("audit store unavailable")
+		called := false
+		bus.Subscribe("user.created", func(ctx context.Context, payload interface{}) error {
+			return errAudit
+		})
+		bus.Subscribe("user.created", func(ctx context.Context, payload interface{}) error {
+			panic("boom")
+		})
+		bus.Subscribe("user.created", func(ctx context.Context, payload interface{}) error {
+			called = true
+			return nil
+		})
+
+		err := bus.Publish(ctx, "user.created", "some-uuid")
+
+		assert.ErrorIs(t, err, errAudit)
+		assert.ErrorContains(t, err, "boom")
+		assert.True(t, called)
+
+		assert.Equal(t, 1, recorded.FilterMessage("event handler failed").Len())
+		assert.Equal(t, 1, recorded.FilterMessage("recovered from panic in event handler").Len())
+		assert.Equal(t, "user.created", recorded.All()[0].ContextMap()["topic"])
+	})
+
+	t.Run("should ignore topics without subscribers", func(t *testing.T) {
+		assert.NoError(t, events.NewBus().Publish(context.Background(), "unknown", nil))
+	})
+}
+
+func Test_BusAsync(t *testing.T) {
+	ctx, recorded := newLoggedContext(t)
+	ctx, cancel := context.WithCancel(ctx)
+	bus := events.NewBus(events.WithAsync())
+
+	var (
+		mu  sync.Mutex
+		got []string
+	)
+	release := make(chan struct{})
+	bus.Subscribe("order.paid", func(ctx context.Context, payload interface{}) error {
+		<-release
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, payload.(string))
+		// The handler context is detached from the publisher's
+		return ctx.Err()
+	})
+	bus.Subscribe("order.paid", func(ctx context.Context, payload interface{}) error {
+		panic("boom")
+	})
+
+	err := bus.Publish(ctx, "order.paid", "order-1")
+	assert.NoError(t, err)
+
+	cancel()
+	close(release)
+	bus.Wait()
+
+	assert.Equal(t, []string{"order-1"}, got)
+	assert.Equal(t, 0, recorded.FilterMessage("event handler failed").Len())
+	assert.Equal(t, 1, recorded.FilterMessage("recovered from panic in event handler").Len())
+}
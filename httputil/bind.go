@@ -0,0 +1,94 @@
+package httputil
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/junkd0g/go-microservice-commons/apperr"
+)
+
+// DefaultMaxBodyBytes is the maximum size of a request body decoded by Bind.
+const DefaultMaxBodyBytes = 1 << 20
+
+// Error codes of the errors returned by Bind.
+const (
+	CodeMalformedBody = "malformed_body"
+	CodeInvalidBody   = "invalid_body"
+	CodeBodyTooLarge  = "body_too_large"
+)
+
+// Errors found in the chain of the errors returned by Bind.
+var (
+	ErrMalformedBody = errors.New("malformed request body")
+	ErrInvalidBody   = errors.New("invalid request body")
+	ErrBodyTooLarge  = errors.New("request body too large")
+)
+
+// Validator is implemented by request bodies checking their own content.
+type Validator interface {
+	Validate() error
+}
+
+// ContextValidator is implemented by request bodies whose checks need the
+// request context, e.g. to look up existing records.
+type ContextValidator interface {
+	Validate(ctx context.Context) error
+}
+
+// Bind decodes the JSON body of r, up to DefaultMaxBodyBytes, into a T and
+// validates it when T implements Validator or ContextValidator. The returned
+// errors are *apperr.Error values ready for WriteError:
+//   - a 400 with CodeMalformedBody wrapping ErrMalformedBody when the body is
+//     empty, is not valid JSON or does not match T;
+//   - a 400 with CodeInvalidBody wrapping ErrInvalidBody and the validation
+//     error, whose message is returned to the client, when validation fails;
+//   - a 413 with CodeBodyTooLarge wrapping ErrBodyTooLarge when the body is
+//     larger than the limit.
+func Bind[T any](ctx context.Context, r *http.Request) (T, error) {
+	var v, zero T
+
+	dec := json.NewDecoder(http.MaxBytesReader(nil, r.Body, DefaultMaxBodyBytes))
+	if err := dec.Decode(&v); err != nil {
+		return zero, decodeError(err)
+	}
+	if err := dec.Decode(&struct{}{}); err != io.EOF {
+		return zero, decodeError(errors.New("unexpected data after the JSON value"))
+	}
+
+	// Check the pointer too so Validate methods with a pointer receiver are found
+	if err := validate(ctx, v, &v); err != nil {
+		return zero, apperr.New(http.StatusBadRequest, CodeInvalidBody, err.Error()).
+			WithCause(fmt.Errorf("%w: %w", ErrInvalidBody, err))
+	}
+
+	return v, nil
+}
+
+// validate runs the validation implemented by the first of the values
+// implementing Validator or ContextValidator, if any.
+func validate(ctx context.Context, values ...interface{}) error {
+	for _, v := range values {
+		switch validator := v.(type) {
+		case ContextValidator:
+			return validator.Validate(ctx)
+		case Validator:
+			return validator.Validate()
+		}
+	}
+	return nil
+}
+
+// decodeError maps an error decoding the body to the error returned by Bind.
+func decodeError(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return apperr.New(http.StatusRequestEntityTooLarge, CodeBodyTooLarge, ErrBodyTooLarge.Error()).
+			WithCause(fmt.Errorf("%w: %w", ErrBodyTooLarge, err))
+	}
+	return apperr.New(http.StatusBadRequest, CodeMalformedBody, ErrMalformedBody.Error()).
+		WithCause(fmt.Errorf("%w: %w", ErrMalformedBody, err))
+}
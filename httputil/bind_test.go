@@ -0,0 +1,122 @@
+package httputil_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/junkd0g/go-microservice-commons/apperr"
+	"github.com/junkd0g/go-microservice-commons/httputil"
+)
+
+type createUser struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+var errNameRequired = errors.New("name is required")
+
+func (c *createUser) Validate() error {
+	if c.Name == "" {
+		return errNameRequired
+	}
+	return nil
+}
+
+func Test_Bind(t *testing.T) {
+	type testCase struct {
+		name           string
+		body           string
+		expected       createUser
+		expectedError  error
+		expectedStatus int
+		expectedCode   string
+	}
+
+	testCases := []testCase{
+		{
+			name:     "valid payload",
+			body:     `{"name":"some-name","email":"some-email"}`,
+			expected: createUser{Name: "some-name", Email: "some-email"},
+		},
+		{
+			name:           "malformed payload",
+			body:           `{"name":`,
+			expectedError:  httputil.ErrMalformedBody,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   httputil.CodeMalformedBody,
+		},
+		{
+			name:           "mismatched types",
+			body:           `{"name":1}`,
+			expectedError:  httputil.ErrMalformedBody,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   httputil.CodeMalformedBody,
+		},
+		{
+			name:           "empty body",
+			expectedError:  httputil.ErrMalformedBody,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   httputil.CodeMalformedBody,
+		},
+		{
+			name:           "trailing data",
+			body:           `{"name":"some-name"} {}`,
+			expectedError:  httputil.ErrMalformedBody,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   httputil.CodeMalformedBody,
+		},
+		{
+			name:           "semantically invalid payload",
+			body:           `{"email":"some-email"}`,
+			expectedError:  errNameRequired,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   httputil.CodeInvalidBody,
+		},
+		{
+			name:           "too large payload",
+			body:           `{"name":"` + strings.Repeat("a", httputil.DefaultMaxBodyBytes) + `"}`,
+			expectedError:  httputil.ErrBodyTooLarge,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedCode:   httputil.CodeBodyTooLarge,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.body))
+
+			got, err := httputil.Bind[createUser](context.Background(), req)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+
+				var appErr *apperr.Error
+				assert.ErrorAs(t, err, &appErr)
+				assert.Equal(t, tc.expectedStatus, appErr.Status)
+				assert.Equal(t, tc.expectedCode, appErr.Code)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, got)
+			}
+		})
+	}
+}
+
+func Test_BindInvalidBodyMessage(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`))
+
+	_, err := httputil.Bind[createUser](context.Background(), req)
+	assert.ErrorIs(t, err, httputil.ErrInvalidBody)
+
+	rec := httptest.NewRecorder()
+	httputil.WriteError(rec, req, err)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.JSONEq(t, `{"code":"invalid_body","message":"name is required"}`, rec.Body.String())
+}
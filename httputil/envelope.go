@@ -0,0 +1,55 @@
+package httputil
+
+import (
+	"net/http"
+)
+
+// Response is the envelope wrapping every payload written by OK and Fail, so
+// responses share the same shape across services: the payload under "data",
+// or the error message under "error", with optional pagination under "meta".
+type Response[T any] struct {
+	Data  T       `json:"data"`
+	Error *string `json:"error,omitempty"`
+	Meta  *Meta   `json:"meta,omitempty"`
+}
+
+// Meta describes the page of a paginated payload.
+type Meta struct {
+	Page       int    `json:"page,omitempty"`
+	PageSize   int    `json:"page_size,omitempty"`
+	Total      int    `json:"total,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// OK writes data wrapped in a Response with 200 OK.
+func OK[T any](w http.ResponseWriter, r *http.Request, data T) {
+	writeEnvelope(w, r, http.StatusOK, Response[T]{Data: data})
+}
+
+// OKWithMeta writes a page of data wrapped in a Response with its pagination
+// meta and 200 OK.
+func OKWithMeta[T any](w http.ResponseWriter, r *http.Request, data T, meta Meta) {
+	writeEnvelope(w, r, http.StatusOK, Response[T]{Data: data, Meta: &meta})
+}
+
+// Fail writes a Response holding the error message, with a null data, and the
+// given status.
+func Fail(w http.ResponseWriter, r *http.Request, status int, msg string) {
+	writeEnvelope(w, r, status, Response[any]{Error: &msg})
+}
+
+// writeEnvelope writes the envelope as JSON. Like WriteJSON, an encoding
+// failure is logged through the context logger and answered with a bare 500
+// Internal Server Error.
+func writeEnvelope(w http.ResponseWriter, r *http.Request, status int, envelope interface{}) {
+	body, err := encode(ContentTypeJSON, envelope, JSONOptions{})
+	if err != nil {
+		logEncodingError(r, ContentTypeJSON, err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", ContentTypeJSON+"; charset=utf-8")
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,64 @@
+package httputil_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+	"github.com/junkd0g/go-microservice-commons/httputil"
+	"github.com/junkd0g/go-microservice-commons/logger"
+)
+
+type user struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func Test_Envelope(t *testing.T) {
+	t.Run("should wrap data in a success envelope", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		httputil.OK(rec, httptest.NewRequest(http.MethodGet, "/", nil), user{ID: "some-uuid", Name: "some-name"})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json; charset=utf-8", rec.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{"data":{"id":"some-uuid","name":"some-name"}}`, rec.Body.String())
+	})
+
+	t.Run("should add the pagination meta", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		users := []user{{ID: "some-uuid", Name: "some-name"}}
+		httputil.OKWithMeta(rec, httptest.NewRequest(http.MethodGet, "/", nil), users, httputil.Meta{Page: 2, PageSize: 1, Total: 3})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"data":[{"id":"some-uuid","name":"some-name"}],"meta":{"page":2,"page_size":1,"total":3}}`, rec.Body.String())
+	})
+
+	t.Run("should write the error envelope", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		httputil.Fail(rec, httptest.NewRequest(http.MethodGet, "/", nil), http.StatusNotFound, "user not found")
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, "application/json; charset=utf-8", rec.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{"data":null,"error":"user not found"}`, rec.Body.String())
+	})
+
+	t.Run("should log encoding failures and answer 500", func(t *testing.T) {
+		log, err := logger.NewLogger()
+		assert.NoError(t, err)
+		core, recorded := observer.New(zapcore.InfoLevel)
+		log.SetCore(core)
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req = req.WithContext(goctx.AddLoggerToContex(req.Context(), log))
+		rec := httptest.NewRecorder()
+		httputil.OK(rec, req, make(chan int))
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Equal(t, 1, recorded.FilterMessage("failed to encode response").Len())
+	})
+}
@@ -0,0 +1,75 @@
+/*
+Package httputil provides helpers for HTTP handlers, such as writing responses
+in the content type negotiated with the client.
+*/
+package httputil
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Content types the response helpers can encode.
+const (
+	ContentTypeJSON  = "application/json"
+	ContentTypeXML   = "application/xml"
+	ContentTypePlain = "text/plain"
+)
+
+// supportedContentTypes lists the negotiable content types, the default first.
+var supportedContentTypes = []string{ContentTypeJSON, ContentTypeXML, ContentTypePlain}
+
+// NegotiateContentType returns the content type to respond with given the
+// request's Accept header: the supported type with the highest quality, the
+// earliest listed on ties. JSON is returned when the header is absent, accepts
+// anything or lists no supported type.
+func NegotiateContentType(r *http.Request) string {
+	accept := r.Header.Get("Accept")
+	if accept == "" {
+		return ContentTypeJSON
+	}
+
+	best, bestQuality := ContentTypeJSON, 0.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+
+		contentType, ok := matchContentType(mediaType)
+		if ok && quality > bestQuality {
+			best, bestQuality = contentType, quality
+		}
+	}
+
+	return best
+}
+
+// matchContentType returns the supported content type matching the media
+// range, wildcards matching JSON first.
+func matchContentType(mediaRange string) (string, bool) {
+	if mediaRange == "*/*" || mediaRange == "application/*" {
+		return ContentTypeJSON, true
+	}
+	if mediaRange == "text/*" {
+		return ContentTypePlain, true
+	}
+	if mediaRange == "text/xml" {
+		return ContentTypeXML, true
+	}
+	for _, contentType := range supportedContentTypes {
+		if mediaRange == contentType {
+			return contentType, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,41 @@
+package httputil_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/junkd0g/go-microservice-commons/httputil"
+)
+
+func Test_NegotiateContentType(t *testing.T) {
+	testCases := []struct {
+		name     string
+		accept   string
+		expected string
+	}{
+		{name: "no accept header", accept: "", expected: httputil.ContentTypeJSON},
+		{name: "json", accept: "application/json", expected: httputil.ContentTypeJSON},
+		{name: "xml", accept: "application/xml", expected: httputil.ContentTypeXML},
+		{name: "text xml", accept: "text/xml", expected: httputil.ContentTypeXML},
+		{name: "plain text", accept: "text/plain", expected: httputil.ContentTypePlain},
+		{name: "anything", accept: "*/*", expected: httputil.ContentTypeJSON},
+		{name: "unknown", accept: "image/png", expected: httputil.ContentTypeJSON},
+		{name: "malformed", accept: ";;;", expected: httputil.ContentTypeJSON},
+		{name: "first supported on ties", accept: "image/png, text/plain, application/json", expected: httputil.ContentTypePlain},
+		{name: "highest quality", accept: "application/json;q=0.5, application/xml;q=0.9", expected: httputil.ContentTypeXML},
+		{name: "browser", accept: "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", expected: httputil.ContentTypeXML},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+			assert.Equal(t, tc.expected, httputil.NegotiateContentType(req))
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package httputil
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+)
+
+// Correlation headers propagated by default.
+const (
+	HeaderRequestID   = "X-Request-ID"
+	HeaderTraceParent = "traceparent"
+	HeaderTenantID    = "X-Tenant-ID"
+	HeaderBaggage     = "baggage"
+)
+
+// DefaultPropagatedHeaders are the headers handled by WithHeaders and
+// PropagateHeaders when no names are given.
+var DefaultPropagatedHeaders = []string{HeaderRequestID, HeaderTraceParent, HeaderTenantID}
+
+// contextKeyHeaders is the context key under which WithHeaders stores headers.
+var contextKeyHeaders = goctx.NewKey[http.Header]("propagatedHeaders")
+
+// WithHeaders stores the values of the named headers of an inbound request in
+// ctx, defaulting to DefaultPropagatedHeaders, for PropagateHeaders to copy
+// them to outbound requests. Absent headers are skipped.
+func WithHeaders(ctx context.Context, inbound http.Header, names ...string) context.Context {
+	if len(names) == 0 {
+		names = DefaultPropagatedHeaders
+	}
+
+	headers := make(http.Header, len(names))
+	if stored, ok := goctx.Value(ctx, contextKeyHeaders); ok {
+		for name, values := range stored {
+			headers[name] = values
+		}
+	}
+	for _, name := range names {
+		if values := inbound.Values(name); len(values) > 0 {
+			headers[http.CanonicalHeaderKey(name)] = append([]string(nil), values...)
+		}
+	}
+
+	return goctx.WithValue(ctx, contextKeyHeaders, headers)
+}
+
+// PropagateHeaders sets the named headers, defaulting to
+// DefaultPropagatedHeaders, on the outbound request from the values stored in
+// ctx with WithHeaders. The request ID and tenant ID fall back to the values
+// set with goctx.WithRequestID and goctx.WithTenant, and the baggage header,
+// when named, to the baggage set with goctx.SetBaggage. Headers missing from the
+// context are skipped, and headers already set on the outbound request are
+// left untouched.
+func PropagateHeaders(ctx context.Context, outbound *http.Request, names ...string) {
+	if len(names) == 0 {
+		names = DefaultPropagatedHeaders
+	}
+
+	stored, _ := goctx.Value(ctx, contextKeyHeaders)
+	for _, name := range names {
+		if outbound.Header.Get(name) != "" {
+			continue
+		}
+
+		if values := stored.Values(name); len(values) > 0 {
+			for _, value := range values {
+				outbound.Header.Add(name, value)
+			}
+			continue
+		}
+
+		if value, ok := contextHeader(ctx, name); ok {
+			outbound.Header.Set(name, value)
+		}
+	}
+}
+
+// contextHeader returns the value of a correlation header from the values
+// stored in ctx by the context package.
+func contextHeader(ctx context.Context, name string) (string, bool) {
+	switch {
+	case strings.EqualFold(name, HeaderRequestID):
+		return goctx.RequestIDFromContext(ctx)
+	case strings.EqualFold(name, HeaderTenantID):
+		return goctx.TenantFromContext(ctx)
+	case strings.EqualFold(name, HeaderBaggage):
+		header := goctx.BaggageHeader(ctx)
+		return header, header != ""
+	}
+	return "", false
+}
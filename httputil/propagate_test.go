@@ -0,0 +1,86 @@
+package httputil_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+	"github.com/junkd0g/go-microservice-commons/httputil"
+)
+
+func Test_PropagateHeaders(t *testing.T) {
+	inbound := http.Header{}
+	inbound.Set("X-Request-ID", "abc-123")
+	inbound.Set("traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	inbound.Set("X-Tenant-ID", "tenant-123")
+	inbound.Set("X-Custom", "custom-value")
+
+	newOutbound := func() *http.Request {
+		return httptest.NewRequest(http.MethodGet, "http://downstream/", nil)
+	}
+
+	t.Run("should propagate the default correlation headers", func(t *testing.T) {
+		ctx := httputil.WithHeaders(context.Background(), inbound)
+		outbound := newOutbound()
+
+		httputil.PropagateHeaders(ctx, outbound)
+
+		assert.Equal(t, "abc-123", outbound.Header.Get("X-Request-ID"))
+		assert.Equal(t, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", outbound.Header.Get("traceparent"))
+		assert.Equal(t, "tenant-123", outbound.Header.Get("X-Tenant-ID"))
+		assert.Empty(t, outbound.Header.Get("X-Custom"))
+	})
+
+	t.Run("should propagate only the named headers", func(t *testing.T) {
+		ctx := httputil.WithHeaders(context.Background(), inbound, "X-Custom", "X-Tenant-ID")
+		outbound := newOutbound()
+
+		httputil.PropagateHeaders(ctx, outbound, "x-custom")
+
+		assert.Equal(t, "custom-value", outbound.Header.Get("X-Custom"))
+		assert.Empty(t, outbound.Header.Get("X-Tenant-ID"))
+		assert.Empty(t, outbound.Header.Get("X-Request-ID"))
+	})
+
+	t.Run("should fall back to the request and tenant IDs of the context", func(t *testing.T) {
+		ctx := goctx.WithRequestID(context.Background(), "ctx-id")
+		ctx = goctx.WithTenant(ctx, "ctx-tenant")
+		outbound := newOutbound()
+
+		httputil.PropagateHeaders(ctx, outbound)
+
+		assert.Equal(t, "ctx-id", outbound.Header.Get("X-Request-ID"))
+		assert.Equal(t, "ctx-tenant", outbound.Header.Get("X-Tenant-ID"))
+	})
+
+	t.Run("should fall back to the baggage of the context", func(t *testing.T) {
+		ctx := goctx.SetBaggage(context.Background(), "tier", "gold")
+		outbound := newOutbound()
+
+		httputil.PropagateHeaders(ctx, outbound, httputil.HeaderBaggage)
+
+		assert.Equal(t, "tier=gold", outbound.Header.Get("baggage"))
+	})
+
+	t.Run("should skip absent headers", func(t *testing.T) {
+		outbound := newOutbound()
+
+		httputil.PropagateHeaders(context.Background(), outbound)
+
+		assert.Empty(t, outbound.Header)
+	})
+
+	t.Run("should keep headers already set on the outbound request", func(t *testing.T) {
+		ctx := httputil.WithHeaders(context.Background(), inbound)
+		outbound := newOutbound()
+		outbound.Header.Set("X-Request-ID", "explicit-id")
+
+		httputil.PropagateHeaders(ctx, outbound)
+
+		assert.Equal(t, "explicit-id", outbound.Header.Get("X-Request-ID"))
+	})
+}
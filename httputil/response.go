@@ -0,0 +1,125 @@
+package httputil
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/junkd0g/go-microservice-commons/apperr"
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+)
+
+// ErrorBody is the body written by WriteError.
+type ErrorBody struct {
+	XMLName xml.Name `json:"-" xml:"error"`
+	Code    string   `json:"code" xml:"code"`
+	Message string   `json:"message" xml:"message"`
+}
+
+// String returns the message, used as the plain text body.
+func (b ErrorBody) String() string {
+	return b.Message
+}
+
+// JSONOptions controls how WriteJSONOpts encodes JSON bodies. The zero value
+// encodes compact JSON with HTML characters escaped, as WriteJSON does.
+type JSONOptions struct {
+	// Indent pretty-prints the body, indenting nested values with the string,
+	// e.g. "  " for debug endpoints read by humans. Empty means compact JSON.
+	Indent string
+	// DisableHTMLEscaping writes <, > and & as is instead of escaping them as
+	// \u003c, \u003e and \u0026.
+	DisableHTMLEscaping bool
+}
+
+// WriteJSON writes v with the given status as JSON, unless the request's Accept
+// header prefers XML or plain text (see NegotiateContentType). Values that
+// cannot be encoded as XML, such as maps, fall back to JSON. Plain text bodies
+// are formatted with fmt.Sprint. The body is encoded before anything is
+// written, so an encoding failure is logged through the context logger and
+// answered with a bare 500 Internal Server Error instead of a partial body.
+func WriteJSON(w http.ResponseWriter, r *http.Request, status int, v interface{}) {
+	WriteJSONOpts(w, r, status, v, JSONOptions{})
+}
+
+// WriteJSONOpts writes v like WriteJSON, encoding JSON bodies with the given
+// options, e.g. pretty-printed when a debug query parameter is set. XML and
+// plain text bodies are not affected by the options.
+func WriteJSONOpts(w http.ResponseWriter, r *http.Request, status int, v interface{}, opts JSONOptions) {
+	contentType := NegotiateContentType(r)
+
+	body, err := encode(contentType, v, opts)
+	if err != nil && contentType == ContentTypeXML {
+		// Maps and most interface{} values have no XML encoding; browsers
+		// accept XML but are as happy with JSON
+		contentType = ContentTypeJSON
+		body, err = encode(contentType, v, opts)
+	}
+	if err != nil {
+		logEncodingError(r, contentType, err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType+"; charset=utf-8")
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
+
+// WriteError writes err as an ErrorBody in the negotiated content type. The
+// status, code and message come from the first *apperr.Error in err's chain;
+// other errors are answered with a generic 500 so internal details never
+// reach the client.
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	WriteJSON(w, r, apperr.StatusCode(err), errorBody(err))
+}
+
+// errorBody returns the client safe body of err.
+func errorBody(err error) ErrorBody {
+	var appErr *apperr.Error
+	if errors.As(err, &appErr) {
+		return ErrorBody{Code: appErr.Code, Message: appErr.Message}
+	}
+	return ErrorBody{
+		Code:    apperr.CodeInternal,
+		Message: http.StatusText(http.StatusInternalServerError),
+	}
+}
+
+// encode encodes v in the given content type, JSON with the given options.
+func encode(contentType string, v interface{}, opts JSONOptions) ([]byte, error) {
+	switch contentType {
+	case ContentTypeXML:
+		body, err := xml.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte(xml.Header), body...), nil
+	case ContentTypePlain:
+		return []byte(fmt.Sprint(v)), nil
+	default:
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetIndent("", opts.Indent)
+		enc.SetEscapeHTML(!opts.DisableHTMLEscaping)
+		if err := enc.Encode(v); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+}
+
+// logEncodingError logs a failure to encode a response through the context logger, if any.
+func logEncodingError(r *http.Request, contentType string, err error) {
+	logger, loggerErr := goctx.GetLoggerFromContext(r.Context())
+	if loggerErr != nil {
+		return
+	}
+	logger.Error(r.Context(), "failed to encode response", map[string]interface{}{
+		"content_type": contentType,
+		"error":        err.Error(),
+	})
+}
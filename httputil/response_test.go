@@ -0,0 +1,163 @@
+package httputil_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/junkd0g/go-microservice-commons/apperr"
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+	"github.com/junkd0g/go-microservice-commons/httputil"
+	"github.com/junkd0g/go-microservice-commons/logger"
+)
+
+func Test_WriteJSON(t *testing.T) {
+	body := map[string]interface{}{"status": "ok"}
+
+	t.Run("should default to JSON", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		httputil.WriteJSON(rec, httptest.NewRequest(http.MethodGet, "/", nil), http.StatusCreated, body)
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, "application/json; charset=utf-8", rec.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{"status":"ok"}`, rec.Body.String())
+	})
+
+	t.Run("should write plain text when preferred", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept", "text/plain")
+		rec := httptest.NewRecorder()
+		httputil.WriteJSON(rec, req, http.StatusOK, "ok")
+
+		assert.Equal(t, "text/plain; charset=utf-8", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "ok", rec.Body.String())
+	})
+
+	t.Run("should fall back to JSON for values without an XML encoding", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
+		rec := httptest.NewRecorder()
+		httputil.WriteJSON(rec, req, http.StatusOK, body)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json; charset=utf-8", rec.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{"status":"ok"}`, rec.Body.String())
+	})
+
+	t.Run("should log encoding failures and answer 500", func(t *testing.T) {
+		log, err := logger.NewLogger()
+		assert.NoError(t, err)
+		core, recorded := observer.New(zapcore.InfoLevel)
+		log.SetCore(core)
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req = req.WithContext(goctx.AddLoggerToContex(req.Context(), log))
+		rec := httptest.NewRecorder()
+		httputil.WriteJSON(rec, req, http.StatusOK, map[string]interface{}{"invalid": make(chan int)})
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.NotContains(t, rec.Body.String(), "invalid")
+		assert.Equal(t, 1, recorded.FilterMessage("failed to encode response").Len())
+	})
+}
+
+func Test_WriteJSONOpts(t *testing.T) {
+	type owner struct {
+		Name string `json:"name"`
+	}
+	body := struct {
+		Title string `json:"title"`
+		Owner owner  `json:"owner"`
+	}{Title: "<b>report</b>", Owner: owner{Name: "alice"}}
+
+	testCases := []struct {
+		name     string
+		opts     httputil.JSONOptions
+		expected string
+	}{
+		{
+			name:     "should default to compact JSON with HTML escaped",
+			expected: `{"title":"\u003cb\u003ereport\u003c/b\u003e","owner":{"name":"alice"}}` + "\n",
+		},
+		{
+			name: "should pretty-print nested values",
+			opts: httputil.JSONOptions{Indent: "  "},
+			expected: `{
+  "title": "\u003cb\u003ereport\u003c/b\u003e",
+  "owner": {
+    "name": "alice"
+  }
+}
+`,
+		},
+		{
+			name:     "should leave HTML unescaped",
+			opts:     httputil.JSONOptions{DisableHTMLEscaping: true},
+			expected: `{"title":"<b>report</b>","owner":{"name":"alice"}}` + "\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			httputil.WriteJSONOpts(rec, httptest.NewRequest(http.MethodGet, "/", nil), http.StatusOK, body, tc.opts)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, tc.expected, rec.Body.String())
+		})
+	}
+
+	t.Run("should answer 500 without a partial body on encoding failures", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		httputil.WriteJSONOpts(rec, httptest.NewRequest(http.MethodGet, "/", nil), http.StatusOK,
+			map[string]interface{}{"valid": "yes", "invalid": make(chan int)}, httputil.JSONOptions{Indent: "  "})
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.NotContains(t, rec.Body.String(), "valid")
+	})
+}
+
+func Test_WriteError(t *testing.T) {
+	notFound := apperr.NotFound("user not found").WithCause(errors.New("sql: no rows"))
+
+	t.Run("should write an application error as JSON", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		httputil.WriteError(rec, httptest.NewRequest(http.MethodGet, "/", nil), notFound)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.JSONEq(t, `{"code":"not_found","message":"user not found"}`, rec.Body.String())
+	})
+
+	t.Run("should write XML when preferred", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept", "application/xml")
+		rec := httptest.NewRecorder()
+		httputil.WriteError(rec, req, notFound)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, "application/xml; charset=utf-8", rec.Header().Get("Content-Type"))
+		assert.Contains(t, rec.Body.String(), "<error><code>not_found</code><message>user not found</message></error>")
+	})
+
+	t.Run("should write plain text when preferred", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept", "text/plain")
+		rec := httptest.NewRecorder()
+		httputil.WriteError(rec, req, notFound)
+
+		assert.Equal(t, "user not found", rec.Body.String())
+	})
+
+	t.Run("should hide the details of other errors", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		httputil.WriteError(rec, httptest.NewRequest(http.MethodGet, "/", nil), errors.New("sql: connection refused"))
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.JSONEq(t, `{"code":"internal","message":"Internal Server Error"}`, rec.Body.String())
+	})
+}
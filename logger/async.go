@@ -0,0 +1,179 @@
+package logger
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// defaultAsyncFlushInterval is used when WithAsync is given a non-positive flush interval.
+	defaultAsyncFlushInterval = time.Second
+
+	// asyncBatchSize is the number of buffered bytes that triggers a flush before
+	// the flush interval elapses.
+	asyncBatchSize = 256 * 1024
+)
+
+// AsyncOverflowPolicy controls what an asynchronous logger does with entries
+// logged while its queue is full.
+type AsyncOverflowPolicy int
+
+const (
+	// AsyncDrop discards entries while the queue is full so logging never
+	// blocks the caller. This is the default.
+	AsyncDrop AsyncOverflowPolicy = iota
+	// AsyncBlock makes the caller wait for room in the queue, trading latency
+	// spikes for not losing entries.
+	AsyncBlock
+)
+
+// WithAsync moves writing entries off the caller's goroutine: encoded entries
+// are queued, up to bufferSize of them, and written in batches by a background
+// goroutine at least every flushInterval. This removes the write syscalls from
+// hot request paths at the cost of durability: entries still queued are lost
+// if the process crashes, and entries are dropped while the queue is full
+// unless AsyncBlock is set with WithAsyncOverflow. Call Sync to drain the queue
+// and Close before exiting.
+func WithAsync(bufferSize int, flushInterval time.Duration) Option {
+	return func(o *loggerOptions) {
+		if flushInterval <= 0 {
+			flushInterval = defaultAsyncFlushInterval
+		}
+		o.asyncBufferSize = max(bufferSize, 1)
+		o.asyncFlushInterval = flushInterval
+	}
+}
+
+// WithAsyncOverflow sets what an asynchronous logger does while its queue is full.
+func WithAsyncOverflow(policy AsyncOverflowPolicy) Option {
+	return func(o *loggerOptions) {
+		o.asyncPolicy = policy
+	}
+}
+
+// asyncRequest is either an encoded entry to write or, when flushed is set, a
+// request to flush everything queued before it.
+type asyncRequest struct {
+	entry   []byte
+	flushed chan struct{}
+}
+
+// asyncWriter is a WriteSyncer queueing writes to out for a background goroutine.
+type asyncWriter struct {
+	out           zapcore.WriteSyncer
+	queue         chan asyncRequest
+	policy        AsyncOverflowPolicy
+	flushInterval time.Duration
+	done          chan struct{}
+	dropped       atomic.Uint64
+
+	// mu guards closed; writers hold it for reading while sending to the queue
+	mu     sync.RWMutex
+	closed bool
+}
+
+// newAsyncWriter starts the background goroutine writing to out.
+func newAsyncWriter(out zapcore.WriteSyncer, o *loggerOptions) *asyncWriter {
+	w := &asyncWriter{
+		out:           out,
+		queue:         make(chan asyncRequest, o.asyncBufferSize),
+		policy:        o.asyncPolicy,
+		flushInterval: o.asyncFlushInterval,
+		done:          make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// Write queues a copy of p, as zap reuses the buffer once Write returns.
+func (w *asyncWriter) Write(p []byte) (int, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.closed {
+		return w.out.Write(p)
+	}
+
+	req := asyncRequest{entry: append([]byte(nil), p...)}
+	if w.policy == AsyncBlock {
+		w.queue <- req
+		return len(p), nil
+	}
+
+	select {
+	case w.queue <- req:
+	default:
+		w.dropped.Add(1)
+	}
+	return len(p), nil
+}
+
+// Sync waits until every entry queued so far has been written, then syncs out.
+func (w *asyncWriter) Sync() error {
+	w.mu.RLock()
+	if !w.closed {
+		flushed := make(chan struct{})
+		w.queue <- asyncRequest{flushed: flushed}
+		<-flushed
+	}
+	w.mu.RUnlock()
+
+	return w.out.Sync()
+}
+
+// Close drains the queue and stops the background goroutine. Later writes go
+// straight to out.
+func (w *asyncWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.queue)
+	w.mu.Unlock()
+
+	<-w.done
+	return w.out.Sync()
+}
+
+// run writes the queued entries in batches until the queue is closed.
+func (w *asyncWriter) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]byte, 0, asyncBatchSize)
+	flush := func() {
+		if len(batch) > 0 {
+			// Write errors have no caller to be reported to
+			_, _ = w.out.Write(batch)
+			batch = batch[:0]
+		}
+	}
+
+	for {
+		select {
+		case req, ok := <-w.queue:
+			if !ok {
+				flush()
+				return
+			}
+			if req.flushed != nil {
+				flush()
+				close(req.flushed)
+				continue
+			}
+			batch = append(batch, req.entry...)
+			if len(batch) >= asyncBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+
+	"github.com/junkd0g/go-microservice-commons/logger"
+)
+
+// slowWriter simulates a sink with a per-write latency, such as a network or disk write.
+type slowWriter struct {
+	delay time.Duration
+}
+
+func (w slowWriter) Write(p []byte) (int, error) {
+	time.Sleep(w.delay)
+	return len(p), nil
+}
+
+func (w slowWriter) Sync() error { return nil }
+
+func TestAsync(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	log, err := logger.NewLogger(
+		logger.WithLevelRouting(zapcore.AddSync(&stdout), zapcore.AddSync(&stderr)),
+		logger.WithAsync(16, time.Hour),
+		logger.WithAsyncOverflow(logger.AsyncBlock),
+	)
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+
+	for i := 0; i < 10; i++ {
+		log.Info(context.Background(), "Info Message")
+	}
+	log.Error(context.Background(), "Error Message")
+
+	if stdout.Len() != 0 {
+		t.Errorf("Expected entries to be queued until synced, got: %s", stdout.String())
+	}
+
+	_ = log.Sync()
+
+	if got := strings.Count(stdout.String(), "Info Message"); got != 10 {
+		t.Errorf("Expected 10 info entries after Sync, got %d", got)
+	}
+	if !strings.Contains(stderr.String(), "Error Message") {
+		t.Errorf("Expected error entry in stderr after Sync, got: %s", stderr.String())
+	}
+
+	log.Info(context.Background(), "Before Close")
+	if err := log.Close(); err != nil {
+		t.Fatalf("Error closing logger: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "Before Close") {
+		t.Errorf("Expected Close to drain the queue, got: %s", stdout.String())
+	}
+
+	// Entries logged after Close are written synchronously
+	log.Info(context.Background(), "After Close")
+	if !strings.Contains(stdout.String(), "After Close") {
+		t.Errorf("Expected entry written after Close, got: %s", stdout.String())
+	}
+}
+
+func TestCloseWithoutAsync(t *testing.T) {
+	log, err := logger.NewLogger()
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+	if err := log.Close(); err != nil {
+		t.Errorf("Unexpected error closing logger: %v", err)
+	}
+	if got := log.Dropped(); got != 0 {
+		t.Errorf("Expected no dropped entries, got %d", got)
+	}
+}
+
+func BenchmarkInfoSlowSink(b *testing.B) {
+	benchmarks := []struct {
+		name string
+		opts []logger.Option
+	}{
+		{name: "sync"},
+		{name: "async", opts: []logger.Option{logger.WithAsync(1024, 100*time.Millisecond)}},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			out := zapcore.AddSync(slowWriter{delay: 50 * time.Microsecond})
+			log, err := logger.NewLogger(append(bm.opts, logger.WithLevelRouting(out, out))...)
+			if err != nil {
+				b.Fatalf("Error creating logger: %v", err)
+			}
+			defer log.Close()
+
+			ctx := context.Background()
+			fields := map[string]interface{}{"key": "value", "number": 1}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				log.Info(ctx, "Info Message", fields)
+			}
+		})
+	}
+}
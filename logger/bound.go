@@ -0,0 +1,58 @@
+package logger
+
+import "context"
+
+// BoundLogger is a Logger bound to a context by Logger.Ctx, logging without
+// taking a context argument. It extracts the same fields as the Logger
+// methods it calls, from the context captured at bind time.
+type BoundLogger struct {
+	logger *Logger
+	ctx    context.Context
+}
+
+// Ctx binds the logger to ctx, e.g. for a long-lived logger within a request:
+//
+//	log := l.Ctx(ctx)
+//	log.Info("user created", map[string]interface{}{"user_id": id})
+//
+// The context is captured as is: contexts derived from ctx later, e.g. to add
+// fields, are not seen by the returned logger, while changes to the
+// MutableFields already in ctx are.
+func (l *Logger) Ctx(ctx context.Context) *BoundLogger {
+	return &BoundLogger{logger: l, ctx: ctx}
+}
+
+// Context returns the context the logger is bound to.
+func (b *BoundLogger) Context() context.Context {
+	return b.ctx
+}
+
+// Info logs an informational message, see Logger.Info.
+func (b *BoundLogger) Info(msg string, fields ...map[string]interface{}) {
+	b.logger.Info(b.ctx, msg, fields...)
+}
+
+// Warn logs a warning message, see Logger.Warn.
+func (b *BoundLogger) Warn(msg string, fields ...map[string]interface{}) {
+	b.logger.Warn(b.ctx, msg, fields...)
+}
+
+// Error logs an error message, see Logger.Error.
+func (b *BoundLogger) Error(msg string, fields ...map[string]interface{}) {
+	b.logger.Error(b.ctx, msg, fields...)
+}
+
+// LogError logs err at Error level, see Logger.LogError.
+func (b *BoundLogger) LogError(err error, fields ...map[string]interface{}) {
+	b.logger.LogError(b.ctx, err, fields...)
+}
+
+// WrapError logs msg at Error level and returns err wrapped, see Logger.WrapError.
+func (b *BoundLogger) WrapError(err error, msg string, fields ...map[string]interface{}) error {
+	return b.logger.WrapError(b.ctx, err, msg, fields...)
+}
+
+// TimeIt runs fn and logs its completion, see Logger.TimeIt.
+func (b *BoundLogger) TimeIt(name string, fn func() error) error {
+	return b.logger.TimeIt(b.ctx, name, fn)
+}
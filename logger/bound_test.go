@@ -0,0 +1,77 @@
+package logger_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+	"github.com/junkd0g/go-microservice-commons/logger"
+)
+
+func TestCtx(t *testing.T) {
+	log, err := logger.NewLogger()
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+	core, recorded := observer.New(zapcore.InfoLevel)
+	log.SetCore(core)
+
+	mutableFields := goctx.NewMutableFields()
+	mutableFields.AddField(map[string]interface{}{"request_id": "abc-123"})
+	ctx := goctx.WithMutableFields(context.Background(), mutableFields)
+	bound := log.Ctx(ctx)
+
+	// Contexts derived after binding are not seen by the bound logger
+	other := goctx.NewMutableFields()
+	other.AddField(map[string]interface{}{"request_id": "other"})
+	_ = goctx.WithMutableFields(ctx, other)
+
+	if bound.Context() != ctx {
+		t.Errorf("Expected the bound context to be returned")
+	}
+
+	bound.Info("Info Message", map[string]interface{}{"user_id": "user-1"})
+	bound.Warn("Warn Message")
+	bound.Error("Error Message")
+	bound.LogError(errors.New("failure"))
+
+	entries := recorded.All()
+	if len(entries) != 4 {
+		t.Fatalf("Expected 4 log entries, got %d", len(entries))
+	}
+
+	expectedLevels := []zapcore.Level{zapcore.InfoLevel, zapcore.WarnLevel, zapcore.ErrorLevel, zapcore.ErrorLevel}
+	for i, entry := range entries {
+		if entry.Level != expectedLevels[i] {
+			t.Errorf("Expected level %v, got %v", expectedLevels[i], entry.Level)
+		}
+		if got := entry.ContextMap()["request_id"]; got != "abc-123" {
+			t.Errorf("Expected request_id from the bound context, got %v", got)
+		}
+	}
+	if got := entries[0].ContextMap()["user_id"]; got != "user-1" {
+		t.Errorf("Expected per-call field user_id, got %v", got)
+	}
+
+	wrapped := bound.WrapError(errors.New("timeout"), "fetch failed")
+	if wrapped == nil || wrapped.Error() != "fetch failed: timeout" {
+		t.Errorf("Expected wrapped error, got %v", wrapped)
+	}
+	if err := bound.TimeIt("job", func() error { return nil }); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if recorded.Len() != 6 {
+		t.Errorf("Expected 6 log entries, got %d", recorded.Len())
+	}
+}
+
+func TestCtxNilLogger(t *testing.T) {
+	var log *logger.Logger
+
+	// Logging through a bound nil logger does nothing
+	log.Ctx(context.Background()).Info("Info Message")
+}
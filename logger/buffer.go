@@ -0,0 +1,103 @@
+package logger
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// entryBufferKey is the context key under which a request's entryBuffer is stored.
+type entryBufferKey struct{}
+
+// bufferedEntry is an entry held back until an error is logged for its context.
+type bufferedEntry struct {
+	level  zapcore.Level
+	time   time.Time
+	msg    string
+	fields []zap.Field
+}
+
+// entryBuffer is a fixed size ring buffer of held back entries, keeping the
+// most recent ones once full. It is safe for concurrent use.
+type entryBuffer struct {
+	mu      sync.Mutex
+	owner   *Logger
+	entries []bufferedEntry
+	next    int
+	full    bool
+}
+
+// add stores the entry, overwriting the oldest one when the buffer is full.
+func (b *entryBuffer) add(entry bufferedEntry) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.entries[b.next] = entry
+	b.next = (b.next + 1) % len(b.entries)
+	if b.next == 0 {
+		b.full = true
+	}
+}
+
+// drain returns the buffered entries, oldest first, and empties the buffer.
+func (b *entryBuffer) drain() []bufferedEntry {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var drained []bufferedEntry
+	if b.full {
+		drained = append(drained, b.entries[b.next:]...)
+	}
+	drained = append(drained, b.entries[:b.next]...)
+
+	clear(b.entries)
+	b.next = 0
+	b.full = false
+
+	return drained
+}
+
+// WithEntryBuffer returns a context in which the logger holds back info entries
+// instead of writing them, keeping the most recent ones up to the size set with
+// WithErrorTriggeredBuffer. They are written at their original level and time
+// right before the first error logged with the context, and silently discarded
+// otherwise, so detailed logs are only kept for failing requests. The context is
+// returned unchanged when buffering is not enabled for the logger.
+func (l *Logger) WithEntryBuffer(ctx context.Context) context.Context {
+	if l.noop() || l.bufferSize <= 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, entryBufferKey{}, &entryBuffer{
+		owner:   l,
+		entries: make([]bufferedEntry, l.bufferSize),
+	})
+}
+
+// entryBuffer returns the logger's buffer stored in the context, if any.
+func (l *Logger) entryBuffer(ctx context.Context) *entryBuffer {
+	if l.bufferSize <= 0 {
+		return nil
+	}
+	if buf, ok := ctx.Value(entryBufferKey{}).(*entryBuffer); ok && buf.owner == l {
+		return buf
+	}
+	return nil
+}
+
+// flushEntryBuffer writes the entries held back in the context's buffer.
+func (l *Logger) flushEntryBuffer(ctx context.Context) {
+	buf := l.entryBuffer(ctx)
+	if buf == nil {
+		return
+	}
+
+	for _, entry := range buf.drain() {
+		if ce := l.logger.Check(entry.level, entry.msg); ce != nil {
+			ce.Time = entry.time
+			ce.Write(entry.fields...)
+		}
+	}
+}
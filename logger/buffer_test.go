@@ -0,0 +1,93 @@
+package logger_test
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/junkd0g/go-microservice-commons/logger"
+)
+
+func TestErrorTriggeredBuffer(t *testing.T) {
+	log, err := logger.NewLogger(logger.WithErrorTriggeredBuffer(2))
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+	core, recorded := observer.New(zapcore.InfoLevel)
+	log.SetCore(core)
+
+	t.Run("discards buffered entries without an error", func(t *testing.T) {
+		ctx := log.WithEntryBuffer(context.Background())
+
+		log.Info(ctx, "step one")
+		log.Warn(ctx, "warning")
+
+		entries := recorded.TakeAll()
+		if len(entries) != 1 {
+			t.Fatalf("Expected 1 log entry, got %d", len(entries))
+		}
+		if entries[0].Message != "warning" {
+			t.Errorf("Unexpected message: %s", entries[0].Message)
+		}
+	})
+
+	t.Run("flushes the most recent entries before an error", func(t *testing.T) {
+		ctx := log.WithEntryBuffer(context.Background())
+
+		log.Info(ctx, "step one")
+		log.Info(ctx, "step two", map[string]interface{}{"key": "value"})
+		log.Info(ctx, "step three")
+		log.Error(ctx, "failed")
+		log.Error(ctx, "failed again")
+
+		entries := recorded.TakeAll()
+		want := []string{"step two", "step three", "failed", "failed again"}
+		if len(entries) != len(want) {
+			t.Fatalf("Expected %d log entries, got %d", len(want), len(entries))
+		}
+		for i, msg := range want {
+			if entries[i].Message != msg {
+				t.Errorf("Entry %d: expected message %q, got %q", i, msg, entries[i].Message)
+			}
+		}
+
+		if entries[0].Level != zapcore.InfoLevel {
+			t.Errorf("Expected buffered entry at info level, got %s", entries[0].Level)
+		}
+		if entries[0].ContextMap()["key"] != "value" {
+			t.Errorf("Expected buffered entry to keep its fields, got %v", entries[0].ContextMap())
+		}
+		if entries[0].Time.After(entries[2].Time) {
+			t.Errorf("Expected buffered entry to keep its original time")
+		}
+	})
+
+	t.Run("logs directly without a buffered context", func(t *testing.T) {
+		log.Info(context.Background(), "not buffered")
+
+		if got := recorded.TakeAll(); len(got) != 1 {
+			t.Fatalf("Expected 1 log entry, got %d", len(got))
+		}
+	})
+}
+
+func TestWithEntryBufferDisabled(t *testing.T) {
+	log, err := logger.NewLogger()
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+	core, recorded := observer.New(zapcore.InfoLevel)
+	log.SetCore(core)
+
+	ctx := context.Background()
+	if log.WithEntryBuffer(ctx) != ctx {
+		t.Errorf("Expected the context to be returned unchanged")
+	}
+
+	log.Info(log.WithEntryBuffer(ctx), "Info Message")
+	if recorded.Len() != 1 {
+		t.Fatalf("Expected 1 log entry, got %d", recorded.Len())
+	}
+}
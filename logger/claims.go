@@ -0,0 +1,35 @@
+package logger
+
+import (
+	"context"
+
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+)
+
+const (
+	// FieldUserID is the key under which the authenticated subject is logged.
+	FieldUserID = "user_id"
+	// FieldEmail is the key under which the authenticated email is logged when
+	// enabled with WithClaimsEmail.
+	FieldEmail = "email"
+)
+
+// claimsFields returns the fields identifying the authenticated user stored in
+// the context with goctx.WithUser, as done by auth.WithClaims, if any. The
+// email is only included when the logger was built with WithClaimsEmail as it
+// is personal data.
+func (l *Logger) claimsFields(ctx context.Context) []map[string]interface{} {
+	user, ok := goctx.UserFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	fields := make(map[string]interface{}, 2)
+	if user.ID != "" {
+		fields[FieldUserID] = user.ID
+	}
+	if l.logEmail && user.Email != "" {
+		fields[FieldEmail] = user.Email
+	}
+	return []map[string]interface{}{fields}
+}
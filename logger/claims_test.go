@@ -0,0 +1,54 @@
+package logger_test
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+	"github.com/junkd0g/go-microservice-commons/logger"
+)
+
+func TestClaimsFields(t *testing.T) {
+	ctx := goctx.WithUser(context.Background(), goctx.User{ID: "some-uuid", Email: "some-email"})
+
+	t.Run("logs the user ID without the email by default", func(t *testing.T) {
+		log, err := logger.NewLogger()
+		if err != nil {
+			t.Fatalf("Error creating logger: %v", err)
+		}
+		core, recorded := observer.New(zapcore.InfoLevel)
+		log.SetCore(core)
+
+		log.Info(ctx, "Info Message")
+
+		fields := recorded.All()[0].ContextMap()
+		if fields[logger.FieldUserID] != "some-uuid" {
+			t.Errorf("Expected user_id field, got %v", fields)
+		}
+		if _, ok := fields[logger.FieldEmail]; ok {
+			t.Errorf("Unexpected email field: %v", fields)
+		}
+	})
+
+	t.Run("logs the email when enabled", func(t *testing.T) {
+		log, err := logger.NewLogger(logger.WithClaimsEmail())
+		if err != nil {
+			t.Fatalf("Error creating logger: %v", err)
+		}
+		core, recorded := observer.New(zapcore.InfoLevel)
+		log.SetCore(core)
+
+		log.Info(ctx, "Info Message", map[string]interface{}{logger.FieldUserID: "override"})
+
+		fields := recorded.All()[0].ContextMap()
+		if fields[logger.FieldEmail] != "some-email" {
+			t.Errorf("Expected email field, got %v", fields)
+		}
+		if fields[logger.FieldUserID] != "override" {
+			t.Errorf("Expected per-call field to override user_id, got %v", fields[logger.FieldUserID])
+		}
+	})
+}
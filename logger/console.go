@@ -0,0 +1,110 @@
+package logger
+
+import (
+	"fmt"
+	"sort"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// FieldOverflow is the key of the marker replacing the fields beyond the cap
+// set with WithConsoleFieldCap.
+const FieldOverflow = "..."
+
+// Color is an ANSI foreground color used to render levels, see WithLevelColors.
+type Color uint8
+
+// Colors supported by WithLevelColors.
+const (
+	ColorBlack Color = iota + 30
+	ColorRed
+	ColorGreen
+	ColorYellow
+	ColorBlue
+	ColorMagenta
+	ColorCyan
+	ColorWhite
+)
+
+// WithConsoleEncoding writes entries in zap's human readable console format,
+// with ISO 8601 timestamps, instead of JSON. It is meant for local development;
+// production output should stay JSON so it can be indexed.
+func WithConsoleEncoding() Option {
+	return func(o *loggerOptions) {
+		o.console = true
+	}
+}
+
+// WithConsoleFieldCap limits the fields printed inline by the console encoding
+// to the first n in key order, the rest being summarized by a "..." field
+// holding their count, so entries carrying many fields stay readable. It has
+// no effect on JSON output.
+func WithConsoleFieldCap(n int) Option {
+	return func(o *loggerOptions) {
+		o.consoleFieldCap = n
+	}
+}
+
+// WithLevelColors renders levels in uppercase wrapped in the given colors,
+// overriding the level format. Levels without a color keep zap's default one
+// (see LevelCapitalColor). It is meant for the console encoding.
+func WithLevelColors(colors map[zapcore.Level]Color) Option {
+	return func(o *loggerOptions) {
+		o.levelColors = colors
+	}
+}
+
+// levelColorEncoder returns a level encoder rendering levels with the colors.
+func levelColorEncoder(colors map[zapcore.Level]Color) zapcore.LevelEncoder {
+	return func(level zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+		color, ok := colors[level]
+		if !ok {
+			zapcore.CapitalColorLevelEncoder(level, enc)
+			return
+		}
+		enc.AppendString(fmt.Sprintf("\x1b[%dm%s\x1b[0m", color, level.CapitalString()))
+	}
+}
+
+// newEncoder returns the encoder of the config, wrapping console encoders to
+// apply the field cap.
+func (o *loggerOptions) newEncoder(config zap.Config) zapcore.Encoder {
+	if config.Encoding != "console" {
+		return zapcore.NewJSONEncoder(config.EncoderConfig)
+	}
+
+	encoder := zapcore.NewConsoleEncoder(config.EncoderConfig)
+	if o.consoleFieldCap > 0 {
+		return &cappedEncoder{Encoder: encoder, limit: o.consoleFieldCap}
+	}
+	return encoder
+}
+
+// cappedEncoder is an encoder printing at most limit fields per entry.
+type cappedEncoder struct {
+	zapcore.Encoder
+	limit int
+}
+
+// Clone clones the wrapped encoder, keeping the cap.
+func (e *cappedEncoder) Clone() zapcore.Encoder {
+	return &cappedEncoder{Encoder: e.Encoder.Clone(), limit: e.limit}
+}
+
+// EncodeEntry sorts a copy of the fields by key, so the output is
+// deterministic, and replaces those beyond the cap with the overflow marker
+// before encoding the entry.
+func (e *cappedEncoder) EncodeEntry(entry zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	sorted := make([]zapcore.Field, len(fields), len(fields)+1)
+	copy(sorted, fields)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Key < sorted[j].Key
+	})
+
+	if overflow := len(sorted) - e.limit; overflow > 0 {
+		sorted = append(sorted[:e.limit], zap.String(FieldOverflow, fmt.Sprintf("%d more", overflow)))
+	}
+	return e.Encoder.EncodeEntry(entry, sorted)
+}
@@ -0,0 +1,81 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+
+	"github.com/junkd0g/go-microservice-commons/logger"
+)
+
+func TestConsoleFieldCap(t *testing.T) {
+	var out bytes.Buffer
+
+	log, err := logger.NewLogger(
+		logger.WithConsoleEncoding(),
+		logger.WithConsoleFieldCap(2),
+		logger.WithLevelRouting(zapcore.AddSync(&out), zapcore.AddSync(&out)),
+	)
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+
+	log.Info(context.Background(), "Few Fields", map[string]interface{}{"a": "1", "b": "2"})
+	log.Info(context.Background(), "Many Fields", map[string]interface{}{"a": "1", "b": "2", "c": "3", "d": "4"})
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d: %s", len(lines), out.String())
+	}
+
+	if strings.HasPrefix(lines[0], "{") || !strings.HasSuffix(lines[0], `Few Fields	{"a": "1", "b": "2"}`) {
+		t.Errorf("Expected every field printed in console format, got: %s", lines[0])
+	}
+	if !strings.HasSuffix(lines[1], `Many Fields	{"a": "1", "b": "2", "...": "2 more"}`) {
+		t.Errorf("Expected the fields beyond the cap to be summarized, got: %s", lines[1])
+	}
+}
+
+func TestConsoleFieldCapIgnoredByJSON(t *testing.T) {
+	var out bytes.Buffer
+
+	log, err := logger.NewLogger(
+		logger.WithConsoleFieldCap(1),
+		logger.WithLevelRouting(zapcore.AddSync(&out), zapcore.AddSync(&out)),
+	)
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+
+	log.Info(context.Background(), "Info Message", map[string]interface{}{"a": "1", "b": "2"})
+
+	if !bytes.Contains(out.Bytes(), []byte(`"a":"1"`)) || !bytes.Contains(out.Bytes(), []byte(`"b":"2"`)) {
+		t.Errorf("Expected every field in JSON output, got: %s", out.String())
+	}
+}
+
+func TestLevelColors(t *testing.T) {
+	var out bytes.Buffer
+
+	log, err := logger.NewLogger(
+		logger.WithConsoleEncoding(),
+		logger.WithLevelColors(map[zapcore.Level]logger.Color{zapcore.InfoLevel: logger.ColorGreen}),
+		logger.WithLevelRouting(zapcore.AddSync(&out), zapcore.AddSync(&out)),
+	)
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+
+	log.Info(context.Background(), "Info Message")
+	log.Error(context.Background(), "Error Message")
+
+	if !strings.Contains(out.String(), "\x1b[32mINFO\x1b[0m") {
+		t.Errorf("Expected info level in the configured color, got: %q", out.String())
+	}
+	if !strings.Contains(out.String(), "\x1b[31mERROR\x1b[0m") {
+		t.Errorf("Expected error level in the default color, got: %q", out.String())
+	}
+}
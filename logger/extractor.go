@@ -0,0 +1,66 @@
+package logger
+
+import (
+	"context"
+	"sync"
+)
+
+// ContextExtractor returns fields to log pulled from arbitrary context values,
+// such as a request ID or tenant stored under keys the logger does not know.
+type ContextExtractor func(ctx context.Context) map[string]interface{}
+
+// extractorEntry wraps a registered extractor so it can be deregistered by identity.
+type extractorEntry struct {
+	extract ContextExtractor
+}
+
+var (
+	extractorsMu sync.RWMutex
+	extractors   []*extractorEntry
+)
+
+// RegisterContextExtractor registers an extractor invoked on every log call of
+// every Logger. Extractors run in registration order and their fields are
+// merged after the default fields and before the context MutableFields and the
+// per-call fields, which override them. As extractors run on the hot path they
+// should be cheap and must be safe for concurrent use. The returned func
+// deregisters the extractor, which is mostly useful in tests.
+func RegisterContextExtractor(extract ContextExtractor) func() {
+	entry := &extractorEntry{extract: extract}
+
+	extractorsMu.Lock()
+	extractors = append(extractors, entry)
+	extractorsMu.Unlock()
+
+	return func() {
+		extractorsMu.Lock()
+		defer extractorsMu.Unlock()
+
+		for i, e := range extractors {
+			if e == entry {
+				// Copy on removal so slices handed out by extractedFields stay intact
+				extractors = append(extractors[:i:i], extractors[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// extractedFields runs the registered extractors against the context.
+func extractedFields(ctx context.Context) []map[string]interface{} {
+	extractorsMu.RLock()
+	registered := extractors
+	extractorsMu.RUnlock()
+
+	if len(registered) == 0 {
+		return nil
+	}
+
+	fields := make([]map[string]interface{}, 0, len(registered))
+	for _, entry := range registered {
+		if extracted := entry.extract(ctx); len(extracted) > 0 {
+			fields = append(fields, extracted)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,71 @@
+package logger_test
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+	"github.com/junkd0g/go-microservice-commons/logger"
+)
+
+func TestContextExtractor(t *testing.T) {
+	log, err := logger.NewLogger()
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+
+	core, recorded := observer.New(zapcore.InfoLevel)
+	log.SetCore(core)
+
+	deregisterTenant := logger.RegisterContextExtractor(func(ctx context.Context) map[string]interface{} {
+		tenant, ok := goctx.TenantFromContext(ctx)
+		if !ok {
+			return nil
+		}
+		return map[string]interface{}{"tenant": tenant}
+	})
+	deregisterRequestID := logger.RegisterContextExtractor(func(ctx context.Context) map[string]interface{} {
+		requestID, ok := goctx.RequestIDFromContext(ctx)
+		if !ok {
+			return nil
+		}
+		return map[string]interface{}{"request_id": requestID}
+	})
+
+	ctx := goctx.WithTenant(context.Background(), "tenant-123")
+	ctx = goctx.WithRequestID(ctx, "abc-123")
+
+	log.Info(ctx, "Extracted")
+	log.Info(ctx, "Overridden", map[string]interface{}{"tenant": "other"})
+
+	deregisterTenant()
+	log.Info(ctx, "Deregistered")
+	deregisterRequestID()
+	log.Info(ctx, "None")
+
+	entries := recorded.All()
+	if len(entries) != 4 {
+		t.Fatalf("Expected 4 log entries, got %d", len(entries))
+	}
+
+	fields := entries[0].ContextMap()
+	if fields["tenant"] != "tenant-123" || fields["request_id"] != "abc-123" {
+		t.Errorf("Expected extracted fields, got %v", fields)
+	}
+
+	if entries[1].ContextMap()["tenant"] != "other" {
+		t.Errorf("Expected per-call field to override extracted one, got %v", entries[1].ContextMap())
+	}
+
+	fields = entries[2].ContextMap()
+	if _, ok := fields["tenant"]; ok || fields["request_id"] != "abc-123" {
+		t.Errorf("Expected only the remaining extractor, got %v", fields)
+	}
+
+	if len(entries[3].Context) != 0 {
+		t.Errorf("Expected no fields once all extractors are deregistered, got %v", entries[3].ContextMap())
+	}
+}
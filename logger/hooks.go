@@ -0,0 +1,256 @@
+package logger
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Hook receives the entries forwarded by RegisterHook, e.g. to report errors
+// to an error tracker such as Sentry. The fields are those logged with the
+// entry; a synchronous hook must not keep the slice after returning.
+type Hook func(entry zapcore.Entry, fields []zapcore.Field)
+
+// HookOption configures a hook registered with RegisterHook.
+type HookOption func(*hookConfig)
+
+// hookConfig holds the settings collected from HookOptions.
+type hookConfig struct {
+	bufferSize int
+}
+
+// WithHookBuffer runs the hook on a background goroutine so a slow external
+// sink never delays the caller: entries are queued, up to size of them, and
+// dropped while the queue is full. Close drains the queue.
+func WithHookBuffer(size int) HookOption {
+	return func(c *hookConfig) {
+		c.bufferSize = max(size, 1)
+	}
+}
+
+// RegisterHook forwards every entry logged at level or above to fn, alongside
+// the regular outputs which are left unchanged. Hooks see the entries of the
+// levels enabled on the logger, including those dropped by sampling. By default
+// fn runs on the caller's goroutine; use WithHookBuffer for sinks doing network
+// calls. It is a no-op on a nil or zero value Logger.
+func (l *Logger) RegisterHook(level zapcore.Level, fn Hook, opts ...HookOption) {
+	if l.noop() || l.hooks == nil {
+		return
+	}
+
+	config := &hookConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	h := &registeredHook{level: level, fn: fn}
+	if config.bufferSize > 0 {
+		h.async = newAsyncHook(fn, config.bufferSize)
+	}
+	l.hooks.add(h)
+}
+
+// hookRegistry holds the hooks of a logger. Registrations copy the slice so
+// entries are dispatched without locking.
+type hookRegistry struct {
+	mu    sync.Mutex
+	hooks atomic.Pointer[[]*registeredHook]
+}
+
+// registeredHook is a hook and the minimum level of the entries it receives.
+type registeredHook struct {
+	level zapcore.Level
+	fn    Hook
+	async *asyncHook
+}
+
+// add registers the hook.
+func (r *hookRegistry) add(h *registeredHook) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var hooks []*registeredHook
+	if current := r.hooks.Load(); current != nil {
+		hooks = append(hooks, *current...)
+	}
+	hooks = append(hooks, h)
+	r.hooks.Store(&hooks)
+}
+
+// load returns the registered hooks.
+func (r *hookRegistry) load() []*registeredHook {
+	if current := r.hooks.Load(); current != nil {
+		return *current
+	}
+	return nil
+}
+
+// matches reports whether any hook receives entries at the level.
+func (r *hookRegistry) matches(level zapcore.Level) bool {
+	for _, h := range r.load() {
+		if level >= h.level {
+			return true
+		}
+	}
+	return false
+}
+
+// dispatch forwards the entry to the hooks receiving its level.
+func (r *hookRegistry) dispatch(entry zapcore.Entry, fields []zapcore.Field) {
+	for _, h := range r.load() {
+		if entry.Level < h.level {
+			continue
+		}
+		if h.async != nil {
+			h.async.send(entry, fields)
+			continue
+		}
+		h.fn(entry, fields)
+	}
+}
+
+// Close drains and stops the asynchronous hooks.
+func (r *hookRegistry) Close() {
+	for _, h := range r.load() {
+		if h.async != nil {
+			h.async.Close()
+		}
+	}
+}
+
+// hookCore wraps the core of a logger so that the entries it enables are
+// also forwarded to the registered hooks.
+type hookCore struct {
+	zapcore.Core
+	hooks  *hookRegistry
+	fields []zapcore.Field
+}
+
+// newHookCore wraps core with the hooks, if any registry is given.
+func newHookCore(core zapcore.Core, hooks *hookRegistry) zapcore.Core {
+	if hooks == nil {
+		return core
+	}
+	return &hookCore{Core: core, hooks: hooks}
+}
+
+// With adds the fields to both the wrapped core and the entries forwarded to the hooks.
+func (c *hookCore) With(fields []zapcore.Field) zapcore.Core {
+	return &hookCore{
+		Core:   c.Core.With(fields),
+		hooks:  c.hooks,
+		fields: append(c.fields[:len(c.fields):len(c.fields)], fields...),
+	}
+}
+
+// Check adds the wrapped core and, when a hook receives the entry's level, the hooks.
+func (c *hookCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	ce = c.Core.Check(entry, ce)
+	if c.Core.Enabled(entry.Level) && c.hooks.matches(entry.Level) {
+		ce = ce.AddCore(entry, hookWriter{c})
+	}
+	return ce
+}
+
+// hookWriter is the core writing the entries checked by a hookCore to its hooks.
+type hookWriter struct {
+	core *hookCore
+}
+
+// Enabled reports whether a hook receives entries at the level.
+func (w hookWriter) Enabled(level zapcore.Level) bool {
+	return w.core.hooks.matches(level)
+}
+
+// With returns the writer of the hookCore with the fields added.
+func (w hookWriter) With(fields []zapcore.Field) zapcore.Core {
+	return hookWriter{w.core.With(fields).(*hookCore)}
+}
+
+// Check is never called, as hookWriter is only added to checked entries.
+func (w hookWriter) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce
+}
+
+// Write forwards the entry with the fields of the hookCore to the hooks.
+func (w hookWriter) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	if len(w.core.fields) > 0 {
+		fields = append(w.core.fields[:len(w.core.fields):len(w.core.fields)], fields...)
+	}
+	w.core.hooks.dispatch(entry, fields)
+	return nil
+}
+
+// Sync is a no-op: asynchronous hooks are drained by Close.
+func (w hookWriter) Sync() error {
+	return nil
+}
+
+// hookEntry is an entry queued for an asynchronous hook.
+type hookEntry struct {
+	entry  zapcore.Entry
+	fields []zapcore.Field
+}
+
+// asyncHook runs a hook on a background goroutine.
+type asyncHook struct {
+	fn    Hook
+	queue chan hookEntry
+	done  chan struct{}
+
+	// mu guards closed; senders hold it for reading while sending to the queue
+	mu     sync.RWMutex
+	closed bool
+}
+
+// newAsyncHook starts the background goroutine running fn.
+func newAsyncHook(fn Hook, size int) *asyncHook {
+	h := &asyncHook{
+		fn:    fn,
+		queue: make(chan hookEntry, size),
+		done:  make(chan struct{}),
+	}
+	go h.run()
+	return h
+}
+
+// send queues a copy of the fields, as the logger reuses the slice, dropping
+// the entry while the queue is full. Entries sent after Close are passed to
+// the hook directly.
+func (h *asyncHook) send(entry zapcore.Entry, fields []zapcore.Field) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.closed {
+		h.fn(entry, fields)
+		return
+	}
+
+	select {
+	case h.queue <- hookEntry{entry: entry, fields: append([]zapcore.Field(nil), fields...)}:
+	default:
+	}
+}
+
+// Close drains the queue and stops the background goroutine.
+func (h *asyncHook) Close() {
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		return
+	}
+	h.closed = true
+	close(h.queue)
+	h.mu.Unlock()
+
+	<-h.done
+}
+
+// run passes the queued entries to the hook until the queue is closed.
+func (h *asyncHook) run() {
+	defer close(h.done)
+	for e := range h.queue {
+		h.fn(e.entry, e.fields)
+	}
+}
@@ -0,0 +1,122 @@
+package logger_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/junkd0g/go-microservice-commons/logger"
+)
+
+// hookRecorder is a Hook recording the messages and fields of the entries it receives.
+type hookRecorder struct {
+	mu       sync.Mutex
+	messages []string
+	fields   []map[string]interface{}
+}
+
+// hook records the entry.
+func (r *hookRecorder) hook(entry zapcore.Entry, fields []zapcore.Field) {
+	encoder := zapcore.NewMapObjectEncoder()
+	for _, field := range fields {
+		field.AddTo(encoder)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = append(r.messages, entry.Message)
+	r.fields = append(r.fields, encoder.Fields)
+}
+
+// recorded returns the messages recorded so far.
+func (r *hookRecorder) recorded() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.messages...)
+}
+
+func TestRegisterHook(t *testing.T) {
+	log, err := logger.NewLogger()
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+	core, recorded := observer.New(zapcore.InfoLevel)
+	log.SetCore(core)
+
+	hook := &hookRecorder{}
+	log.RegisterHook(zapcore.ErrorLevel, hook.hook)
+
+	log.Info(context.Background(), "Info Message")
+	log.Error(context.Background(), "Error Message", map[string]interface{}{"user_id": "user-1"})
+
+	if got := hook.recorded(); len(got) != 1 || got[0] != "Error Message" {
+		t.Fatalf("Expected the hook to fire for the error only, got %v", got)
+	}
+	if hook.fields[0]["user_id"] != "user-1" {
+		t.Errorf("Expected the hook to receive the entry fields, got %v", hook.fields[0])
+	}
+
+	// The regular output is unchanged
+	if recorded.Len() != 2 {
+		t.Errorf("Expected 2 log entries, got %d", recorded.Len())
+	}
+}
+
+func TestRegisterHookBuffered(t *testing.T) {
+	log, err := logger.NewLogger()
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+	core, _ := observer.New(zapcore.InfoLevel)
+	log.SetCore(core)
+
+	release := make(chan struct{})
+	hook := &hookRecorder{}
+	log.RegisterHook(zapcore.ErrorLevel, func(entry zapcore.Entry, fields []zapcore.Field) {
+		<-release
+		hook.hook(entry, fields)
+	}, logger.WithHookBuffer(10))
+
+	// Logging does not wait for the hook
+	log.Error(context.Background(), "First Error", map[string]interface{}{"attempt": 1})
+	log.Error(context.Background(), "Second Error", map[string]interface{}{"attempt": 2})
+	log.Info(context.Background(), "Info Message")
+
+	close(release)
+	if err := log.Close(); err != nil {
+		t.Fatalf("Error closing logger: %v", err)
+	}
+
+	got := hook.recorded()
+	if len(got) != 2 || got[0] != "First Error" || got[1] != "Second Error" {
+		t.Fatalf("Expected the queued errors to be drained by Close, got %v", got)
+	}
+	if hook.fields[1]["attempt"] != int64(2) {
+		t.Errorf("Expected the hook to receive a copy of the entry fields, got %v", hook.fields[1])
+	}
+}
+
+func TestRegisterHookFromZap(t *testing.T) {
+	core, _ := observer.New(zapcore.InfoLevel)
+	log := logger.FromZap(zap.New(core).With(zap.String("service", "users")))
+
+	hook := &hookRecorder{}
+	log.RegisterHook(zapcore.WarnLevel, hook.hook)
+
+	log.Warn(context.Background(), "Warn Message")
+
+	if got := hook.recorded(); len(got) != 1 || got[0] != "Warn Message" {
+		t.Fatalf("Expected the hook to fire for the warning, got %v", got)
+	}
+}
+
+func TestRegisterHookNilLogger(t *testing.T) {
+	var log *logger.Logger
+
+	// Registering a hook on a nil logger does nothing
+	log.RegisterHook(zapcore.ErrorLevel, func(zapcore.Entry, []zapcore.Field) {})
+}
@@ -5,7 +5,9 @@ package logger
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -18,74 +20,484 @@ type LogField map[string]interface{}
 
 // Logger encapsulates an instance of zap's logger with custom functionalities.
 type Logger struct {
-	logger *zap.Logger
+	logger         *zap.Logger
+	durationFormat DurationFormat
+	defaults       []map[string]interface{}
+	bufferSize     int
+	logEmail       bool
+	contextID      bool
+	stdLevel       zapcore.Level
+	fieldTypes     map[string]FieldConstructor
+	maskers        map[string]Masker
+	asyncWriters   []*asyncWriter
+	socketWriter   *socketWriter
+	closeOutput    func()
+	sampler        *contextSampler
+	throttler      *errorThrottler
+	hooks          *hookRegistry
 }
 
 // NewLogger initializes and returns a new instance of Logger with predefined configurations.
-func NewLogger() (*Logger, error) {
+// Options can be passed to customize how and where entries are written.
+func NewLogger(opts ...Option) (*Logger, error) {
+	o := &loggerOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
 	config := zap.NewProductionConfig()
 
 	// Set the desired logging level and control stack trace settings
 	config.Level = zap.NewAtomicLevelAt(zapcore.InfoLevel)
 	config.DisableStacktrace = true
 
+	// Context sampling replaces the global sampling by level and message
+	if o.sampler != nil {
+		config.Sampling = nil
+	}
+
+	// Render zap.Duration fields as human readable strings (e.g. "1.5s")
+	config.EncoderConfig.EncodeDuration = zapcore.StringDurationEncoder
+	config.EncoderConfig.EncodeLevel = o.levelFormat.levelEncoder()
+	if o.levelColors != nil {
+		config.EncoderConfig.EncodeLevel = levelColorEncoder(o.levelColors)
+	}
+
+	if o.console {
+		config.Encoding = "console"
+		config.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	}
+
+	zapOptions, err := o.buildZapOptions(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create logger: %w", err)
+	}
+
+	// Wrap the outputs last so hooks see the entries of every core
+	hooks := &hookRegistry{}
+	zapOptions = append(zapOptions, zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return newHookCore(core, hooks)
+	}))
+
 	// Initialize the logger with the given configuration
-	logger, err := config.Build()
+	logger, err := config.Build(zapOptions...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create logger: %w", err)
 	}
 
+	l := &Logger{
+		logger:         logger,
+		durationFormat: o.durationFormat,
+		defaults:       o.defaultFields(),
+		bufferSize:     o.bufferSize,
+		logEmail:       o.logEmail,
+		contextID:      o.contextID,
+		stdLevel:       o.stdLevel,
+		fieldTypes:     o.fieldTypes,
+		maskers:        o.maskers,
+		asyncWriters:   o.asyncWriters,
+		socketWriter:   o.socketWriter,
+		closeOutput:    o.closeOutput,
+		sampler:        o.sampler,
+		hooks:          hooks,
+	}
+
+	if o.throttleWindow > 0 {
+		l.throttler = newErrorThrottler(o.throttleWindow, o.throttleKeyFields, l.logSummary)
+	}
+
+	return l, nil
+}
+
+// NewLoggerWithDefaults initializes a new Logger that adds the given default
+// fields, such as service, version and env, to every entry.
+func NewLoggerWithDefaults(defaults map[string]interface{}, opts ...Option) (*Logger, error) {
+	return NewLogger(append(opts, WithDefaults(defaults))...)
+}
+
+// FromZap wraps an existing zap logger, keeping its configuration (sampling,
+// hooks, sinks) while adding the context field integration. A nil logger is
+// replaced with a no-op one.
+func FromZap(z *zap.Logger) *Logger {
+	if z == nil {
+		z = zap.NewNop()
+	}
+	hooks := &hookRegistry{}
 	return &Logger{
-		logger: logger,
-	}, nil
+		logger: z.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return newHookCore(core, hooks)
+		})),
+		hooks: hooks,
+	}
 }
 
 // SetCore updates the logger's core, useful for testing and custom configurations.
+// It is a no-op on a nil Logger.
 func (l *Logger) SetCore(core zapcore.Core) {
-	l.logger = zap.New(core)
+	if l == nil {
+		return
+	}
+	l.logger = zap.New(newHookCore(core, l.hooks))
+}
+
+// Sync flushes buffered entries, waiting for those queued by WithAsync to be written.
+func (l *Logger) Sync() error {
+	if l.noop() {
+		return nil
+	}
+	return l.logger.Sync()
+}
+
+// Close logs the pending summaries of WithErrorThrottling, then drains the
+// entries queued by WithAsync, WithSocketOutput and hooks registered with
+// WithHookBuffer and stops the background writers. It also closes the output
+// files the logger opened itself to queue or encode their entries. Entries
+// logged afterwards are written synchronously, or dropped for a socket output
+// and for the output files closed here.
+// It is a no-op for loggers built without these options.
+func (l *Logger) Close() error {
+	if l == nil {
+		return nil
+	}
+
+	if l.throttler != nil {
+		l.throttler.Close()
+	}
+	if l.hooks != nil {
+		l.hooks.Close()
+	}
+
+	var errs []error
+	for _, w := range l.asyncWriters {
+		errs = append(errs, w.Close())
+	}
+	if l.socketWriter != nil {
+		errs = append(errs, l.socketWriter.Close())
+	}
+	if l.closeOutput != nil {
+		l.closeOutput()
+		l.closeOutput = nil
+	}
+	return errors.Join(errs...)
+}
+
+// Dropped returns the number of entries dropped so far because the queue of
+// WithAsync or the buffer of WithSocketOutput was full, e.g. to export it as a
+// metric. It is always zero with the blocking overflow policies.
+func (l *Logger) Dropped() uint64 {
+	if l == nil {
+		return 0
+	}
+
+	var dropped uint64
+	for _, w := range l.asyncWriters {
+		dropped += w.dropped.Load()
+	}
+	if l.socketWriter != nil {
+		dropped += l.socketWriter.droppedCount()
+	}
+	return dropped
+}
+
+// Level returns the minimum enabled logging level of the logger, or
+// zapcore.InvalidLevel for a nil or zero value Logger.
+func (l *Logger) Level() zapcore.Level {
+	if l.noop() {
+		return zapcore.InvalidLevel
+	}
+	return l.logger.Level()
+}
+
+// Enabled reports whether entries at the given level would be logged, so callers
+// can skip building expensive fields for disabled levels.
+func (l *Logger) Enabled(level zapcore.Level) bool {
+	if l.noop() {
+		return false
+	}
+	// The core is asked directly, as checking an entry would go through sampling
+	return l.logger.Core().Enabled(level)
+}
+
+// sampled reports whether the entry passes the context sampling, if enabled.
+func (l *Logger) sampled(ctx context.Context, level zapcore.Level, msg string) bool {
+	return l.sampler == nil || l.sampler.sample(ctx, level, msg)
+}
+
+// noop reports whether the logger is nil or a zero value Logger without a zap
+// logger, on which the logging methods do nothing instead of panicking.
+func (l *Logger) noop() bool {
+	return l == nil || l.logger == nil
 }
 
 // Info logs an informational message and extracts additional fields from the context, if present.
+//
+// Fields are merged into a single set before logging so every key appears once.
+// Precedence from lowest to highest is: default fields, registered context
+// extractors, the authenticated user in the context (see goctx.WithUser, set
+// by auth.WithClaims), the context ID (see WithContextID), context fields and
+// per-call maps, later maps overriding earlier ones. Typed fields from the
+// context are appended as is, without deduplication.
+//
+// When the context carries an entry buffer (see WithEntryBuffer) the entry is
+// held back until an error is logged with the same context.
+//
+// Nil field maps are ignored, and logging on a nil or zero value Logger does nothing.
 func (l *Logger) Info(ctx context.Context, msg string, fields ...map[string]interface{}) {
-	// Extract additional fields from the context, if available
-	if mutableFields, ok := ctx.Value(goctx.ContextKeyLoggerFields).(*goctx.MutableFields); ok {
-		extraFields := mutableFields.GetFields()
-		fields = append(fields, extraFields...)
+	if l.noop() || !l.sampled(ctx, zapcore.InfoLevel, msg) {
+		return
 	}
 
-	// Convert custom fields to zap fields and log the message
-	zapFields := convertToZapFields(fields...)
-	l.logger.Info(msg, zapFields...)
+	// Convert context and custom fields to zap fields and log the message
+	zapFields := l.entryFields(ctx, fields)
+	if buf := l.entryBuffer(ctx); buf != nil {
+		buf.add(bufferedEntry{
+			level:  zapcore.InfoLevel,
+			time:   time.Now(),
+			msg:    msg,
+			fields: append([]zap.Field(nil), *zapFields...),
+		})
+		putFieldBuffer(zapFields)
+		return
+	}
+	l.logger.Info(msg, *zapFields...)
+	putFieldBuffer(zapFields)
+}
+
+// Warn logs a warning message and extracts additional fields from the context, if present.
+// Fields are merged with the same precedence as Info.
+func (l *Logger) Warn(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	if l.noop() || !l.sampled(ctx, zapcore.WarnLevel, msg) {
+		return
+	}
+
+	// Convert context and custom fields to zap fields and log the warning message
+	zapFields := l.entryFields(ctx, fields)
+	l.logger.Warn(msg, *zapFields...)
+	putFieldBuffer(zapFields)
 }
 
 // Error logs an error message and extracts additional fields from the context, if present.
+// Fields are merged with the same precedence as Info. Entries held back in the
+// context's entry buffer are written first. Repeated errors are only counted
+// when throttled with WithErrorThrottling.
 func (l *Logger) Error(ctx context.Context, msg string, fields ...map[string]interface{}) {
-	// Extract additional fields from the context, if available
+	if l.noop() || !l.sampled(ctx, zapcore.ErrorLevel, msg) {
+		return
+	}
+
+	// Convert context and custom fields to zap fields and log the error message
+	zapFields := l.entryFields(ctx, fields)
+	if l.throttler != nil && !l.throttler.allow(msg, *zapFields) {
+		putFieldBuffer(zapFields)
+		return
+	}
+
+	l.flushEntryBuffer(ctx)
+	l.logger.Error(msg, *zapFields...)
+	putFieldBuffer(zapFields)
+}
+
+// logSummary logs the summary of a throttled error along with the default fields.
+func (l *Logger) logSummary(msg string, fields []zap.Field) {
+	if l.noop() {
+		return
+	}
+	l.logger.Error(msg, append(l.convertToZapFields(nil, l.defaults), fields...)...)
+}
+
+// Fielder is implemented by structured errors exposing fields to log, such as
+// *apperr.Error.
+type Fielder interface {
+	Fields() map[string]interface{}
+}
+
+// LogError logs err at Error level using the error text as the message. The
+// error's type is attached as "error_type" and the messages of the errors it
+// wraps, outermost first, as "error_chain". When an error in the chain
+// implements Fielder its fields are logged too, overridden by the given
+// fields. It does nothing when err is nil.
+func (l *Logger) LogError(ctx context.Context, err error, fields ...map[string]interface{}) {
+	if err == nil {
+		return
+	}
+	fields = withErrorFields(err, fields)
+
+	errorFields := map[string]interface{}{
+		"error_type": fmt.Sprintf("%T", err),
+	}
+
+	var chain []string
+	for cause := errors.Unwrap(err); cause != nil; cause = errors.Unwrap(cause) {
+		chain = append(chain, cause.Error())
+	}
+	if len(chain) > 0 {
+		errorFields["error_chain"] = chain
+	}
+
+	// Copy on append so the caller's backing array is never written to
+	l.Error(ctx, err.Error(), append(fields[:len(fields):len(fields)], errorFields)...)
+}
+
+// WrapError logs msg at Error level with the error and fields attached, then
+// returns err wrapped as "msg: err" so callers can log and propagate in one
+// statement. Fields of a Fielder in the chain are logged as with LogError. It
+// returns nil and logs nothing when err is nil.
+func (l *Logger) WrapError(ctx context.Context, err error, msg string, fields ...map[string]interface{}) error {
+	if err == nil {
+		return nil
+	}
+	fields = withErrorFields(err, fields)
+
+	// Copy on append so the caller's backing array is never written to
+	l.Error(ctx, msg, append(fields[:len(fields):len(fields)], map[string]interface{}{"error": err.Error()})...)
+	return fmt.Errorf("%s: %w", msg, err)
+}
+
+// TimeIt runs fn and logs its completion under the given name with the elapsed
+// time as "duration", at Info level on success and at Error level with the
+// error attached on failure. It returns fn's error unchanged.
+func (l *Logger) TimeIt(ctx context.Context, name string, fn func() error) error {
+	start := time.Now()
+	err := fn()
+
+	fields := map[string]interface{}{"duration": time.Since(start)}
+	if err != nil {
+		fields["error"] = err.Error()
+		l.Error(ctx, name, fields)
+		return err
+	}
+
+	l.Info(ctx, name, fields)
+	return nil
+}
+
+// withErrorFields prepends the fields of the first Fielder in err's chain, if
+// any, so the given fields take precedence over them.
+func withErrorFields(err error, fields []map[string]interface{}) []map[string]interface{} {
+	var fielder Fielder
+	if !errors.As(err, &fielder) {
+		return fields
+	}
+	return append([]map[string]interface{}{fielder.Fields()}, fields...)
+}
+
+// entryFields builds the zap fields of an entry from the default, context and
+// per-call fields into a pooled buffer that must be released with putFieldBuffer
+// once the entry has been logged.
+func (l *Logger) entryFields(ctx context.Context, fields []map[string]interface{}) *[]zap.Field {
+	buf := getFieldBuffer()
+	*buf = l.convertToZapFields(*buf, l.defaults, extractedFields(ctx), l.claimsFields(ctx), l.contextIDFields(ctx), contextFields(ctx), fields)
+
+	if typedFields, ok := TypedFieldsFromContext(ctx); ok {
+		start := len(*buf)
+		*buf = typedFields.appendTo(*buf)
+		if l.maskers != nil {
+			l.maskTyped((*buf)[start:])
+		}
+	}
+
+	return buf
+}
+
+// contextFields extracts additional fields from the context, if available.
+func contextFields(ctx context.Context) []map[string]interface{} {
 	if mutableFields, ok := ctx.Value(goctx.ContextKeyLoggerFields).(*goctx.MutableFields); ok {
-		extraFields := mutableFields.GetFields()
-		fields = append(fields, extraFields...)
+		return mutableFields.GetFields()
 	}
+	return nil
+}
 
-	// Convert custom fields to zap fields and log the error message
-	zapFields := convertToZapFields(fields...)
-	l.logger.Error(msg, zapFields...)
+// contextIDFields returns the ID of the MutableFields in the context when
+// enabled with WithContextID.
+func (l *Logger) contextIDFields(ctx context.Context) []map[string]interface{} {
+	if !l.contextID {
+		return nil
+	}
+	if mutableFields, ok := ctx.Value(goctx.ContextKeyLoggerFields).(*goctx.MutableFields); ok {
+		return []map[string]interface{}{{FieldContextID: mutableFields.ID()}}
+	}
+	return nil
 }
 
-// convertToZapFields transforms custom log fields into zap-compatible fields.
-// It currently supports fields of type string and int.
-func convertToZapFields(fields ...map[string]interface{}) []zap.Field {
-	var zapFields []zap.Field
+// convertToZapFields transforms custom log fields into zap-compatible fields and
+// appends them to dst. The field sets are merged in order, so a key in a later
+// map overrides the same key in an earlier one, and nil maps are skipped. Keys
+// registered with WithFieldType are built by their constructor. Other keys
+// currently support values of type string, []string, int and time.Duration,
+// the latter rendered according to the logger's DurationFormat.
+func (l *Logger) convertToZapFields(dst []zap.Field, fieldSets ...[]map[string]interface{}) []zap.Field {
+	merged := mergeFields(fieldSets...)
+	if len(merged) == 0 {
+		return dst
+	}
+
+	zapFields := dst
 
-	for _, field := range fields {
-		for k, v := range field {
-			switch value := v.(type) {
-			case string:
-				zapFields = append(zapFields, zap.String(k, value))
-			case int:
-				zapFields = append(zapFields, zap.Int(k, value))
-			}
+	for k, v := range merged {
+		if l.maskers != nil {
+			v = l.mask(k, v)
+		}
+
+		if constructor, ok := l.fieldTypes[k]; ok {
+			zapFields = append(zapFields, constructor(k, v))
+			continue
+		}
+
+		switch value := v.(type) {
+		case string:
+			zapFields = append(zapFields, zap.String(k, value))
+		case []string:
+			zapFields = append(zapFields, zap.Strings(k, value))
+		case int:
+			zapFields = append(zapFields, zap.Int(k, value))
+		case time.Duration:
+			zapFields = append(zapFields, l.durationField(k, value))
 		}
 	}
 
 	return zapFields
 }
+
+// mergeFields flattens the field sets into a single map where later maps
+// override earlier ones. When only one non-empty map is given it is returned
+// as is, avoiding an allocation on the common single-map path.
+func mergeFields(fieldSets ...[]map[string]interface{}) map[string]interface{} {
+	var (
+		only  map[string]interface{}
+		count int
+		size  int
+	)
+	for _, fields := range fieldSets {
+		for _, field := range fields {
+			if len(field) == 0 {
+				continue
+			}
+			only = field
+			count++
+			size += len(field)
+		}
+	}
+
+	if count <= 1 {
+		return only
+	}
+
+	merged := make(map[string]interface{}, size)
+	for _, fields := range fieldSets {
+		for _, field := range fields {
+			for k, v := range field {
+				merged[k] = v
+			}
+		}
+	}
+
+	return merged
+}
+
+// durationField converts a duration into a zap field according to the logger's DurationFormat.
+func (l *Logger) durationField(key string, d time.Duration) zap.Field {
+	if l.durationFormat == DurationMilliseconds {
+		return zap.Float64(key+"_ms", float64(d)/float64(time.Millisecond))
+	}
+	return zap.Duration(key, d)
+}
@@ -2,11 +2,16 @@ package logger_test
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync"
 	"testing"
 
+	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"go.uber.org/zap/zaptest/observer"
 
+	goctx "github.com/junkd0g/go-microservice-commons/context"
 	"github.com/junkd0g/go-microservice-commons/logger"
 )
 
@@ -65,3 +70,477 @@ func TestErrorLog(t *testing.T) {
 		t.Fatalf("Expected 2 fields, got %d", len(entries[0].Context))
 	}
 }
+
+func BenchmarkInfo(b *testing.B) {
+	log, err := logger.NewLogger()
+	if err != nil {
+		b.Fatalf("Error creating logger: %v", err)
+	}
+	log.SetCore(zapcore.NewNopCore())
+
+	ctx := context.Background()
+	fields := map[string]interface{}{"key": "value", "number": 1}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		log.Info(ctx, "Info Message", fields)
+	}
+}
+
+func BenchmarkInfoWithContextFields(b *testing.B) {
+	log, err := logger.NewLogger()
+	if err != nil {
+		b.Fatalf("Error creating logger: %v", err)
+	}
+	log.SetCore(zapcore.NewNopCore())
+
+	mutableFields := goctx.NewMutableFields()
+	mutableFields.AddField(map[string]interface{}{"request_id": "abc-123", "user_id": 42})
+	ctx := context.WithValue(context.Background(), goctx.ContextKeyLoggerFields, mutableFields)
+	fields := map[string]interface{}{"key": "value", "number": 1}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		log.Info(ctx, "Info Message", fields)
+	}
+}
+
+func TestLogError(t *testing.T) {
+	log, err := logger.NewLogger()
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+
+	core, recorded := observer.New(zapcore.ErrorLevel)
+	log.SetCore(core)
+
+	cause := errors.New("connection refused")
+	wrapped := fmt.Errorf("querying users: %w", fmt.Errorf("dialing db: %w", cause))
+
+	log.LogError(context.Background(), wrapped, map[string]interface{}{"user_id": 7})
+	log.LogError(context.Background(), nil)
+
+	entries := recorded.All()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 log entry, got %d", len(entries))
+	}
+
+	if entries[0].Message != wrapped.Error() {
+		t.Errorf("Unexpected message: %s", entries[0].Message)
+	}
+
+	fields := entries[0].ContextMap()
+	if fields["error_type"] != "*fmt.wrapError" {
+		t.Errorf("Unexpected error_type: %v", fields["error_type"])
+	}
+	if fields["user_id"] != int64(7) {
+		t.Errorf("Unexpected user_id: %v", fields["user_id"])
+	}
+
+	chain, ok := fields["error_chain"].([]interface{})
+	if !ok || len(chain) != 2 {
+		t.Fatalf("Expected an error chain of 2, got %v", fields["error_chain"])
+	}
+	if chain[0] != "dialing db: connection refused" || chain[1] != "connection refused" {
+		t.Errorf("Unexpected error chain: %v", chain)
+	}
+}
+
+func TestLogErrorKeepsCallerFields(t *testing.T) {
+	log, err := logger.NewLogger()
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+
+	core, _ := observer.New(zapcore.ErrorLevel)
+	log.SetCore(core)
+
+	// A slice with spare capacity must not be appended to in place
+	fields := []map[string]interface{}{{"user_id": 7}, {"order_id": 9}}
+	log.LogError(context.Background(), errors.New("failure"), fields[:1]...)
+
+	if _, ok := fields[1]["order_id"]; !ok || len(fields[1]) != 1 {
+		t.Errorf("Expected the caller's fields to be left untouched, got %v", fields[1])
+	}
+}
+
+// fieldError is an error exposing structured fields.
+type fieldError struct {
+	fields map[string]interface{}
+}
+
+func (e *fieldError) Error() string { return "field error" }
+
+func (e *fieldError) Fields() map[string]interface{} { return e.fields }
+
+func TestLogErrorFielder(t *testing.T) {
+	log, err := logger.NewLogger()
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+
+	core, recorded := observer.New(zapcore.ErrorLevel)
+	log.SetCore(core)
+
+	fielder := &fieldError{fields: map[string]interface{}{"error_code": "not_found", "status": 404}}
+
+	log.LogError(context.Background(), fmt.Errorf("loading user: %w", fielder), map[string]interface{}{"status": 410})
+	_ = log.WrapError(context.Background(), fielder, "loading user")
+	log.LogError(context.Background(), errors.New("plain error"))
+
+	entries := recorded.All()
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 log entries, got %d", len(entries))
+	}
+
+	fields := entries[0].ContextMap()
+	if fields["error_code"] != "not_found" {
+		t.Errorf("Expected error_code from the wrapped error, got %v", fields["error_code"])
+	}
+	if fields["status"] != int64(410) {
+		t.Errorf("Expected per-call field to override the error's, got %v", fields["status"])
+	}
+
+	if got := entries[1].ContextMap()["error_code"]; got != "not_found" {
+		t.Errorf("Expected WrapError to log error_code, got %v", got)
+	}
+
+	if _, ok := entries[2].ContextMap()["error_code"]; ok {
+		t.Errorf("Unexpected error_code for an error without fields")
+	}
+}
+
+func TestFieldPrecedence(t *testing.T) {
+	log, err := logger.NewLogger()
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+
+	core, recorded := observer.New(zapcore.InfoLevel)
+	log.SetCore(core)
+
+	mutableFields := goctx.NewMutableFields()
+	mutableFields.AddField(map[string]interface{}{"source": "context", "request_id": "abc-123"})
+	ctx := context.WithValue(context.Background(), goctx.ContextKeyLoggerFields, mutableFields)
+
+	log.Info(ctx, "Info Message",
+		map[string]interface{}{"key": "first", "number": 1},
+		map[string]interface{}{"key": "second", "source": "call"},
+	)
+
+	entries := recorded.All()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 log entry, got %d", len(entries))
+	}
+
+	// Every key must appear exactly once.
+	if len(entries[0].Context) != 4 {
+		t.Fatalf("Expected 4 fields, got %d", len(entries[0].Context))
+	}
+
+	fields := entries[0].ContextMap()
+	if fields["key"] != "second" {
+		t.Errorf("Expected later map to override earlier one, got %v", fields["key"])
+	}
+	if fields["source"] != "call" {
+		t.Errorf("Expected call fields to override context fields, got %v", fields["source"])
+	}
+	if fields["request_id"] != "abc-123" || fields["number"] != int64(1) {
+		t.Errorf("Unexpected fields: %v", fields)
+	}
+}
+
+func TestLevel(t *testing.T) {
+	log, err := logger.NewLogger()
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+
+	if log.Level() != zapcore.InfoLevel {
+		t.Errorf("Expected info level, got %v", log.Level())
+	}
+
+	if log.Enabled(zapcore.DebugLevel) {
+		t.Error("Expected debug to be disabled at the default level")
+	}
+	if !log.Enabled(zapcore.InfoLevel) || !log.Enabled(zapcore.ErrorLevel) {
+		t.Error("Expected info and error to be enabled at the default level")
+	}
+
+	core, _ := observer.New(zapcore.ErrorLevel)
+	log.SetCore(core)
+
+	if log.Level() != zapcore.ErrorLevel || log.Enabled(zapcore.InfoLevel) {
+		t.Error("Expected the level to follow the core")
+	}
+}
+
+func TestEnabledIgnoresSampling(t *testing.T) {
+	log, err := logger.NewLogger()
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+
+	// The default sampling keeps the first 100 entries per second of a message
+	for i := 0; i < 1000; i++ {
+		if !log.Enabled(zapcore.InfoLevel) {
+			t.Fatalf("Expected info to be enabled on call %d", i+1)
+		}
+	}
+}
+
+func TestFromZap(t *testing.T) {
+	core, recorded := observer.New(zapcore.InfoLevel)
+	log := logger.FromZap(zap.New(core).With(zap.String("org", "acme")))
+
+	mutableFields := goctx.NewMutableFields()
+	mutableFields.AddField(map[string]interface{}{"request_id": "abc-123"})
+	ctx := goctx.WithMutableFields(context.Background(), mutableFields)
+
+	log.Info(ctx, "Info Message")
+
+	entries := recorded.All()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 log entry, got %d", len(entries))
+	}
+
+	fields := entries[0].ContextMap()
+	if fields["org"] != "acme" || fields["request_id"] != "abc-123" {
+		t.Errorf("Expected zap and context fields, got %v", fields)
+	}
+
+	// SetCore still works on the wrapped instance.
+	newCore, newRecorded := observer.New(zapcore.InfoLevel)
+	log.SetCore(newCore)
+	log.Info(ctx, "Info Message")
+
+	if recorded.Len() != 1 || newRecorded.Len() != 1 {
+		t.Errorf("Expected the entry on the new core only, got %d and %d", recorded.Len(), newRecorded.Len())
+	}
+
+	// A nil zap logger does not panic.
+	logger.FromZap(nil).Info(ctx, "Info Message")
+}
+
+func TestWarnLog(t *testing.T) {
+	log, err := logger.NewLogger()
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+
+	core, recorded := observer.New(zapcore.WarnLevel)
+	log.SetCore(core)
+
+	log.Info(context.Background(), "Info Message")
+	log.Warn(context.Background(), "Warn Message", map[string]interface{}{"key": "value"})
+
+	entries := recorded.All()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 log entry, got %d", len(entries))
+	}
+
+	if entries[0].Message != "Warn Message" || entries[0].Level != zapcore.WarnLevel {
+		t.Errorf("Unexpected entry: %s at %v", entries[0].Message, entries[0].Level)
+	}
+}
+
+func TestConcurrentLogging(t *testing.T) {
+	log, err := logger.NewLogger()
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+
+	core, recorded := observer.New(zapcore.InfoLevel)
+	log.SetCore(core)
+
+	const goroutines, perGoroutine = 8, 100
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < perGoroutine; i++ {
+				log.Info(context.Background(), "Info Message", map[string]interface{}{"goroutine": g, "iteration": i})
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	entries := recorded.All()
+	if len(entries) != goroutines*perGoroutine {
+		t.Fatalf("Expected %d log entries, got %d", goroutines*perGoroutine, len(entries))
+	}
+
+	// Pooled buffers must not leak fields from one entry into another.
+	seen := make(map[[2]int64]bool, len(entries))
+	for _, entry := range entries {
+		fields := entry.ContextMap()
+		if len(fields) != 2 {
+			t.Fatalf("Expected 2 fields, got %v", fields)
+		}
+		key := [2]int64{fields["goroutine"].(int64), fields["iteration"].(int64)}
+		if seen[key] {
+			t.Fatalf("Duplicate entry %v", key)
+		}
+		seen[key] = true
+	}
+}
+
+func TestWrapError(t *testing.T) {
+	log, err := logger.NewLogger()
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+
+	core, recorded := observer.New(zapcore.InfoLevel)
+	log.SetCore(core)
+
+	cause := errors.New("connection refused")
+	wrapped := log.WrapError(context.Background(), cause, "loading user", map[string]interface{}{"user_id": 7})
+
+	if !errors.Is(wrapped, cause) {
+		t.Errorf("Expected the wrap chain to be preserved")
+	}
+	if wrapped.Error() != "loading user: connection refused" {
+		t.Errorf("Unexpected error: %s", wrapped.Error())
+	}
+
+	if log.WrapError(context.Background(), nil, "loading user") != nil {
+		t.Errorf("Expected nil for a nil error")
+	}
+
+	entries := recorded.All()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 log entry, got %d", len(entries))
+	}
+
+	fields := entries[0].ContextMap()
+	if entries[0].Message != "loading user" || entries[0].Level != zapcore.ErrorLevel {
+		t.Errorf("Unexpected entry: %s at %v", entries[0].Message, entries[0].Level)
+	}
+	if fields["error"] != "connection refused" || fields["user_id"] != int64(7) {
+		t.Errorf("Unexpected fields: %v", fields)
+	}
+}
+
+func TestWrapErrorKeepsCallerFields(t *testing.T) {
+	log, err := logger.NewLogger()
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+
+	core, _ := observer.New(zapcore.ErrorLevel)
+	log.SetCore(core)
+
+	// A slice with spare capacity must not be appended to in place
+	fields := []map[string]interface{}{{"user_id": 7}, {"order_id": 9}}
+	_ = log.WrapError(context.Background(), errors.New("failure"), "loading user", fields[:1]...)
+
+	if _, ok := fields[1]["order_id"]; !ok || len(fields[1]) != 1 {
+		t.Errorf("Expected the caller's fields to be left untouched, got %v", fields[1])
+	}
+}
+
+func TestTimeIt(t *testing.T) {
+	log, err := logger.NewLogger()
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+	core, recorded := observer.New(zapcore.InfoLevel)
+	log.SetCore(core)
+
+	mutableFields := goctx.NewMutableFields()
+	mutableFields.AddField(map[string]interface{}{"request_id": "abc-123"})
+	ctx := goctx.WithMutableFields(context.Background(), mutableFields)
+
+	if err := log.TimeIt(ctx, "load user", func() error { return nil }); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	failure := errors.New("connection refused")
+	if err := log.TimeIt(ctx, "save user", func() error { return failure }); err != failure {
+		t.Errorf("Expected fn's error to be returned unchanged, got %v", err)
+	}
+
+	entries := recorded.All()
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 log entries, got %d", len(entries))
+	}
+
+	success, failed := entries[0], entries[1]
+	if success.Message != "load user" || success.Level != zapcore.InfoLevel {
+		t.Errorf("Unexpected success entry: %s at %s", success.Message, success.Level)
+	}
+	if _, ok := success.ContextMap()["duration"]; !ok {
+		t.Errorf("Expected a duration field, got %v", success.ContextMap())
+	}
+	if success.ContextMap()["request_id"] != "abc-123" {
+		t.Errorf("Expected context fields, got %v", success.ContextMap())
+	}
+
+	if failed.Message != "save user" || failed.Level != zapcore.ErrorLevel {
+		t.Errorf("Unexpected failure entry: %s at %s", failed.Message, failed.Level)
+	}
+	if failed.ContextMap()["error"] != "connection refused" {
+		t.Errorf("Expected the error field, got %v", failed.ContextMap()["error"])
+	}
+}
+
+func TestNilSafety(t *testing.T) {
+	t.Run("nil field maps", func(t *testing.T) {
+		log, err := logger.NewLogger()
+		if err != nil {
+			t.Fatalf("Error creating logger: %v", err)
+		}
+		core, recorded := observer.New(zapcore.InfoLevel)
+		log.SetCore(core)
+
+		log.Info(context.Background(), "Info Message", nil, map[string]interface{}{"key": "value"}, nil)
+		log.Error(context.Background(), "Error Message", nil)
+
+		entries := recorded.All()
+		if len(entries) != 2 {
+			t.Fatalf("Expected 2 log entries, got %d", len(entries))
+		}
+		if len(entries[0].Context) != 1 {
+			t.Errorf("Expected 1 field, got %d", len(entries[0].Context))
+		}
+	})
+
+	loggers := map[string]*logger.Logger{
+		"nil receiver": nil,
+		"zero value":   {},
+	}
+	for name, log := range loggers {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			log.Info(ctx, "Info Message", nil)
+			log.Warn(ctx, "Warn Message")
+			log.Error(ctx, "Error Message")
+			log.LogError(ctx, errors.New("some error"))
+
+			if err := log.WrapError(ctx, errors.New("some error"), "loading user"); err == nil || err.Error() != "loading user: some error" {
+				t.Errorf("Unexpected wrapped error: %v", err)
+			}
+			if log.Enabled(zapcore.ErrorLevel) {
+				t.Errorf("Expected no level to be enabled")
+			}
+			if log.Level() != zapcore.InvalidLevel {
+				t.Errorf("Unexpected level: %s", log.Level())
+			}
+			if log.WithEntryBuffer(ctx) != ctx {
+				t.Errorf("Expected the context to be returned unchanged")
+			}
+			if err := log.Sync(); err != nil {
+				t.Errorf("Unexpected error syncing: %v", err)
+			}
+			if err := log.Close(); err != nil {
+				t.Errorf("Unexpected error closing: %v", err)
+			}
+		})
+	}
+}
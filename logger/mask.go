@@ -0,0 +1,128 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// maskPlaceholder replaces the masked part of values.
+const maskPlaceholder = "***"
+
+// Masker partially hides a personal value before it is logged, e.g. MaskEmail.
+type Masker func(value string) string
+
+// WithFieldMasking masks the values of the given fields with their Masker
+// before they are logged, e.g.
+//
+//	logger.WithFieldMasking(map[string]logger.Masker{
+//		"email": logger.MaskEmail,
+//		"phone": logger.MaskPhone,
+//	})
+//
+// Masking applies to fields from every source, including the context, the
+// TypedFields and the claims email of WithClaimsEmail. Values that are not
+// strings, such as a fmt.Stringer, a *string or a []byte, are converted to
+// their string form and logged as strings once masked. Calling it more than
+// once merges the
+// maskers, later ones winning for the same key. Values are logged as is by
+// default.
+func WithFieldMasking(maskers map[string]Masker) Option {
+	return func(o *loggerOptions) {
+		if o.maskers == nil {
+			o.maskers = make(map[string]Masker, len(maskers))
+		}
+		for key, masker := range maskers {
+			o.maskers[key] = masker
+		}
+	}
+}
+
+// MaskEmail keeps the first character of the local part and the domain of an
+// email address: "alice@example.com" becomes "a***@example.com". Values that
+// are not email addresses are fully masked.
+func MaskEmail(value string) string {
+	local, domain, ok := strings.Cut(value, "@")
+	if !ok || local == "" {
+		return maskPlaceholder
+	}
+	// Keep the first rune, not byte, so the result stays valid UTF-8
+	_, size := utf8.DecodeRuneInString(local)
+	return local[:size] + maskPlaceholder + "@" + domain
+}
+
+// MaskPhone keeps the formatting and last four digits of a phone number,
+// masking the other digits: "+44 7700 900123" becomes "+** **** **0123".
+// Numbers with four digits or less are fully masked.
+func MaskPhone(value string) string {
+	digits := 0
+	for _, r := range value {
+		if r >= '0' && r <= '9' {
+			digits++
+		}
+	}
+	if digits <= 4 {
+		return maskPlaceholder
+	}
+
+	masked := []byte(value)
+	toMask := digits - 4
+	for i := 0; i < len(masked) && toMask > 0; i++ {
+		if masked[i] >= '0' && masked[i] <= '9' {
+			masked[i] = '*'
+			toMask--
+		}
+	}
+	return string(masked)
+}
+
+// mask returns the value of the field, masked if a Masker is registered for its key.
+func (l *Logger) mask(key string, value interface{}) interface{} {
+	masker, ok := l.maskers[key]
+	if !ok {
+		return value
+	}
+
+	switch v := value.(type) {
+	case string:
+		return masker(v)
+	case *string:
+		if v == nil {
+			return value
+		}
+		return masker(*v)
+	case []byte:
+		return masker(string(v))
+	case fmt.Stringer:
+		return masker(v.String())
+	case nil:
+		return value
+	default:
+		return masker(fmt.Sprint(v))
+	}
+}
+
+// maskTyped masks the typed fields registered with a Masker in place, logging
+// them as strings.
+func (l *Logger) maskTyped(fields []zap.Field) {
+	for i, field := range fields {
+		masker, ok := l.maskers[field.Key]
+		if !ok {
+			continue
+		}
+		fields[i] = zap.String(field.Key, masker(typedFieldString(field)))
+	}
+}
+
+// typedFieldString returns the string form of a typed field's value.
+func typedFieldString(field zap.Field) string {
+	if field.Type == zapcore.StringType {
+		return field.String
+	}
+	encoder := zapcore.NewMapObjectEncoder()
+	field.AddTo(encoder)
+	return fmt.Sprint(encoder.Fields[field.Key])
+}
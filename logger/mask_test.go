@@ -0,0 +1,143 @@
+package logger_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/junkd0g/go-microservice-commons/logger"
+)
+
+func TestMaskEmail(t *testing.T) {
+	testCases := map[string]string{
+		"alice@example.com": "a***@example.com",
+		"a@example.com":     "a***@example.com",
+		"élise@example.com": "é***@example.com",
+		"李雷@example.cn":     "李***@example.cn",
+		"@example.com":      "***",
+		"not-an-email":      "***",
+		"":                  "***",
+	}
+
+	for value, expected := range testCases {
+		if got := logger.MaskEmail(value); got != expected {
+			t.Errorf("MaskEmail(%q) = %q, expected %q", value, got, expected)
+		}
+	}
+}
+
+func TestMaskPhone(t *testing.T) {
+	testCases := map[string]string{
+		"+44 7700 900123": "+** **** **0123",
+		"07700900123":     "*******0123",
+		"1234":            "***",
+		"":                "***",
+	}
+
+	for value, expected := range testCases {
+		if got := logger.MaskPhone(value); got != expected {
+			t.Errorf("MaskPhone(%q) = %q, expected %q", value, got, expected)
+		}
+	}
+}
+
+func TestFieldMasking(t *testing.T) {
+	log, err := logger.NewLogger(logger.WithFieldMasking(map[string]logger.Masker{
+		"email": logger.MaskEmail,
+		"phone": logger.MaskPhone,
+		"name":  strings.ToUpper,
+	}))
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+
+	core, recorded := observer.New(zapcore.InfoLevel)
+	log.SetCore(core)
+
+	log.Info(context.Background(), "Info Message", map[string]interface{}{
+		"email":   "alice@example.com",
+		"phone":   "+44 7700 900123",
+		"name":    "alice",
+		"user_id": "some-uuid",
+	})
+
+	entries := recorded.All()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 log entry, got %d", len(entries))
+	}
+
+	expected := map[string]interface{}{
+		"email":   "a***@example.com",
+		"phone":   "+** **** **0123",
+		"name":    "ALICE",
+		"user_id": "some-uuid",
+	}
+	fields := entries[0].ContextMap()
+	for key, value := range expected {
+		if fields[key] != value {
+			t.Errorf("Expected %s to be %v, got %v", key, value, fields[key])
+		}
+	}
+}
+
+// maskedID is a fmt.Stringer logged under a masked key.
+type maskedID string
+
+func (id maskedID) String() string {
+	return string(id)
+}
+
+func TestFieldMaskingNonStringValues(t *testing.T) {
+	log, err := logger.NewLogger(logger.WithFieldMasking(map[string]logger.Masker{
+		"email":   logger.MaskEmail,
+		"phone":   logger.MaskPhone,
+		"token":   strings.ToUpper,
+		"account": strings.ToUpper,
+		"card":    logger.MaskPhone,
+	}))
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+
+	core, recorded := observer.New(zapcore.InfoLevel)
+	log.SetCore(core)
+
+	typedFields := logger.NewTypedFields()
+	typedFields.Add(zap.String("email", "alice@example.com"), zap.Int64("card", 4111111111111111))
+	ctx := logger.WithTypedFields(context.Background(), typedFields)
+
+	phone := "+44 7700 900123"
+	log.Info(ctx, "Info Message", map[string]interface{}{
+		"phone":   &phone,
+		"token":   []byte("secret"),
+		"account": maskedID("acc-1"),
+	})
+
+	entries := recorded.All()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 log entry, got %d", len(entries))
+	}
+
+	expected := map[string]interface{}{
+		"email":   "a***@example.com",
+		"card":    "************1111",
+		"phone":   "+** **** **0123",
+		"token":   "SECRET",
+		"account": "ACC-1",
+	}
+	fields := entries[0].ContextMap()
+	for key, value := range expected {
+		if fields[key] != value {
+			t.Errorf("Expected %s to be %v, got %v", key, value, fields[key])
+		}
+	}
+
+	// The TypedFields of the context are left untouched
+	if got := typedFields.Fields()[0].String; got != "alice@example.com" {
+		t.Errorf("Expected the typed fields to be unchanged, got %v", got)
+	}
+}
@@ -0,0 +1,53 @@
+package logger
+
+import (
+	"context"
+
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+)
+
+var (
+	_ goctx.Logger     = (*MultiLogger)(nil)
+	_ goctx.WarnLogger = (*MultiLogger)(nil)
+)
+
+// MultiLogger fans out every entry to several loggers, e.g. to write to both an
+// old and a new sink while migrating between logging backends. Each wrapped
+// logger applies its own defaults and options, and a failing sink doesn't keep
+// the others from receiving the entry.
+type MultiLogger struct {
+	loggers []*Logger
+}
+
+// NewMultiLogger returns a MultiLogger writing to all the given loggers. Nil
+// loggers are ignored.
+func NewMultiLogger(loggers ...*Logger) *MultiLogger {
+	m := &MultiLogger{loggers: make([]*Logger, 0, len(loggers))}
+	for _, l := range loggers {
+		if l != nil {
+			m.loggers = append(m.loggers, l)
+		}
+	}
+	return m
+}
+
+// Info logs an informational message to all wrapped loggers.
+func (m *MultiLogger) Info(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	for _, l := range m.loggers {
+		l.Info(ctx, msg, fields...)
+	}
+}
+
+// Warn logs a warning message to all wrapped loggers.
+func (m *MultiLogger) Warn(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	for _, l := range m.loggers {
+		l.Warn(ctx, msg, fields...)
+	}
+}
+
+// Error logs an error message to all wrapped loggers.
+func (m *MultiLogger) Error(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	for _, l := range m.loggers {
+		l.Error(ctx, msg, fields...)
+	}
+}
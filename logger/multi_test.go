@@ -0,0 +1,82 @@
+package logger_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/junkd0g/go-microservice-commons/logger"
+)
+
+// failingCore is a core whose writes always fail.
+type failingCore struct {
+	zapcore.LevelEnabler
+}
+
+func (c failingCore) With([]zapcore.Field) zapcore.Core { return c }
+
+func (c failingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(ent, c)
+}
+
+func (c failingCore) Write(zapcore.Entry, []zapcore.Field) error { return errors.New("sink unavailable") }
+
+func (c failingCore) Sync() error { return nil }
+
+func TestMultiLogger(t *testing.T) {
+	oldLog, err := logger.NewLogger()
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+	oldCore, oldRecorded := observer.New(zapcore.InfoLevel)
+	oldLog.SetCore(oldCore)
+
+	newLog, err := logger.NewLogger()
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+	newCore, newRecorded := observer.New(zapcore.WarnLevel)
+	newLog.SetCore(newCore)
+
+	multi := logger.NewMultiLogger(oldLog, nil, newLog)
+
+	multi.Info(context.Background(), "Info Message", map[string]interface{}{"key": "value"})
+	multi.Warn(context.Background(), "Warn Message")
+	multi.Error(context.Background(), "Error Message")
+
+	if got := oldRecorded.Len(); got != 3 {
+		t.Fatalf("Expected 3 entries in the old sink, got %d", got)
+	}
+	if got := oldRecorded.FilterField(zapcore.Field{Key: "key", Type: zapcore.StringType, String: "value"}).Len(); got != 1 {
+		t.Errorf("Expected 1 entry with the per-call field, got %d", got)
+	}
+
+	// The new sink only records warnings and errors
+	if got := newRecorded.Len(); got != 2 {
+		t.Fatalf("Expected 2 entries in the new sink, got %d", got)
+	}
+}
+
+func TestMultiLoggerFailingSink(t *testing.T) {
+	failing, err := logger.NewLogger()
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+	failing.SetCore(failingCore{zapcore.InfoLevel})
+
+	working, err := logger.NewLogger()
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+	core, recorded := observer.New(zapcore.InfoLevel)
+	working.SetCore(core)
+
+	logger.NewMultiLogger(failing, working).Error(context.Background(), "Error Message")
+
+	if got := recorded.Len(); got != 1 {
+		t.Fatalf("Expected the working sink to receive 1 entry, got %d", got)
+	}
+}
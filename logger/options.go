@@ -0,0 +1,283 @@
+package logger
+
+import (
+	"net"
+	"os"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Keys of the fields added by WithHostInfo.
+const (
+	FieldHostname = "hostname"
+	FieldPID      = "pid"
+)
+
+// FieldContextID is the key of the field added by WithContextID.
+const FieldContextID = "context_id"
+
+// samplingTick mirrors the sampling interval zap applies when building from a zap.Config.
+const samplingTick = time.Second
+
+// DurationFormat controls how time.Duration field values are rendered.
+type DurationFormat int
+
+const (
+	// DurationString renders durations as human readable strings such as "1.5s".
+	// This is the default.
+	DurationString DurationFormat = iota
+	// DurationMilliseconds renders durations as float milliseconds under the
+	// original key suffixed with "_ms", e.g. "latency_ms":1500.
+	DurationMilliseconds
+)
+
+// LevelFormat controls how the level of entries is rendered.
+type LevelFormat int
+
+const (
+	// LevelLowercase renders levels in lowercase, e.g. "info". This is the default.
+	LevelLowercase LevelFormat = iota
+	// LevelCapital renders levels in uppercase, e.g. "INFO".
+	LevelCapital
+	// LevelCapitalColor renders levels in uppercase wrapped in ANSI color codes,
+	// meant for console output read by humans.
+	LevelCapitalColor
+)
+
+// levelEncoder returns the zap encoder rendering levels in the format.
+func (f LevelFormat) levelEncoder() zapcore.LevelEncoder {
+	switch f {
+	case LevelCapital:
+		return zapcore.CapitalLevelEncoder
+	case LevelCapitalColor:
+		return zapcore.CapitalColorLevelEncoder
+	}
+	return zapcore.LowercaseLevelEncoder
+}
+
+// Option configures the Logger built by NewLogger.
+type Option func(*loggerOptions)
+
+// loggerOptions holds the settings collected from the Options passed to NewLogger.
+type loggerOptions struct {
+	infoOutput     zapcore.WriteSyncer
+	errorOutput    zapcore.WriteSyncer
+	durationFormat DurationFormat
+	levelFormat    LevelFormat
+	defaults       map[string]interface{}
+	bufferSize     int
+	logEmail       bool
+	contextID      bool
+	stdLevel       zapcore.Level
+	fieldTypes     map[string]FieldConstructor
+	maskers        map[string]Masker
+
+	console         bool
+	consoleFieldCap int
+	levelColors     map[zapcore.Level]Color
+
+	asyncBufferSize    int
+	asyncFlushInterval time.Duration
+	asyncPolicy        AsyncOverflowPolicy
+	asyncWriters       []*asyncWriter
+	closeOutput        func()
+
+	socketDial       func() (net.Conn, error)
+	socketBufferSize int
+	socketPolicy     SocketOverflowPolicy
+	socketWriter     *socketWriter
+
+	sampler *contextSampler
+
+	throttleWindow    time.Duration
+	throttleKeyFields []string
+}
+
+// WithLevelRouting routes debug and info entries to infoOutput and warn and
+// above to errorOutput. Without it every entry is written to stderr.
+func WithLevelRouting(infoOutput, errorOutput zapcore.WriteSyncer) Option {
+	return func(o *loggerOptions) {
+		o.infoOutput = infoOutput
+		o.errorOutput = errorOutput
+	}
+}
+
+// WithStdoutStderrRouting routes debug and info entries to stdout and warn
+// and above to stderr, as expected by most container log collectors.
+func WithStdoutStderrRouting() Option {
+	return WithLevelRouting(zapcore.Lock(os.Stdout), zapcore.Lock(os.Stderr))
+}
+
+// WithDurationFormat sets how time.Duration field values are rendered.
+// Durations are rendered as strings (DurationString) by default.
+func WithDurationFormat(format DurationFormat) Option {
+	return func(o *loggerOptions) {
+		o.durationFormat = format
+	}
+}
+
+// WithLevelFormat sets how the level of entries is rendered. Levels are
+// rendered in lowercase (LevelLowercase) by default.
+func WithLevelFormat(format LevelFormat) Option {
+	return func(o *loggerOptions) {
+		o.levelFormat = format
+	}
+}
+
+// WithDefaults adds the given fields to every entry. Defaults have the lowest
+// precedence: context and per-call fields with the same key override them.
+// Calling it more than once merges the defaults, later values winning.
+func WithDefaults(defaults map[string]interface{}) Option {
+	return func(o *loggerOptions) {
+		if o.defaults == nil {
+			o.defaults = make(map[string]interface{}, len(defaults))
+		}
+		for k, v := range defaults {
+			o.defaults[k] = v
+		}
+	}
+}
+
+// FieldConstructor builds the zap field of a key from its value, such as zap.Any
+// or a function converting the value into a typed field.
+type FieldConstructor func(key string, value interface{}) zap.Field
+
+// WithContextID adds the ID of the MutableFields in the context (see
+// goctx.MutableFields.ID) to every entry as "context_id". The ID is assigned
+// the first time an entry is logged with the fields and reused afterwards, so
+// all the logs of a request are correlated even without a request ID.
+func WithContextID() Option {
+	return func(o *loggerOptions) {
+		o.contextID = true
+	}
+}
+
+// WithFieldType registers the constructor used to build the field of the given
+// key, taking precedence over the built-in conversion by type. It keeps the
+// type of values logged through maps, e.g. a "latency" int of milliseconds
+// logged as a zap.Duration, or supports types not converted by default.
+// Calling it again for the same key replaces the constructor.
+func WithFieldType(key string, constructor FieldConstructor) Option {
+	return func(o *loggerOptions) {
+		if o.fieldTypes == nil {
+			o.fieldTypes = make(map[string]FieldConstructor)
+		}
+		o.fieldTypes[key] = constructor
+	}
+}
+
+// WithHostInfo adds the hostname and process ID of the service to every entry
+// as "hostname" and "pid", to tell apart instances writing to the same index.
+// Both are resolved once, when the logger is built; the hostname is left out
+// when it cannot be looked up.
+func WithHostInfo() Option {
+	hostInfo := map[string]interface{}{FieldPID: os.Getpid()}
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		hostInfo[FieldHostname] = hostname
+	}
+	return WithDefaults(hostInfo)
+}
+
+// WithErrorTriggeredBuffer enables error-triggered logging: info entries logged
+// with a context prepared by Logger.WithEntryBuffer are held back, up to size
+// per context, and only written when an error is logged with that context.
+// Buffering is disabled by default.
+func WithErrorTriggeredBuffer(size int) Option {
+	return func(o *loggerOptions) {
+		o.bufferSize = size
+	}
+}
+
+// WithClaimsEmail adds the email of the authenticated claims in the context to
+// every entry, next to the user ID. It is off by default as the email is
+// personal data that is usually redacted from logs.
+func WithClaimsEmail() Option {
+	return func(o *loggerOptions) {
+		o.logEmail = true
+	}
+}
+
+// defaultFields returns the default fields in the form expected by convertToZapFields.
+func (o *loggerOptions) defaultFields() []map[string]interface{} {
+	if len(o.defaults) == 0 {
+		return nil
+	}
+	return []map[string]interface{}{o.defaults}
+}
+
+// buildZapOptions translates the collected options into zap options for the given config.
+func (o *loggerOptions) buildZapOptions(config zap.Config) ([]zap.Option, error) {
+	var zapOptions []zap.Option
+
+	switch {
+	case o.socketDial != nil:
+		zapOptions = append(zapOptions, zap.WrapCore(func(zapcore.Core) zapcore.Core {
+			return o.newSocketCore(config)
+		}))
+	case o.infoOutput != nil && o.errorOutput != nil:
+		infoOutput, errorOutput := o.infoOutput, o.errorOutput
+		if o.asyncBufferSize > 0 {
+			infoOutput, errorOutput = o.newAsyncWriter(infoOutput), o.newAsyncWriter(errorOutput)
+		}
+		zapOptions = append(zapOptions, zap.WrapCore(func(zapcore.Core) zapcore.Core {
+			return newLevelRoutingCore(config, o.newEncoder(config), infoOutput, errorOutput)
+		}))
+	case o.asyncBufferSize > 0 || (o.console && o.consoleFieldCap > 0):
+		// The config's outputs are opened here so writes to them can be
+		// queued or encoded with a custom encoder
+		output, closeOutput, err := zap.Open(config.OutputPaths...)
+		if err != nil {
+			return nil, err
+		}
+		o.closeOutput = closeOutput
+		if o.asyncBufferSize > 0 {
+			output = o.newAsyncWriter(output)
+		}
+		zapOptions = append(zapOptions, zap.WrapCore(func(zapcore.Core) zapcore.Core {
+			core := zapcore.NewCore(o.newEncoder(config), output, config.Level)
+			return newSampler(config, core)
+		}))
+	}
+
+	return zapOptions, nil
+}
+
+// newAsyncWriter wraps out in an asyncWriter closed along with the logger.
+func (o *loggerOptions) newAsyncWriter(out zapcore.WriteSyncer) zapcore.WriteSyncer {
+	w := newAsyncWriter(out, o)
+	o.asyncWriters = append(o.asyncWriters, w)
+	return w
+}
+
+// newLevelRoutingCore tees two level-filtered cores so entries below warn go to
+// infoOutput and the rest to errorOutput, keeping the config's sampling policy.
+func newLevelRoutingCore(config zap.Config, encoder zapcore.Encoder, infoOutput, errorOutput zapcore.WriteSyncer) zapcore.Core {
+	lowPriority := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
+		return lvl < zapcore.WarnLevel && config.Level.Enabled(lvl)
+	})
+	highPriority := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
+		return lvl >= zapcore.WarnLevel && config.Level.Enabled(lvl)
+	})
+
+	core := zapcore.NewTee(
+		zapcore.NewCore(encoder, infoOutput, lowPriority),
+		zapcore.NewCore(encoder.Clone(), errorOutput, highPriority),
+	)
+
+	return newSampler(config, core)
+}
+
+// newSampler wraps the core with the config's sampling policy, if any.
+func newSampler(config zap.Config, core zapcore.Core) zapcore.Core {
+	if config.Sampling == nil {
+		return core
+	}
+	return zapcore.NewSamplerWithOptions(
+		core,
+		samplingTick,
+		config.Sampling.Initial,
+		config.Sampling.Thereafter,
+	)
+}
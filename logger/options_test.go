@@ -0,0 +1,247 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+	"github.com/junkd0g/go-microservice-commons/logger"
+)
+
+func TestLevelRouting(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	log, err := logger.NewLogger(logger.WithLevelRouting(zapcore.AddSync(&stdout), zapcore.AddSync(&stderr)))
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+
+	log.Info(context.Background(), "Info Message")
+	log.Error(context.Background(), "Error Message")
+
+	if !bytes.Contains(stdout.Bytes(), []byte("Info Message")) {
+		t.Errorf("Expected info entry in stdout, got: %s", stdout.String())
+	}
+	if bytes.Contains(stdout.Bytes(), []byte("Error Message")) {
+		t.Errorf("Unexpected error entry in stdout: %s", stdout.String())
+	}
+
+	if !bytes.Contains(stderr.Bytes(), []byte("Error Message")) {
+		t.Errorf("Expected error entry in stderr, got: %s", stderr.String())
+	}
+	if bytes.Contains(stderr.Bytes(), []byte("Info Message")) {
+		t.Errorf("Unexpected info entry in stderr: %s", stderr.String())
+	}
+}
+
+func TestDurationFormat(t *testing.T) {
+	testCases := []struct {
+		name     string
+		opts     []logger.Option
+		expected string
+	}{
+		{
+			name:     "default renders durations as strings",
+			expected: `"latency":"1.5s"`,
+		},
+		{
+			name:     "milliseconds renders durations as floats under a _ms key",
+			opts:     []logger.Option{logger.WithDurationFormat(logger.DurationMilliseconds)},
+			expected: `"latency_ms":1500`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+
+			opts := append(tc.opts, logger.WithLevelRouting(zapcore.AddSync(&out), zapcore.AddSync(&out)))
+			log, err := logger.NewLogger(opts...)
+			if err != nil {
+				t.Fatalf("Error creating logger: %v", err)
+			}
+
+			log.Info(context.Background(), "Info Message", map[string]interface{}{"latency": 1500 * time.Millisecond})
+
+			if !bytes.Contains(out.Bytes(), []byte(tc.expected)) {
+				t.Errorf("Expected %s in output, got: %s", tc.expected, out.String())
+			}
+		})
+	}
+}
+
+func TestLevelFormat(t *testing.T) {
+	testCases := []struct {
+		name     string
+		opts     []logger.Option
+		expected string
+	}{
+		{
+			name:     "default renders levels in lowercase",
+			expected: `"level":"info"`,
+		},
+		{
+			name:     "capital renders levels in uppercase",
+			opts:     []logger.Option{logger.WithLevelFormat(logger.LevelCapital)},
+			expected: `"level":"INFO"`,
+		},
+		{
+			name:     "capital color renders levels in uppercase with colors",
+			opts:     []logger.Option{logger.WithLevelFormat(logger.LevelCapitalColor)},
+			expected: `\u001b[34mINFO\u001b[0m`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+
+			opts := append(tc.opts, logger.WithLevelRouting(zapcore.AddSync(&out), zapcore.AddSync(&out)))
+			log, err := logger.NewLogger(opts...)
+			if err != nil {
+				t.Fatalf("Error creating logger: %v", err)
+			}
+
+			log.Info(context.Background(), "Info Message")
+
+			if !bytes.Contains(out.Bytes(), []byte(tc.expected)) {
+				t.Errorf("Expected %s in output, got: %s", tc.expected, out.String())
+			}
+		})
+	}
+}
+
+func TestDefaults(t *testing.T) {
+	log, err := logger.NewLoggerWithDefaults(map[string]interface{}{"service": "my-service", "env": "test"})
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+
+	core, recorded := observer.New(zapcore.InfoLevel)
+	log.SetCore(core)
+
+	log.Info(context.Background(), "No Fields")
+	log.Info(context.Background(), "Override", map[string]interface{}{"env": "prod"})
+
+	entries := recorded.All()
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 log entries, got %d", len(entries))
+	}
+
+	fields := entries[0].ContextMap()
+	if fields["service"] != "my-service" || fields["env"] != "test" {
+		t.Errorf("Expected default fields, got %v", fields)
+	}
+
+	fields = entries[1].ContextMap()
+	if fields["service"] != "my-service" || fields["env"] != "prod" {
+		t.Errorf("Expected per-call field to override default, got %v", fields)
+	}
+}
+
+func TestHostInfo(t *testing.T) {
+	log, err := logger.NewLogger(logger.WithHostInfo())
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+
+	core, recorded := observer.New(zapcore.InfoLevel)
+	log.SetCore(core)
+
+	log.Info(context.Background(), "Info Message")
+
+	entries := recorded.All()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 log entry, got %d", len(entries))
+	}
+
+	fields := entries[0].ContextMap()
+	if hostname, _ := os.Hostname(); fields[logger.FieldHostname] != hostname {
+		t.Errorf("Expected hostname %q, got %v", hostname, fields[logger.FieldHostname])
+	}
+	if fields[logger.FieldPID] != int64(os.Getpid()) {
+		t.Errorf("Expected pid %d, got %v", os.Getpid(), fields[logger.FieldPID])
+	}
+}
+
+func TestFieldType(t *testing.T) {
+	log, err := logger.NewLogger(
+		logger.WithFieldType("latency", func(key string, value interface{}) zap.Field {
+			return zap.Duration(key, time.Duration(value.(int))*time.Millisecond)
+		}),
+		logger.WithFieldType("ratio", zap.Any),
+	)
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+
+	core, recorded := observer.New(zapcore.InfoLevel)
+	log.SetCore(core)
+
+	log.Info(context.Background(), "Info Message", map[string]interface{}{
+		"latency": 1500,
+		"ratio":   0.5,
+		"count":   3,
+	})
+
+	entries := recorded.All()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 log entry, got %d", len(entries))
+	}
+
+	fields := entries[0].ContextMap()
+	if fields["latency"] != 1500*time.Millisecond {
+		t.Errorf("Expected latency as a duration, got %v", fields["latency"])
+	}
+	if fields["ratio"] != 0.5 {
+		t.Errorf("Expected ratio as a float, got %v", fields["ratio"])
+	}
+	if fields["count"] != int64(3) {
+		t.Errorf("Expected unregistered count to keep the default conversion, got %v", fields["count"])
+	}
+}
+
+func TestContextID(t *testing.T) {
+	log, err := logger.NewLogger(logger.WithContextID())
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+
+	core, recorded := observer.New(zapcore.InfoLevel)
+	log.SetCore(core)
+
+	ctx := goctx.WithMutableFields(context.Background(), goctx.NewMutableFields())
+	derived, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	log.Info(ctx, "First Message")
+	log.Info(derived, "Second Message")
+	log.Info(goctx.WithMutableFields(context.Background(), goctx.NewMutableFields()), "Other Request")
+	log.Info(context.Background(), "No Fields")
+
+	entries := recorded.All()
+	if len(entries) != 4 {
+		t.Fatalf("Expected 4 log entries, got %d", len(entries))
+	}
+
+	id := entries[0].ContextMap()[logger.FieldContextID]
+	if id == nil || id == "" {
+		t.Fatalf("Expected a context ID, got %v", entries[0].ContextMap())
+	}
+	if got := entries[1].ContextMap()[logger.FieldContextID]; got != id {
+		t.Errorf("Expected the same context ID %v across logs, got %v", id, got)
+	}
+	if got := entries[2].ContextMap()[logger.FieldContextID]; got == id {
+		t.Errorf("Expected another context to get its own ID, got %v", got)
+	}
+	if _, ok := entries[3].ContextMap()[logger.FieldContextID]; ok {
+		t.Errorf("Unexpected context ID without fields: %v", entries[3].ContextMap())
+	}
+}
@@ -0,0 +1,40 @@
+package logger
+
+import (
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// maxPooledFields caps the capacity of buffers returned to the pool so an
+// occasional entry with many fields does not pin a large slice forever.
+const maxPooledFields = 64
+
+// fieldPool recycles the []zap.Field buffers built for each log call.
+var fieldPool = sync.Pool{
+	New: func() interface{} {
+		buf := make([]zap.Field, 0, 16)
+		return &buf
+	},
+}
+
+// getFieldBuffer returns an empty field buffer from the pool.
+func getFieldBuffer() *[]zap.Field {
+	buf := fieldPool.Get().(*[]zap.Field)
+	*buf = (*buf)[:0]
+	return buf
+}
+
+// putFieldBuffer clears the buffer and returns it to the pool. It must only be
+// called once the entry has been written: zap cores encode or copy fields
+// during Write and never retain the slice afterwards.
+func putFieldBuffer(buf *[]zap.Field) {
+	if cap(*buf) > maxPooledFields {
+		return
+	}
+
+	// Drop references to field values so they can be garbage collected
+	clear(*buf)
+	*buf = (*buf)[:0]
+	fieldPool.Put(buf)
+}
@@ -0,0 +1,105 @@
+package logger
+
+import (
+	"context"
+	"hash/fnv"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+)
+
+// samplingBuckets is the number of counters per level; keys hashing to the
+// same bucket share a counter, bounding memory whatever the number of keys.
+const samplingBuckets = 4096
+
+// SamplingKeyFunc derives the sampling key of an entry from its context, such
+// as the tenant ID. Entries with an empty key are sampled together.
+type SamplingKeyFunc func(ctx context.Context) string
+
+// SampleByRequestID is a SamplingKeyFunc sampling per request, keyed by the
+// request ID in the context (see goctx.WithRequestID). A request repeating the
+// same message is capped without suppressing that message in other requests,
+// so low-volume requests stay fully logged. Entries without a request ID are
+// sampled together.
+func SampleByRequestID(ctx context.Context) string {
+	requestID, _ := goctx.RequestIDFromContext(ctx)
+	return requestID
+}
+
+// WithContextSampling replaces the global sampling, keyed by level and message,
+// with sampling also keyed by a value read from the context at log time, so a
+// noisy tenant doesn't starve the logs of the others. Within each tick, the
+// first entries with the same key, level and message are logged, then only
+// every thereafter-th one; a thereafter of zero drops them all. A nil key is
+// ignored, keeping the global sampling.
+func WithContextSampling(key SamplingKeyFunc, tick time.Duration, first, thereafter int) Option {
+	return func(o *loggerOptions) {
+		if key == nil {
+			return
+		}
+		o.sampler = &contextSampler{
+			key:        key,
+			tick:       tick,
+			first:      uint64(max(first, 0)),
+			thereafter: uint64(max(thereafter, 0)),
+		}
+	}
+}
+
+// contextSampler counts entries per context key, level and message.
+type contextSampler struct {
+	key        SamplingKeyFunc
+	tick       time.Duration
+	first      uint64
+	thereafter uint64
+	counters   [zapcore.FatalLevel - zapcore.DebugLevel + 1][samplingBuckets]samplingCounter
+}
+
+// samplingCounter counts the entries of a bucket within the current tick.
+type samplingCounter struct {
+	resetAt atomic.Int64
+	count   atomic.Uint64
+}
+
+// sample reports whether the entry should be logged.
+func (s *contextSampler) sample(ctx context.Context, level zapcore.Level, msg string) bool {
+	if level < zapcore.DebugLevel || level > zapcore.FatalLevel {
+		return true
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(s.key(ctx)))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(msg))
+
+	counter := &s.counters[level-zapcore.DebugLevel][h.Sum32()%samplingBuckets]
+	n := counter.incCheckReset(time.Now(), s.tick)
+
+	if n <= s.first {
+		return true
+	}
+	return s.thereafter > 0 && (n-s.first)%s.thereafter == 0
+}
+
+// incCheckReset increments the counter, resetting it first when the tick has
+// elapsed, and returns the new count.
+func (c *samplingCounter) incCheckReset(now time.Time, tick time.Duration) uint64 {
+	tn := now.UnixNano()
+	resetAfter := c.resetAt.Load()
+	if resetAfter > tn {
+		return c.count.Add(1)
+	}
+
+	c.count.Store(1)
+
+	newResetAfter := tn + tick.Nanoseconds()
+	if !c.resetAt.CompareAndSwap(resetAfter, newResetAfter) {
+		// Another goroutine reset the counter concurrently, count this entry on top of it
+		return c.count.Add(1)
+	}
+
+	return 1
+}
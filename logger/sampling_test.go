@@ -0,0 +1,91 @@
+package logger_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+	"github.com/junkd0g/go-microservice-commons/logger"
+)
+
+// tenantKey is a SamplingKeyFunc sampling per tenant.
+func tenantKey(ctx context.Context) string {
+	tenant, _ := goctx.TenantFromContext(ctx)
+	return tenant
+}
+
+func TestContextSampling(t *testing.T) {
+	log, err := logger.NewLogger(logger.WithContextSampling(tenantKey, time.Minute, 2, 3))
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+	core, recorded := observer.New(zapcore.InfoLevel)
+	log.SetCore(core)
+
+	noisy := goctx.WithTenant(context.Background(), "noisy")
+	quiet := goctx.WithTenant(context.Background(), "quiet")
+
+	// The noisy tenant logs the first 2 entries, then every 3rd: entries 1, 2, 5 and 8
+	for i := 0; i < 10; i++ {
+		log.Info(noisy, "cache miss", map[string]interface{}{"tenant": "noisy"})
+	}
+	log.Info(quiet, "cache miss", map[string]interface{}{"tenant": "quiet"})
+	log.Info(quiet, "cache miss", map[string]interface{}{"tenant": "quiet"})
+	log.Error(noisy, "cache miss", map[string]interface{}{"tenant": "noisy"})
+
+	info := recorded.FilterLevelExact(zapcore.InfoLevel)
+	if got := info.FilterField(zapcore.Field{Key: "tenant", Type: zapcore.StringType, String: "noisy"}).Len(); got != 4 {
+		t.Errorf("Expected 4 sampled entries for the noisy tenant, got %d", got)
+	}
+	if got := info.FilterField(zapcore.Field{Key: "tenant", Type: zapcore.StringType, String: "quiet"}).Len(); got != 2 {
+		t.Errorf("Expected the quiet tenant to be sampled independently, got %d entries", got)
+	}
+	if got := recorded.FilterLevelExact(zapcore.ErrorLevel).Len(); got != 1 {
+		t.Errorf("Expected the error level to be sampled independently, got %d entries", got)
+	}
+}
+
+func TestContextSamplingNilKey(t *testing.T) {
+	log, err := logger.NewLogger(logger.WithContextSampling(nil, time.Minute, 1, 0))
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+	core, recorded := observer.New(zapcore.InfoLevel)
+	log.SetCore(core)
+
+	// The option is ignored, so logging neither panics nor samples
+	for i := 0; i < 3; i++ {
+		log.Info(context.Background(), "cache miss")
+	}
+	if got := recorded.Len(); got != 3 {
+		t.Errorf("Expected 3 entries without context sampling, got %d", got)
+	}
+}
+
+func TestSampleByRequestID(t *testing.T) {
+	log, err := logger.NewLogger(logger.WithContextSampling(logger.SampleByRequestID, time.Minute, 1, 0))
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+	core, recorded := observer.New(zapcore.InfoLevel)
+	log.SetCore(core)
+
+	chatty := goctx.WithRequestID(context.Background(), "req-1")
+	other := goctx.WithRequestID(context.Background(), "req-2")
+
+	for i := 0; i < 5; i++ {
+		log.Info(chatty, "retrying", map[string]interface{}{"request": "req-1"})
+	}
+	log.Info(other, "retrying", map[string]interface{}{"request": "req-2"})
+
+	if got := recorded.FilterField(zapcore.Field{Key: "request", Type: zapcore.StringType, String: "req-1"}).Len(); got != 1 {
+		t.Errorf("Expected the chatty request to be capped to 1 entry, got %d", got)
+	}
+	if got := recorded.FilterField(zapcore.Field{Key: "request", Type: zapcore.StringType, String: "req-2"}).Len(); got != 1 {
+		t.Errorf("Expected the other request to be logged, got %d entries", got)
+	}
+}
@@ -0,0 +1,268 @@
+package logger
+
+import (
+	"bytes"
+	"net"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// socketDialTimeout bounds each connection attempt of WithSocketOutput.
+	socketDialTimeout = time.Second
+
+	// socketWriteTimeout bounds each write to the socket so a stuck reader
+	// triggers a reconnection instead of stalling the writer forever.
+	socketWriteTimeout = 5 * time.Second
+
+	// socketMinBackoff and socketMaxBackoff bound the wait between reconnection attempts.
+	socketMinBackoff = 100 * time.Millisecond
+	socketMaxBackoff = 5 * time.Second
+)
+
+// SocketOverflowPolicy controls what a socket output does with entries logged
+// while its buffer is full, e.g. because the reader is slow or unreachable.
+type SocketOverflowPolicy int
+
+const (
+	// SocketDropOldest discards the oldest buffered entry to make room for the
+	// new one so logging never blocks the caller. This is the default.
+	SocketDropOldest SocketOverflowPolicy = iota
+	// SocketBlock makes the caller wait for room in the buffer, trading
+	// latency spikes for not losing entries.
+	SocketBlock
+)
+
+// WithSocketOutput writes entries as JSON lines to the socket at address, e.g.
+// WithSocketOutput("unix", "/var/run/sidecar.sock", 1024) for a sidecar
+// reading newline-delimited JSON. Entries are buffered, up to bufferSize of
+// them, and written by a background goroutine, so a slow reader never blocks
+// the application unless SocketBlock is set with WithSocketOverflow. The
+// connection is dialed when the first entry is buffered and re-established
+// with backoff whenever a write fails. The socket replaces the other outputs; call Close before
+// exiting to flush the buffer.
+func WithSocketOutput(network, address string, bufferSize int) Option {
+	return WithSocketDialer(func() (net.Conn, error) {
+		return net.DialTimeout(network, address, socketDialTimeout)
+	}, bufferSize)
+}
+
+// WithSocketDialer behaves like WithSocketOutput, obtaining connections from
+// dial, e.g. to wrap them with TLS. Dial is called again after every failure.
+func WithSocketDialer(dial func() (net.Conn, error), bufferSize int) Option {
+	return func(o *loggerOptions) {
+		o.socketDial = dial
+		o.socketBufferSize = max(bufferSize, 1)
+	}
+}
+
+// WithSocketOverflow sets what a socket output does while its buffer is full.
+func WithSocketOverflow(policy SocketOverflowPolicy) Option {
+	return func(o *loggerOptions) {
+		o.socketPolicy = policy
+	}
+}
+
+// newSocketCore returns the core encoding entries as JSON into a socketWriter
+// closed along with the logger, keeping the config's sampling policy.
+func (o *loggerOptions) newSocketCore(config zap.Config) zapcore.Core {
+	o.socketWriter = newSocketWriter(o.socketDial, o.socketBufferSize, o.socketPolicy)
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(config.EncoderConfig), o.socketWriter, config.Level)
+	return newSampler(config, core)
+}
+
+// socketWriter is a WriteSyncer buffering entries for a background goroutine
+// writing them to a socket.
+type socketWriter struct {
+	dial    func() (net.Conn, error)
+	size    int
+	policy  SocketOverflowPolicy
+	closing chan struct{}
+	done    chan struct{}
+
+	// mu guards the fields below; cond signals changes to them
+	mu      sync.Mutex
+	cond    *sync.Cond
+	queue   [][]byte
+	closed  bool
+	dropped uint64
+}
+
+// newSocketWriter starts the background goroutine writing to the connections from dial.
+func newSocketWriter(dial func() (net.Conn, error), size int, policy SocketOverflowPolicy) *socketWriter {
+	w := &socketWriter{
+		dial:    dial,
+		size:    size,
+		policy:  policy,
+		closing: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	w.cond = sync.NewCond(&w.mu)
+	go w.run()
+	return w
+}
+
+// Write buffers a copy of p, as zap reuses the buffer once Write returns.
+// Entries written after Close are dropped.
+func (w *socketWriter) Write(p []byte) (int, error) {
+	entry := append([]byte(nil), p...)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for !w.closed && len(w.queue) >= w.size {
+		if w.policy == SocketBlock {
+			w.cond.Wait()
+			continue
+		}
+		w.queue[0] = nil
+		w.queue = w.queue[1:]
+		w.dropped++
+	}
+	if w.closed {
+		w.dropped++
+		return len(p), nil
+	}
+
+	w.queue = append(w.queue, entry)
+	w.cond.Broadcast()
+	return len(p), nil
+}
+
+// Sync is a no-op: waiting for an unreachable socket would block the caller.
+// Close flushes the buffer.
+func (w *socketWriter) Sync() error {
+	return nil
+}
+
+// Close stops accepting entries and waits for the buffered ones to be written.
+// If the socket cannot be reached, the buffered entries are dropped.
+func (w *socketWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	w.cond.Broadcast()
+	w.mu.Unlock()
+
+	close(w.closing)
+	<-w.done
+	return nil
+}
+
+// droppedCount returns the number of entries dropped so far.
+func (w *socketWriter) droppedCount() uint64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.dropped
+}
+
+// waitQueued waits for buffered entries, without taking them, and reports
+// false once the writer is closed and drained.
+func (w *socketWriter) waitQueued() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for len(w.queue) == 0 && !w.closed {
+		w.cond.Wait()
+	}
+	return len(w.queue) > 0
+}
+
+// next waits for buffered entries and returns them as a single batch, or
+// false once the writer is closed and drained.
+func (w *socketWriter) next() ([]byte, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for len(w.queue) == 0 && !w.closed {
+		w.cond.Wait()
+	}
+	if len(w.queue) == 0 {
+		return nil, false
+	}
+
+	batch := bytes.Join(w.queue, nil)
+	w.queue = nil
+	// Writers blocked by SocketBlock can proceed
+	w.cond.Broadcast()
+	return batch, true
+}
+
+// run writes the buffered entries until the writer is closed, reconnecting
+// after failures.
+func (w *socketWriter) run() {
+	defer close(w.done)
+
+	var (
+		conn    net.Conn
+		backoff = socketMinBackoff
+	)
+	defer func() {
+		if conn != nil {
+			conn.Close()
+		}
+	}()
+
+	var pending []byte
+	for {
+		if conn == nil {
+			// Only dial once there is something to write, leaving the entries
+			// buffered meanwhile so the overflow policy still applies to them
+			if len(pending) == 0 && !w.waitQueued() {
+				return
+			}
+
+			var err error
+			if conn, err = w.dial(); err != nil {
+				conn = nil
+				if !w.wait(backoff) {
+					return
+				}
+				backoff = min(backoff*2, socketMaxBackoff)
+				continue
+			}
+			backoff = socketMinBackoff
+		}
+
+		if len(pending) == 0 {
+			batch, ok := w.next()
+			if !ok {
+				return
+			}
+			pending = batch
+		}
+
+		_ = conn.SetWriteDeadline(time.Now().Add(socketWriteTimeout))
+		n, err := conn.Write(pending)
+		if err == nil {
+			pending = nil
+			continue
+		}
+
+		// Resend the lines not fully written on a new connection
+		if i := bytes.LastIndexByte(pending[:n], '\n'); i >= 0 {
+			pending = pending[i+1:]
+		}
+		conn.Close()
+		conn = nil
+	}
+}
+
+// wait waits for d and reports whether the writer is still open.
+func (w *socketWriter) wait(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-w.closing:
+		return false
+	}
+}
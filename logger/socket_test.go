@@ -0,0 +1,232 @@
+package logger_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/junkd0g/go-microservice-commons/logger"
+)
+
+// socketServer is a test server collecting the messages of the JSON lines
+// written to a Unix socket.
+type socketServer struct {
+	listener net.Listener
+	messages chan string
+	conns    chan net.Conn
+}
+
+// newSocketServer starts a socketServer listening on a Unix socket in a temporary directory.
+func newSocketServer(t *testing.T) *socketServer {
+	t.Helper()
+
+	listener, err := net.Listen("unix", filepath.Join(t.TempDir(), "sidecar.sock"))
+	if err != nil {
+		t.Fatalf("Error listening: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	s := &socketServer{
+		listener: listener,
+		messages: make(chan string, 100),
+		conns:    make(chan net.Conn, 10),
+	}
+	go s.serve(t)
+	return s
+}
+
+// serve accepts connections and reads their lines until the listener is closed.
+func (s *socketServer) serve(t *testing.T) {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.conns <- conn
+
+		go func() {
+			scanner := bufio.NewScanner(conn)
+			for scanner.Scan() {
+				var entry map[string]interface{}
+				if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+					t.Errorf("Expected a JSON line, got %q: %v", scanner.Text(), err)
+					continue
+				}
+				s.messages <- entry["msg"].(string)
+			}
+		}()
+	}
+}
+
+// address returns the path of the socket.
+func (s *socketServer) address() string {
+	return s.listener.Addr().String()
+}
+
+// next returns the next message received, failing the test after a timeout.
+func (s *socketServer) next(t *testing.T) string {
+	t.Helper()
+
+	select {
+	case msg := <-s.messages:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for a message")
+		return ""
+	}
+}
+
+// toggleDialer returns a dialer failing until it is enabled.
+func toggleDialer(address string) (func() (net.Conn, error), *atomic.Bool) {
+	enabled := &atomic.Bool{}
+	return func() (net.Conn, error) {
+		if !enabled.Load() {
+			return nil, errors.New("sidecar unavailable")
+		}
+		return net.Dial("unix", address)
+	}, enabled
+}
+
+func TestSocketOutput(t *testing.T) {
+	server := newSocketServer(t)
+
+	log, err := logger.NewLogger(logger.WithSocketOutput("unix", server.address(), 10))
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+	defer log.Close()
+
+	log.Info(context.Background(), "First Message")
+	log.Error(context.Background(), "Second Message")
+
+	for _, expected := range []string{"First Message", "Second Message"} {
+		if got := server.next(t); got != expected {
+			t.Errorf("Expected %q, got %q", expected, got)
+		}
+	}
+}
+
+func TestSocketOutputDialsLazily(t *testing.T) {
+	server := newSocketServer(t)
+
+	var dials atomic.Int32
+	log, err := logger.NewLogger(logger.WithSocketDialer(func() (net.Conn, error) {
+		dials.Add(1)
+		return net.Dial("unix", server.address())
+	}, 10))
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+	defer log.Close()
+
+	time.Sleep(50 * time.Millisecond)
+	if got := dials.Load(); got != 0 {
+		t.Fatalf("Expected no dial before the first entry, got %d", got)
+	}
+
+	log.Info(context.Background(), "First Message")
+	if got := server.next(t); got != "First Message" {
+		t.Errorf("Expected %q, got %q", "First Message", got)
+	}
+	if got := dials.Load(); got != 1 {
+		t.Errorf("Expected 1 dial, got %d", got)
+	}
+}
+
+func TestSocketOutputReconnects(t *testing.T) {
+	server := newSocketServer(t)
+
+	log, err := logger.NewLogger(logger.WithSocketOutput("unix", server.address(), 10))
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+	defer log.Close()
+
+	log.Info(context.Background(), "Before")
+	if got := server.next(t); got != "Before" {
+		t.Fatalf("Expected %q, got %q", "Before", got)
+	}
+
+	// The sidecar restarts, dropping the connection
+	(<-server.conns).Close()
+
+	// Entries written to the dead connection before the failure is detected are lost
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		log.Info(context.Background(), "After")
+		select {
+		case got := <-server.messages:
+			if got != "After" {
+				t.Errorf("Expected %q, got %q", "After", got)
+			}
+			return
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+	t.Fatalf("Expected entries to be delivered on a new connection")
+}
+
+func TestSocketOutputDropOldest(t *testing.T) {
+	server := newSocketServer(t)
+	dial, enabled := toggleDialer(server.address())
+
+	log, err := logger.NewLogger(logger.WithSocketDialer(dial, 2))
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+	defer log.Close()
+
+	for _, msg := range []string{"1", "2", "3", "4"} {
+		log.Info(context.Background(), msg)
+	}
+	enabled.Store(true)
+
+	for _, expected := range []string{"3", "4"} {
+		if got := server.next(t); got != expected {
+			t.Errorf("Expected %q, got %q", expected, got)
+		}
+	}
+	if got := log.Dropped(); got != 2 {
+		t.Errorf("Expected 2 dropped entries, got %d", got)
+	}
+}
+
+func TestSocketOutputBlock(t *testing.T) {
+	server := newSocketServer(t)
+	dial, enabled := toggleDialer(server.address())
+
+	log, err := logger.NewLogger(logger.WithSocketDialer(dial, 1), logger.WithSocketOverflow(logger.SocketBlock))
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+	defer log.Close()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for _, msg := range []string{"1", "2", "3"} {
+			log.Info(context.Background(), msg)
+		}
+	}()
+
+	select {
+	case <-done:
+		t.Fatalf("Expected logging to block while the buffer is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	enabled.Store(true)
+
+	for _, expected := range []string{"1", "2", "3"} {
+		if got := server.next(t); got != expected {
+			t.Errorf("Expected %q, got %q", expected, got)
+		}
+	}
+	<-done
+}
@@ -0,0 +1,79 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Key and value of the field tagging the entries written through Writer and
+// StdLogger.
+const (
+	FieldSource  = "source"
+	SourceStdlib = "stdlib"
+)
+
+// WithStdLevel sets the level of the entries written through Writer and
+// StdLogger. Levels above error are logged at error level. Defaults to info.
+func WithStdLevel(level zapcore.Level) Option {
+	return func(o *loggerOptions) {
+		o.stdLevel = level
+	}
+}
+
+// Writer returns an io.Writer logging what is written to it, for libraries
+// that only accept a writer. Each write is split on newlines, every non-empty
+// line becoming an entry at the level set with WithStdLevel, tagged with a
+// "source": "stdlib" field.
+func (l *Logger) Writer() io.Writer {
+	w := &stdWriter{logger: l}
+	if l != nil {
+		w.level = l.stdLevel
+	}
+	return w
+}
+
+// StdLogger returns a standard library *log.Logger writing through Writer,
+// e.g. to capture the errors of an http.Server through its ErrorLog. The
+// message is logged without the standard library's prefix and timestamp.
+func (l *Logger) StdLogger() *log.Logger {
+	return log.New(l.Writer(), "", 0)
+}
+
+// stdWriter is the io.Writer returned by Logger.Writer.
+type stdWriter struct {
+	logger *Logger
+	level  zapcore.Level
+}
+
+// Write logs every non-empty line of p as an entry.
+func (w *stdWriter) Write(p []byte) (int, error) {
+	fields := map[string]interface{}{FieldSource: SourceStdlib}
+
+	for _, line := range bytes.Split(p, []byte("\n")) {
+		line = bytes.TrimRight(line, "\r")
+		if len(line) == 0 {
+			continue
+		}
+		w.log(string(line), fields)
+	}
+
+	return len(p), nil
+}
+
+// log logs the message with the logging method matching the configured level.
+func (w *stdWriter) log(msg string, fields map[string]interface{}) {
+	ctx := context.Background()
+
+	switch {
+	case w.level >= zapcore.ErrorLevel:
+		w.logger.Error(ctx, msg, fields)
+	case w.level == zapcore.WarnLevel:
+		w.logger.Warn(ctx, msg, fields)
+	default:
+		w.logger.Info(ctx, msg, fields)
+	}
+}
@@ -0,0 +1,69 @@
+package logger_test
+
+import (
+	"fmt"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/junkd0g/go-microservice-commons/logger"
+)
+
+func TestWriter(t *testing.T) {
+	log, err := logger.NewLogger()
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+	core, recorded := observer.New(zapcore.InfoLevel)
+	log.SetCore(core)
+
+	written := "first line\nsecond line\r\n\n"
+	n, err := fmt.Fprint(log.Writer(), written)
+	if err != nil || n != len(written) {
+		t.Fatalf("Expected the whole write to succeed, got %d, %v", n, err)
+	}
+
+	entries := recorded.All()
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 log entries, got %d", len(entries))
+	}
+	if entries[0].Message != "first line" || entries[1].Message != "second line" {
+		t.Errorf("Expected one entry per line, got %q and %q", entries[0].Message, entries[1].Message)
+	}
+	for _, entry := range entries {
+		if entry.Level != zapcore.InfoLevel {
+			t.Errorf("Expected info level by default, got %v", entry.Level)
+		}
+		if entry.ContextMap()[logger.FieldSource] != logger.SourceStdlib {
+			t.Errorf("Expected source field, got %v", entry.ContextMap())
+		}
+	}
+}
+
+func TestStdLogger(t *testing.T) {
+	log, err := logger.NewLogger(logger.WithStdLevel(zapcore.ErrorLevel))
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+	core, recorded := observer.New(zapcore.InfoLevel)
+	log.SetCore(core)
+
+	stdLogger := log.StdLogger()
+	stdLogger.Printf("http: TLS handshake error from %s", "10.0.0.1:1234")
+	stdLogger.Print("multi\nline")
+
+	entries := recorded.All()
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 log entries, got %d", len(entries))
+	}
+	if entries[0].Message != "http: TLS handshake error from 10.0.0.1:1234" {
+		t.Errorf("Expected the message without prefix, got %q", entries[0].Message)
+	}
+	if entries[0].Level != zapcore.ErrorLevel {
+		t.Errorf("Expected the configured level, got %v", entries[0].Level)
+	}
+	if entries[1].Message != "multi" || entries[2].Message != "line" {
+		t.Errorf("Expected one entry per line, got %q and %q", entries[1].Message, entries[2].Message)
+	}
+}
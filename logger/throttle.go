@@ -0,0 +1,149 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// FieldOccurrences is the key of the occurrence count logged in the summaries
+// of throttled errors.
+const FieldOccurrences = "occurrences"
+
+// WithErrorThrottling de-duplicates error entries to prevent log storms, e.g.
+// while a downstream is down. Errors are identified by their message and the
+// values of the given key fields, such as "error_code" or "host". Within each
+// window only the first occurrence of an error is logged; when the window ends,
+// errors that occurred more than once are logged again, with their key fields
+// and the number of occurrences in the window as "occurrences". Unlike
+// sampling, other errors logged in the meantime are never suppressed.
+// Call Close to stop the background flushes and log the pending summaries.
+func WithErrorThrottling(window time.Duration, keyFields ...string) Option {
+	return func(o *loggerOptions) {
+		o.throttleWindow = window
+		o.throttleKeyFields = keyFields
+	}
+}
+
+// throttledError counts the occurrences of an error within the current window.
+type throttledError struct {
+	msg         string
+	keyFields   []zap.Field
+	occurrences int
+}
+
+// errorThrottler tracks the errors logged in the current window and logs the
+// summaries of the repeated ones through summarize when the window ends.
+type errorThrottler struct {
+	keyFields []string
+	summarize func(msg string, fields []zap.Field)
+
+	mu     sync.Mutex
+	errors map[string]*throttledError
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// newErrorThrottler starts the background goroutine ending a window every window.
+func newErrorThrottler(window time.Duration, keyFields []string, summarize func(string, []zap.Field)) *errorThrottler {
+	t := &errorThrottler{
+		keyFields: keyFields,
+		summarize: summarize,
+		errors:    make(map[string]*throttledError),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	go t.run(window)
+	return t
+}
+
+// run ends the window on every tick until the throttler is closed.
+func (t *errorThrottler) run(window time.Duration) {
+	defer close(t.done)
+
+	ticker := time.NewTicker(window)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			t.flush()
+		case <-t.stop:
+			t.flush()
+			return
+		}
+	}
+}
+
+// allow records an occurrence of the error and reports whether it is the first
+// one of the window, which should be logged.
+func (t *errorThrottler) allow(msg string, fields []zap.Field) bool {
+	key, keyFields := t.identify(msg, fields)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if throttled, ok := t.errors[key]; ok {
+		throttled.occurrences++
+		return false
+	}
+
+	t.errors[key] = &throttledError{msg: msg, keyFields: keyFields, occurrences: 1}
+	return true
+}
+
+// identify returns the identity of an error, built from its message and the
+// values of the key fields, along with the key fields themselves.
+func (t *errorThrottler) identify(msg string, fields []zap.Field) (string, []zap.Field) {
+	var (
+		key       strings.Builder
+		keyFields []zap.Field
+	)
+	key.WriteString(msg)
+
+	for _, name := range t.keyFields {
+		key.WriteByte(0)
+		for _, field := range fields {
+			if field.Key != name {
+				continue
+			}
+			enc := zapcore.NewMapObjectEncoder()
+			field.AddTo(enc)
+			fmt.Fprint(&key, enc.Fields[name])
+			keyFields = append(keyFields, field)
+			break
+		}
+	}
+
+	return key.String(), keyFields
+}
+
+// flush ends the window, logging the summaries of the repeated errors.
+func (t *errorThrottler) flush() {
+	t.mu.Lock()
+	errors := t.errors
+	t.errors = make(map[string]*throttledError, len(errors))
+	t.mu.Unlock()
+
+	for _, throttled := range errors {
+		if throttled.occurrences < 2 {
+			continue
+		}
+		fields := append(throttled.keyFields, zap.Int(FieldOccurrences, throttled.occurrences))
+		t.summarize(throttled.msg, fields)
+	}
+}
+
+// Close stops the background goroutine once the pending summaries are logged.
+func (t *errorThrottler) Close() {
+	t.stopOnce.Do(func() {
+		close(t.stop)
+	})
+	<-t.done
+}
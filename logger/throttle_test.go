@@ -0,0 +1,81 @@
+package logger_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/junkd0g/go-microservice-commons/logger"
+)
+
+func TestErrorThrottling(t *testing.T) {
+	log, err := logger.NewLogger(logger.WithErrorThrottling(time.Hour, "host"))
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+	core, recorded := observer.New(zapcore.InfoLevel)
+	log.SetCore(core)
+
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		log.Error(ctx, "downstream unavailable", map[string]interface{}{"host": "db-1", "attempt": i})
+	}
+	log.Error(ctx, "downstream unavailable", map[string]interface{}{"host": "db-2"})
+	log.Error(ctx, "other failure", map[string]interface{}{"host": "db-1"})
+	log.Info(ctx, "downstream unavailable", map[string]interface{}{"host": "db-1"})
+
+	if got := recorded.FilterLevelExact(zapcore.ErrorLevel).Len(); got != 3 {
+		t.Fatalf("Expected the first occurrence of each error to be logged, got %d entries", got)
+	}
+	if got := recorded.FilterLevelExact(zapcore.InfoLevel).Len(); got != 1 {
+		t.Errorf("Expected info entries not to be throttled, got %d entries", got)
+	}
+
+	if err := log.Close(); err != nil {
+		t.Fatalf("Error closing logger: %v", err)
+	}
+
+	summaries := recorded.FilterFieldKey(logger.FieldOccurrences).All()
+	if len(summaries) != 1 {
+		t.Fatalf("Expected a summary of the repeated error on close, got %d", len(summaries))
+	}
+
+	summary := summaries[0]
+	if summary.Message != "downstream unavailable" || summary.Level != zapcore.ErrorLevel {
+		t.Errorf("Expected the summary to repeat the error, got %v %q", summary.Level, summary.Message)
+	}
+	fields := summary.ContextMap()
+	if fields["host"] != "db-1" || fields[logger.FieldOccurrences] != int64(5) {
+		t.Errorf("Expected the key fields and occurrence count, got %v", fields)
+	}
+}
+
+func TestErrorThrottlingWindow(t *testing.T) {
+	log, err := logger.NewLogger(logger.WithErrorThrottling(20 * time.Millisecond))
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+	defer log.Close()
+	core, recorded := observer.New(zapcore.InfoLevel)
+	log.SetCore(core)
+
+	log.Error(context.Background(), "downstream unavailable")
+	log.Error(context.Background(), "downstream unavailable")
+
+	deadline := time.Now().Add(time.Second)
+	for recorded.FilterFieldKey(logger.FieldOccurrences).Len() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected a summary once the window ends")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// A new window logs the error again
+	log.Error(context.Background(), "downstream unavailable")
+	if got := recorded.FilterMessage("downstream unavailable").Len(); got != 3 {
+		t.Errorf("Expected the first entry, the summary and the new window's entry, got %d", got)
+	}
+}
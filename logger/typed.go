@@ -0,0 +1,57 @@
+package logger
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// typedFieldsKey is the context key under which TypedFields are stored.
+type typedFieldsKey struct{}
+
+// TypedFields accumulates pre-built zap fields that the logger appends to every
+// entry logged with a context carrying them. Unlike the map based
+// goctx.MutableFields it keeps the field types and skips the interface{}
+// conversion on the hot path. It is safe for concurrent use.
+type TypedFields struct {
+	sync.RWMutex
+	fields []zap.Field
+}
+
+// NewTypedFields initializes a new instance of TypedFields.
+func NewTypedFields() *TypedFields {
+	return &TypedFields{}
+}
+
+// Add safely appends fields to the TypedFields.
+func (tf *TypedFields) Add(fields ...zap.Field) {
+	tf.Lock()
+	defer tf.Unlock()
+	tf.fields = append(tf.fields, fields...)
+}
+
+// Fields safely returns a copy of the accumulated fields.
+func (tf *TypedFields) Fields() []zap.Field {
+	tf.RLock()
+	defer tf.RUnlock()
+	return append([]zap.Field(nil), tf.fields...)
+}
+
+// WithTypedFields associates TypedFields with a context.
+func WithTypedFields(ctx context.Context, fields *TypedFields) context.Context {
+	return context.WithValue(ctx, typedFieldsKey{}, fields)
+}
+
+// TypedFieldsFromContext retrieves the TypedFields associated with a context.
+func TypedFieldsFromContext(ctx context.Context) (*TypedFields, bool) {
+	fields, ok := ctx.Value(typedFieldsKey{}).(*TypedFields)
+	return fields, ok && fields != nil
+}
+
+// appendTo safely appends the accumulated fields to dst.
+func (tf *TypedFields) appendTo(dst []zap.Field) []zap.Field {
+	tf.RLock()
+	defer tf.RUnlock()
+	return append(dst, tf.fields...)
+}
@@ -0,0 +1,81 @@
+package logger_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+	"github.com/junkd0g/go-microservice-commons/logger"
+)
+
+func TestTypedFields(t *testing.T) {
+	log, err := logger.NewLogger()
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+
+	core, recorded := observer.New(zapcore.InfoLevel)
+	log.SetCore(core)
+
+	typedFields := logger.NewTypedFields()
+	typedFields.Add(zap.String("request_id", "abc-123"), zap.Duration("budget", time.Second))
+	ctx := logger.WithTypedFields(context.Background(), typedFields)
+
+	log.Info(ctx, "Info Message", map[string]interface{}{"key": "value"})
+
+	entries := recorded.All()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 log entry, got %d", len(entries))
+	}
+
+	fields := entries[0].ContextMap()
+	if len(fields) != 3 {
+		t.Fatalf("Expected 3 fields, got %d", len(fields))
+	}
+	if fields["request_id"] != "abc-123" || fields["budget"] != time.Second || fields["key"] != "value" {
+		t.Errorf("Unexpected fields: %v", fields)
+	}
+
+	for _, field := range entries[0].Context {
+		if field.Key == "budget" && field.Type != zapcore.DurationType {
+			t.Errorf("Expected budget to keep its duration type, got %v", field.Type)
+		}
+	}
+}
+
+func BenchmarkContextFields(b *testing.B) {
+	log, err := logger.NewLogger()
+	if err != nil {
+		b.Fatalf("Error creating logger: %v", err)
+	}
+	log.SetCore(zapcore.NewNopCore())
+
+	b.Run("map", func(b *testing.B) {
+		mutableFields := goctx.NewMutableFields()
+		mutableFields.AddField(map[string]interface{}{"request_id": "abc-123", "user_id": 42})
+		ctx := goctx.WithMutableFields(context.Background(), mutableFields)
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			log.Info(ctx, "Info Message")
+		}
+	})
+
+	b.Run("typed", func(b *testing.B) {
+		typedFields := logger.NewTypedFields()
+		typedFields.Add(zap.String("request_id", "abc-123"), zap.Int("user_id", 42))
+		ctx := logger.WithTypedFields(context.Background(), typedFields)
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			log.Info(ctx, "Info Message")
+		}
+	})
+}
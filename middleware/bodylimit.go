@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/junkd0g/go-microservice-commons/apperr"
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+	"github.com/junkd0g/go-microservice-commons/httputil"
+)
+
+// MaxBodyBytes limits request bodies to n bytes. Requests declaring a larger
+// Content-Length are rejected before the handler runs; other bodies are wrapped
+// with http.MaxBytesReader so reads past the limit fail with an
+// *http.MaxBytesError. Either way the client receives 413 Request Entity Too
+// Large with an httputil.ErrorBody, JSON unless the client prefers another
+// content type (see httputil.WriteError), unless the handler already responded, and the
+// rejection is logged as a warning through the context logger, if present.
+func MaxBodyBytes(n int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > n {
+				rejectBody(w, r, n)
+				return
+			}
+
+			sw := newStatusWriter(w)
+			body := &limitedBody{ReadCloser: http.MaxBytesReader(w, r.Body, n)}
+			r.Body = body
+
+			next.ServeHTTP(sw, r)
+
+			if body.exceeded && !sw.wroteHeader {
+				rejectBody(w, r, n)
+			}
+		})
+	}
+}
+
+// limitedBody records whether reading the wrapped body hit the size limit.
+type limitedBody struct {
+	io.ReadCloser
+	exceeded bool
+}
+
+// Read reads from the wrapped body, recording a *http.MaxBytesError.
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		b.exceeded = true
+	}
+	return n, err
+}
+
+// rejectBody logs the oversized request and answers it with a 413.
+func rejectBody(w http.ResponseWriter, r *http.Request, limit int64) {
+	if log, err := goctx.GetLoggerFromContext(r.Context()); err == nil {
+		goctx.Warn(r.Context(), log, "request body too large", map[string]interface{}{
+			"path":           r.URL.Path,
+			"content_length": int(r.ContentLength),
+			"limit":          int(limit),
+		})
+	}
+
+	httputil.WriteError(w, r, apperr.New(http.StatusRequestEntityTooLarge, httputil.CodeBodyTooLarge, httputil.ErrBodyTooLarge.Error()))
+}
@@ -0,0 +1,74 @@
+package middleware_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+	"github.com/junkd0g/go-microservice-commons/middleware"
+)
+
+func Test_MaxBodyBytes(t *testing.T) {
+	t.Run("should let bodies within the limit through", func(t *testing.T) {
+		var got string
+		handler := middleware.MaxBodyBytes(16)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			got = string(body)
+		}))
+
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("small body"))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "small body", got)
+	})
+
+	t.Run("should reject an oversized body before the handler runs", func(t *testing.T) {
+		log, recorded := newObservedLogger(t)
+
+		called := false
+		handler := middleware.MaxBodyBytes(16)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		}))
+
+		req := httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader(strings.Repeat("a", 32)))
+		req = req.WithContext(goctx.AddLoggerToContex(req.Context(), log))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.False(t, called)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+		assert.JSONEq(t, `{"code":"body_too_large","message":"request body too large"}`, rec.Body.String())
+
+		entries := recorded.All()
+		assert.Len(t, entries, 1)
+		assert.Equal(t, zapcore.WarnLevel, entries[0].Level)
+		assert.Equal(t, "request body too large", entries[0].Message)
+		assert.Equal(t, "/upload", entries[0].ContextMap()["path"])
+	})
+
+	t.Run("should reject an oversized body of unknown length once read", func(t *testing.T) {
+		var readErr error
+		handler := middleware.MaxBodyBytes(16)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, readErr = io.ReadAll(r.Body)
+		}))
+
+		req := httptest.NewRequest(http.MethodPost, "/", io.NopCloser(strings.NewReader(strings.Repeat("a", 32))))
+		req.ContentLength = -1
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		var maxBytesErr *http.MaxBytesError
+		assert.ErrorAs(t, readErr, &maxBytesErr)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+		assert.JSONEq(t, `{"code":"body_too_large","message":"request body too large"}`, rec.Body.String())
+	})
+}
@@ -0,0 +1,280 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+)
+
+// Default sizes of the bodies kept by ErrorBodyLogger.
+const (
+	DefaultRequestBodyLimit  = 16 << 10
+	DefaultResponseBodyLimit = 4 << 10
+)
+
+// truncatedBody ends the logged bodies cut at their limit.
+const truncatedBody = "..."
+
+// redactedBody replaces the values redacted by RedactKeys, and the bodies it
+// cannot parse or does not support.
+const redactedBody = "[REDACTED]"
+
+// BodyRedactor returns the body to log in place of body, given the body's
+// Content-Type.
+type BodyRedactor func(contentType string, body []byte) []byte
+
+// RedactKeys returns a BodyRedactor replacing the values of the keys
+// containing any of the markers, matched case-insensitively, with
+// "[REDACTED]": JSON keys at any depth and the fields of
+// application/x-www-form-urlencoded bodies. Bodies that cannot be parsed,
+// e.g. because they were truncated, and bodies of other content types, whose
+// sensitive parts cannot be told apart, are replaced entirely.
+func RedactKeys(markers ...string) BodyRedactor {
+	lowered := make([]string, len(markers))
+	for i, marker := range markers {
+		lowered[i] = strings.ToLower(marker)
+	}
+
+	return func(contentType string, body []byte) []byte {
+		if len(body) == 0 {
+			return body
+		}
+
+		mediaType, _, _ := mime.ParseMediaType(contentType)
+		switch {
+		case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+			return redactJSONBody(body, lowered)
+		case mediaType == "application/x-www-form-urlencoded":
+			return redactFormBody(body, lowered)
+		}
+		return []byte(redactedBody)
+	}
+}
+
+// redactJSONBody redacts the values of the JSON keys containing any of the markers.
+func redactJSONBody(body []byte, markers []string) []byte {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return []byte(redactedBody)
+	}
+
+	redacted, err := json.Marshal(redactJSON(value, markers))
+	if err != nil {
+		return []byte(redactedBody)
+	}
+	return redacted
+}
+
+// redactFormBody redacts the values of the form fields containing any of the markers.
+func redactFormBody(body []byte, markers []string) []byte {
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return []byte(redactedBody)
+	}
+
+	for key, fieldValues := range values {
+		if containsMarker(key, markers) {
+			for i := range fieldValues {
+				fieldValues[i] = redactedBody
+			}
+		}
+	}
+	return []byte(values.Encode())
+}
+
+// redactJSON replaces in place the values of the keys containing any of the markers.
+func redactJSON(value interface{}, markers []string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if containsMarker(key, markers) {
+				v[key] = redactedBody
+				continue
+			}
+			v[key] = redactJSON(field, markers)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactJSON(item, markers)
+		}
+	}
+	return value
+}
+
+// containsMarker reports whether the key contains any of the lowercase markers.
+func containsMarker(key string, markers []string) bool {
+	key = strings.ToLower(key)
+	for _, marker := range markers {
+		if strings.Contains(key, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrorBodyOption configures the ErrorBodyLogger middleware.
+type ErrorBodyOption func(*errorBodyConfig)
+
+// errorBodyConfig holds the settings collected from ErrorBodyOptions.
+type errorBodyConfig struct {
+	requestLimit  int
+	responseLimit int
+	redact        BodyRedactor
+}
+
+// WithRequestBodyLimit sets the number of bytes of the request body kept for logging.
+func WithRequestBodyLimit(n int) ErrorBodyOption {
+	return func(c *errorBodyConfig) {
+		c.requestLimit = n
+	}
+}
+
+// WithResponseBodyLimit sets the number of bytes of the response body kept for logging.
+func WithResponseBodyLimit(n int) ErrorBodyOption {
+	return func(c *errorBodyConfig) {
+		c.responseLimit = n
+	}
+}
+
+// WithBodyRedactor sets the redactor applied to both bodies before they are
+// logged, replacing RedactKeys(goctx.SensitiveFieldMarkers...). A nil redactor
+// logs the bodies as they are.
+func WithBodyRedactor(redactor BodyRedactor) ErrorBodyOption {
+	return func(c *errorBodyConfig) {
+		c.redact = redactor
+	}
+}
+
+// ErrorBodyLogger logs the request body and the response body when the
+// handler responds with a server error (5xx), to help reproduce failures,
+// through the context logger, so it must run after RequestContext or
+// RequestLogger. The bodies are logged as "request_body" and "response_body",
+// after redaction; bodies exceeding their limit are cut and end with "...".
+// Other responses are not logged and their copies are discarded.
+//
+// The bodies are copied as the handler reads and writes them, up to the
+// limits, so streaming handlers keep working; on failure, the part of the
+// request body the handler did not read is read up to the limit. As it costs
+// a copy of every body, apply it only to the routes that need it.
+func ErrorBodyLogger(opts ...ErrorBodyOption) func(http.Handler) http.Handler {
+	config := &errorBodyConfig{
+		requestLimit:  DefaultRequestBodyLimit,
+		responseLimit: DefaultResponseBodyLimit,
+		redact:        RedactKeys(goctx.SensitiveFieldMarkers...),
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body := &capturedBody{ReadCloser: r.Body, limit: config.requestLimit}
+			if r.Body != nil && r.Body != http.NoBody {
+				r.Body = body
+			}
+			rec := &capturingWriter{statusWriter: newStatusWriter(w), limit: config.responseLimit}
+
+			next.ServeHTTP(rec, r)
+
+			if rec.status < http.StatusInternalServerError {
+				return
+			}
+			log, err := goctx.GetLoggerFromContext(r.Context())
+			if err != nil {
+				return
+			}
+
+			body.readRest()
+			log.Error(r.Context(), "request failed", map[string]interface{}{
+				"status":        rec.status,
+				"request_body":  config.logBody(r.Header.Get("Content-Type"), body.captured.Bytes(), body.truncated),
+				"response_body": config.logBody(w.Header().Get("Content-Type"), rec.captured.Bytes(), rec.truncated),
+			})
+		})
+	}
+}
+
+// logBody returns the body to log, redacted if a redactor is set and ending
+// with truncatedBody if it was cut.
+func (c *errorBodyConfig) logBody(contentType string, body []byte, truncated bool) string {
+	if c.redact != nil {
+		body = c.redact(contentType, body)
+	}
+	if truncated {
+		return string(body) + truncatedBody
+	}
+	return string(body)
+}
+
+// capture appends to buf the part of b fitting within limit, reporting
+// whether some of it did not fit.
+func capture(buf *bytes.Buffer, b []byte, limit int) bool {
+	room := max(limit-buf.Len(), 0)
+	if len(b) > room {
+		buf.Write(b[:room])
+		return true
+	}
+	buf.Write(b)
+	return false
+}
+
+// capturedBody is a request body keeping a copy of the bytes read, up to limit.
+type capturedBody struct {
+	io.ReadCloser
+	limit     int
+	captured  bytes.Buffer
+	truncated bool
+	done      bool
+}
+
+// Read reads from the wrapped body, copying the bytes read.
+func (b *capturedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if capture(&b.captured, p[:n], b.limit) {
+		b.truncated = true
+	}
+	if err != nil {
+		b.done = true
+	}
+	return n, err
+}
+
+// readRest captures the part of the body the handler did not read, up to the
+// limit. Read errors, e.g. because the handler closed the body, are ignored.
+func (b *capturedBody) readRest() {
+	if b.done || b.ReadCloser == nil || b.truncated {
+		return
+	}
+
+	// Read one byte more than the room left to detect truncation
+	rest, _ := io.ReadAll(io.LimitReader(b.ReadCloser, int64(max(b.limit-b.captured.Len(), 0))+1))
+	if capture(&b.captured, rest, b.limit) {
+		b.truncated = true
+	}
+}
+
+// capturingWriter forwards the response to the client while keeping a copy of
+// the body, up to limit.
+type capturingWriter struct {
+	*statusWriter
+	limit     int
+	captured  bytes.Buffer
+	truncated bool
+}
+
+// Write copies the bytes written to the client.
+func (w *capturingWriter) Write(b []byte) (int, error) {
+	n, err := w.statusWriter.Write(b)
+	if capture(&w.captured, b[:n], w.limit) {
+		w.truncated = true
+	}
+	return n, err
+}
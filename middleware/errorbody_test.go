@@ -0,0 +1,140 @@
+package middleware_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+	"github.com/junkd0g/go-microservice-commons/middleware"
+)
+
+func Test_ErrorBodyLogger(t *testing.T) {
+	// newHandler returns a handler reading the request body and responding with status.
+	newHandler := func(status int, response string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.ReadAll(r.Body)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(response))
+		})
+	}
+
+	// serve sends a JSON POST request with the given body through the handler.
+	serve := func(t *testing.T, handler http.Handler, body string) *httptest.ResponseRecorder {
+		log, _ := newObservedLogger(t)
+		return serveWithLogger(handler, body, log)
+	}
+
+	t.Run("should log the redacted bodies of server errors", func(t *testing.T) {
+		log, recorded := newObservedLogger(t)
+		handler := middleware.ErrorBodyLogger()(newHandler(http.StatusInternalServerError, `{"error":"boom"}`))
+
+		rec := serveWithLogger(handler, `{"email":"a@b.c","password":"hunter2","nested":{"api_token":"t"}}`, log)
+		assert.Equal(t, `{"error":"boom"}`, rec.Body.String())
+
+		entries := recorded.FilterMessage("request failed").All()
+		assert.Len(t, entries, 1)
+		fields := entries[0].ContextMap()
+		assert.Equal(t, int64(http.StatusInternalServerError), fields["status"])
+		assert.Equal(t, `{"email":"[REDACTED]","nested":{"api_token":"[REDACTED]"},"password":"[REDACTED]"}`, fields["request_body"])
+		assert.Equal(t, `{"error":"boom"}`, fields["response_body"])
+	})
+
+	t.Run("should not log successful requests", func(t *testing.T) {
+		log, recorded := newObservedLogger(t)
+		handler := middleware.ErrorBodyLogger()(newHandler(http.StatusBadRequest, `{"error":"invalid"}`))
+
+		rec := serveWithLogger(handler, `{"name":"x"}`, log)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, 0, recorded.FilterMessage("request failed").Len())
+	})
+
+	t.Run("should cap the bodies", func(t *testing.T) {
+		log, recorded := newObservedLogger(t)
+		handler := middleware.ErrorBodyLogger(
+			middleware.WithRequestBodyLimit(4),
+			middleware.WithResponseBodyLimit(3),
+			middleware.WithBodyRedactor(nil),
+		)(newHandler(http.StatusBadGateway, "upstream down"))
+
+		rec := serveWithLogger(handler, "0123456789", log)
+		assert.Equal(t, "upstream down", rec.Body.String())
+
+		fields := recorded.FilterMessage("request failed").All()[0].ContextMap()
+		assert.Equal(t, "0123...", fields["request_body"])
+		assert.Equal(t, "ups...", fields["response_body"])
+	})
+
+	t.Run("should redact unparseable JSON bodies", func(t *testing.T) {
+		log, recorded := newObservedLogger(t)
+		handler := middleware.ErrorBodyLogger(middleware.WithRequestBodyLimit(10))(newHandler(http.StatusInternalServerError, ""))
+
+		serveWithLogger(handler, `{"password":"hunter2"}`, log)
+
+		fields := recorded.FilterMessage("request failed").All()[0].ContextMap()
+		assert.Equal(t, "[REDACTED]...", fields["request_body"])
+	})
+
+	t.Run("should redact form fields and drop other content types", func(t *testing.T) {
+		log, recorded := newObservedLogger(t)
+		handler := middleware.ErrorBodyLogger()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.ReadAll(r.Body)
+			w.Header().Set("Content-Type", "text/plain")
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte("login failed for hunter2"))
+		}))
+
+		req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader("user=alice&password=hunter2"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req = req.WithContext(goctx.AddLoggerToContex(req.Context(), log))
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		fields := recorded.FilterMessage("request failed").All()[0].ContextMap()
+		assert.Equal(t, "password=%5BREDACTED%5D&user=alice", fields["request_body"])
+		assert.Equal(t, "[REDACTED]", fields["response_body"])
+	})
+
+	t.Run("should read the part of the request body left unread", func(t *testing.T) {
+		log, recorded := newObservedLogger(t)
+		handler := middleware.ErrorBodyLogger(middleware.WithBodyRedactor(nil))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = r.Body.Read(make([]byte, 2))
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+
+		serveWithLogger(handler, "partial read", log)
+
+		fields := recorded.FilterMessage("request failed").All()[0].ContextMap()
+		assert.Equal(t, "partial read", fields["request_body"])
+	})
+
+	t.Run("should keep streaming handlers working", func(t *testing.T) {
+		handler := middleware.ErrorBodyLogger()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, chunk := range []string{"a", "b", "c"} {
+				_, _ = w.Write([]byte(chunk))
+				assert.NoError(t, http.NewResponseController(w).Flush())
+			}
+		}))
+
+		rec := serve(t, handler, "")
+
+		assert.True(t, rec.Flushed)
+		assert.Equal(t, "abc", rec.Body.String())
+	})
+}
+
+// serveWithLogger sends a JSON POST request with the given body and the
+// logger in its context through the handler.
+func serveWithLogger(handler http.Handler, body string, log goctx.Logger) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(goctx.AddLoggerToContex(req.Context(), log))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/junkd0g/go-microservice-commons/httputil"
+)
+
+// CaptureHeaders stores the named headers of inbound requests, defaulting to
+// httputil.DefaultPropagatedHeaders, in the request context so that
+// httputil.PropagateHeaders copies them to outbound requests.
+func CaptureHeaders(names ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := httputil.WithHeaders(r.Context(), r.Header, names...)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
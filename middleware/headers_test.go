@@ -0,0 +1,29 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/junkd0g/go-microservice-commons/httputil"
+	"github.com/junkd0g/go-microservice-commons/middleware"
+)
+
+func Test_CaptureHeaders(t *testing.T) {
+	var outbound *http.Request
+	handler := middleware.CaptureHeaders()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		outbound = httptest.NewRequest(http.MethodGet, "http://downstream/", nil)
+		httputil.PropagateHeaders(r.Context(), outbound)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	req.Header.Set("X-Tenant-ID", "tenant-123")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", outbound.Header.Get("traceparent"))
+	assert.Equal(t, "tenant-123", outbound.Header.Get("X-Tenant-ID"))
+	assert.Empty(t, outbound.Header.Get("X-Request-ID"))
+}
@@ -0,0 +1,260 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/junkd0g/go-microservice-commons/cache"
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+)
+
+// HeaderIdempotencyKey is the header clients set to make retries of a request idempotent.
+const HeaderIdempotencyKey = "Idempotency-Key"
+
+// DefaultIdempotencyTTL is how long recorded responses are replayed unless
+// WithIdempotencyTTL is used.
+const DefaultIdempotencyTTL = 24 * time.Hour
+
+// DefaultIdempotencyMaxBody is the size in bytes above which responses are not
+// recorded unless WithIdempotencyMaxBody is used.
+const DefaultIdempotencyMaxBody = 1 << 20
+
+// IdempotentResponse is a response recorded by the Idempotency middleware.
+type IdempotentResponse struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// IdempotencyStore keeps the responses recorded by the Idempotency middleware.
+// Implementations backed by a shared store such as Redis let every instance
+// of a service replay the same responses.
+type IdempotencyStore interface {
+	// Get returns the response recorded for the key, if any.
+	Get(ctx context.Context, key string) (*IdempotentResponse, bool, error)
+	// Set records the response for the key, to be returned by Get for ttl.
+	Set(ctx context.Context, key string, response *IdempotentResponse, ttl time.Duration) error
+}
+
+// MemoryIdempotencyStore is an in-memory IdempotencyStore holding a bounded
+// number of responses, evicting the least recently used one when full. It is
+// safe for concurrent use and mostly useful for tests and single instance
+// services.
+type MemoryIdempotencyStore struct {
+	responses *cache.LRU[string, *IdempotentResponse]
+}
+
+// NewMemoryIdempotencyStore creates a MemoryIdempotencyStore holding at most size responses.
+func NewMemoryIdempotencyStore(size int) *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{
+		responses: cache.NewLRU[string, *IdempotentResponse](size),
+	}
+}
+
+// Get returns the response recorded for the key, if any and not expired.
+func (s *MemoryIdempotencyStore) Get(ctx context.Context, key string) (*IdempotentResponse, bool, error) {
+	response, ok := s.responses.Get(key)
+	return response, ok, nil
+}
+
+// Set records the response for the key for ttl.
+func (s *MemoryIdempotencyStore) Set(ctx context.Context, key string, response *IdempotentResponse, ttl time.Duration) error {
+	s.responses.SetWithTTL(key, response, ttl)
+	return nil
+}
+
+// IdempotencyOption configures the Idempotency middleware.
+type IdempotencyOption func(*idempotencyConfig)
+
+// idempotencyConfig holds the settings collected from IdempotencyOptions.
+type idempotencyConfig struct {
+	ttl     time.Duration
+	scope   func(r *http.Request) string
+	maxBody int
+}
+
+// WithIdempotencyTTL sets how long recorded responses are replayed.
+func WithIdempotencyTTL(ttl time.Duration) IdempotencyOption {
+	return func(c *idempotencyConfig) {
+		c.ttl = ttl
+	}
+}
+
+// WithIdempotencyScope scopes the keys to the caller returned by scope, e.g.
+// the subject of the authenticated claims or the tenant, so a client reusing
+// the key of another never gets its response. Without it, keys are shared by
+// every caller of a route.
+func WithIdempotencyScope(scope func(r *http.Request) string) IdempotencyOption {
+	return func(c *idempotencyConfig) {
+		c.scope = scope
+	}
+}
+
+// WithIdempotencyMaxBody sets the size in bytes above which responses are not
+// recorded, so large downloads never fill the store.
+func WithIdempotencyMaxBody(n int) IdempotencyOption {
+	return func(c *idempotencyConfig) {
+		c.maxBody = n
+	}
+}
+
+// Idempotency makes retries of requests carrying an Idempotency-Key header
+// idempotent: the first response for a key, scoped to the method, the path and
+// the caller set with WithIdempotencyScope, is recorded in the store and
+// replayed for later requests with the same key instead of running the handler
+// again. Requests with the same key are serialized, so concurrent duplicates
+// wait for the first one and get its response. Server errors (5xx) and bodies
+// larger than DefaultIdempotencyMaxBody, unless set with
+// WithIdempotencyMaxBody, are not recorded, and requests without the header are
+// passed through. Hits and misses are logged through the context logger, if
+// present.
+func Idempotency(store IdempotencyStore, opts ...IdempotencyOption) func(http.Handler) http.Handler {
+	config := &idempotencyConfig{
+		ttl:     DefaultIdempotencyTTL,
+		maxBody: DefaultIdempotencyMaxBody,
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	locks := newKeyLocks()
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			idempotencyKey := r.Header.Get(HeaderIdempotencyKey)
+			if idempotencyKey == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := r.Context()
+			key := r.Method + " " + r.URL.Path + " " + idempotencyKey
+			if config.scope != nil {
+				key = config.scope(r) + " " + key
+			}
+
+			unlock := locks.lock(key)
+			defer unlock()
+
+			response, ok, err := store.Get(ctx, key)
+			if err != nil {
+				logIdempotency(ctx, "failed to get idempotent response", idempotencyKey, err)
+			}
+			if ok {
+				logIdempotency(ctx, "idempotency cache hit", idempotencyKey, nil)
+				replay(w, response)
+				return
+			}
+			logIdempotency(ctx, "idempotency cache miss", idempotencyKey, nil)
+
+			rec := &responseRecorder{statusWriter: newStatusWriter(w), limit: config.maxBody}
+			next.ServeHTTP(rec, r)
+
+			if rec.status >= http.StatusInternalServerError || rec.truncated {
+				return
+			}
+			response = &IdempotentResponse{
+				Status: rec.status,
+				Header: w.Header().Clone(),
+				Body:   rec.body.Bytes(),
+			}
+			if err := store.Set(ctx, key, response, config.ttl); err != nil {
+				logIdempotency(ctx, "failed to record idempotent response", idempotencyKey, err)
+			}
+		})
+	}
+}
+
+// replay writes the recorded response. Headers already set on w, such as the
+// X-Request-ID of the current request, are kept.
+func replay(w http.ResponseWriter, response *IdempotentResponse) {
+	for name, values := range response.Header {
+		if _, ok := w.Header()[name]; !ok {
+			w.Header()[name] = values
+		}
+	}
+	w.WriteHeader(response.Status)
+	_, _ = w.Write(response.Body)
+}
+
+// logIdempotency logs an idempotency event through the context logger, if
+// any, as an error when err is set.
+func logIdempotency(ctx context.Context, msg, idempotencyKey string, err error) {
+	log, loggerErr := goctx.GetLoggerFromContext(ctx)
+	if loggerErr != nil {
+		return
+	}
+
+	fields := map[string]interface{}{"idempotency_key": idempotencyKey}
+	if err != nil {
+		fields["error"] = err.Error()
+		log.Error(ctx, msg, fields)
+		return
+	}
+	log.Info(ctx, msg, fields)
+}
+
+// responseRecorder forwards the response to the client while keeping a copy
+// of the body, up to limit.
+type responseRecorder struct {
+	*statusWriter
+	limit     int
+	body      bytes.Buffer
+	truncated bool
+}
+
+// Write copies the bytes written to the client.
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	n, err := w.statusWriter.Write(b)
+	if capture(&w.body, b[:n], w.limit) {
+		w.truncated = true
+	}
+	return n, err
+}
+
+// keyLocks hands out a mutex per key, dropping it once no request holds or
+// waits for it.
+type keyLocks struct {
+	sync.Mutex
+	locks map[string]*keyLock
+}
+
+// keyLock is the mutex of a key and the number of requests holding or waiting for it.
+type keyLock struct {
+	sync.Mutex
+	refs int
+}
+
+// newKeyLocks initializes a new instance of keyLocks.
+func newKeyLocks() *keyLocks {
+	return &keyLocks{
+		locks: make(map[string]*keyLock),
+	}
+}
+
+// lock locks the mutex of the key and returns the function unlocking it.
+func (l *keyLocks) lock(key string) func() {
+	l.Lock()
+	kl, ok := l.locks[key]
+	if !ok {
+		kl = &keyLock{}
+		l.locks[key] = kl
+	}
+	kl.refs++
+	l.Unlock()
+
+	kl.Lock()
+	return func() {
+		kl.Unlock()
+
+		l.Lock()
+		defer l.Unlock()
+		kl.refs--
+		if kl.refs == 0 {
+			delete(l.locks, key)
+		}
+	}
+}
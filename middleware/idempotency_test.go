@@ -0,0 +1,176 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+	"github.com/junkd0g/go-microservice-commons/middleware"
+)
+
+func Test_Idempotency(t *testing.T) {
+	// newHandler returns a handler creating an order numbered by its number of calls.
+	newHandler := func(calls *int32, delay time.Duration) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			n := atomic.AddInt32(calls, 1)
+			time.Sleep(delay)
+			w.Header().Set("Location", "/orders/"+strconv.Itoa(int(n)))
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte("order " + strconv.Itoa(int(n))))
+		})
+	}
+
+	// post sends a POST /orders request with the given idempotency key.
+	post := func(handler http.Handler, key string, log goctx.Logger) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+		if key != "" {
+			req.Header.Set(middleware.HeaderIdempotencyKey, key)
+		}
+		if log != nil {
+			req = req.WithContext(goctx.AddLoggerToContex(req.Context(), log))
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("should record the first response and replay it", func(t *testing.T) {
+		log, recorded := newObservedLogger(t)
+
+		var calls int32
+		handler := middleware.Idempotency(middleware.NewMemoryIdempotencyStore(100))(newHandler(&calls, 0))
+
+		first := post(handler, "key-1", log)
+		assert.Equal(t, http.StatusCreated, first.Code)
+		assert.Equal(t, "order 1", first.Body.String())
+
+		replayed := post(handler, "key-1", log)
+		assert.Equal(t, http.StatusCreated, replayed.Code)
+		assert.Equal(t, "order 1", replayed.Body.String())
+		assert.Equal(t, "/orders/1", replayed.Header().Get("Location"))
+		assert.Equal(t, int32(1), calls)
+
+		assert.Equal(t, 1, recorded.FilterMessage("idempotency cache miss").Len())
+		assert.Equal(t, 1, recorded.FilterMessage("idempotency cache hit").Len())
+		assert.Equal(t, "key-1", recorded.All()[0].ContextMap()["idempotency_key"])
+	})
+
+	t.Run("should run the handler for other keys and requests without a key", func(t *testing.T) {
+		var calls int32
+		handler := middleware.Idempotency(middleware.NewMemoryIdempotencyStore(100))(newHandler(&calls, 0))
+
+		assert.Equal(t, "order 1", post(handler, "key-1", nil).Body.String())
+		assert.Equal(t, "order 2", post(handler, "key-2", nil).Body.String())
+		assert.Equal(t, "order 3", post(handler, "", nil).Body.String())
+		assert.Equal(t, "order 4", post(handler, "", nil).Body.String())
+	})
+
+	t.Run("should serve concurrent duplicates the first response", func(t *testing.T) {
+		var calls int32
+		handler := middleware.Idempotency(middleware.NewMemoryIdempotencyStore(100))(newHandler(&calls, 20*time.Millisecond))
+
+		var wg sync.WaitGroup
+		bodies := make([]string, 10)
+		for i := range bodies {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				bodies[i] = post(handler, "key-1", nil).Body.String()
+			}(i)
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), calls)
+		for _, body := range bodies {
+			assert.Equal(t, "order 1", body)
+		}
+	})
+
+	t.Run("should not record server errors", func(t *testing.T) {
+		var calls int32
+		handler := middleware.Idempotency(middleware.NewMemoryIdempotencyStore(100))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+
+		post(handler, "key-1", nil)
+		post(handler, "key-1", nil)
+
+		assert.Equal(t, int32(2), calls)
+	})
+
+	t.Run("should expire recorded responses", func(t *testing.T) {
+		var calls int32
+		handler := middleware.Idempotency(
+			middleware.NewMemoryIdempotencyStore(100),
+			middleware.WithIdempotencyTTL(10*time.Millisecond),
+		)(newHandler(&calls, 0))
+
+		post(handler, "key-1", nil)
+		time.Sleep(20 * time.Millisecond)
+
+		assert.Equal(t, "order 2", post(handler, "key-1", nil).Body.String())
+	})
+
+	t.Run("should scope keys to the caller", func(t *testing.T) {
+		var calls int32
+		handler := middleware.Idempotency(
+			middleware.NewMemoryIdempotencyStore(100),
+			middleware.WithIdempotencyScope(func(r *http.Request) string {
+				return r.Header.Get("X-Client")
+			}),
+		)(newHandler(&calls, 0))
+
+		// postAs sends a POST /orders request with the key on behalf of the client.
+		postAs := func(client string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+			req.Header.Set(middleware.HeaderIdempotencyKey, "key-1")
+			req.Header.Set("X-Client", client)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			return rec
+		}
+
+		assert.Equal(t, "order 1", postAs("alice").Body.String())
+		assert.Equal(t, "order 2", postAs("bob").Body.String())
+		assert.Equal(t, "order 1", postAs("alice").Body.String())
+	})
+
+	t.Run("should not record bodies above the limit", func(t *testing.T) {
+		var calls int32
+		handler := middleware.Idempotency(
+			middleware.NewMemoryIdempotencyStore(100),
+			middleware.WithIdempotencyMaxBody(4),
+		)(newHandler(&calls, 0))
+
+		assert.Equal(t, "order 1", post(handler, "key-1", nil).Body.String())
+		assert.Equal(t, "order 2", post(handler, "key-1", nil).Body.String())
+	})
+}
+
+func Test_MemoryIdempotencyStore(t *testing.T) {
+	ctx := context.Background()
+	store := middleware.NewMemoryIdempotencyStore(2)
+
+	for _, key := range []string{"key-1", "key-2", "key-3"} {
+		assert.NoError(t, store.Set(ctx, key, &middleware.IdempotentResponse{Status: http.StatusCreated}, time.Hour))
+	}
+
+	// The least recently used response is evicted
+	_, ok, err := store.Get(ctx, "key-1")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	response, ok, err := store.Get(ctx, "key-3")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusCreated, response.Status)
+}
@@ -0,0 +1,138 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+)
+
+// FieldSeeder returns the logger fields to seed for a request.
+type FieldSeeder func(r *http.Request) map[string]interface{}
+
+// SeedRequestID seeds the request ID stored in the request context as "request_id".
+func SeedRequestID(r *http.Request) map[string]interface{} {
+	requestID, ok := goctx.RequestIDFromContext(r.Context())
+	if !ok {
+		return nil
+	}
+	return map[string]interface{}{"request_id": requestID}
+}
+
+// SeedMethod seeds the request method as "method".
+func SeedMethod(r *http.Request) map[string]interface{} {
+	return map[string]interface{}{"method": r.Method}
+}
+
+// SeedPath seeds the request URL path as "path".
+func SeedPath(r *http.Request) map[string]interface{} {
+	return map[string]interface{}{"path": r.URL.Path}
+}
+
+// SeedRemoteAddr seeds the client network address as "remote_addr".
+func SeedRemoteAddr(r *http.Request) map[string]interface{} {
+	return map[string]interface{}{"remote_addr": r.RemoteAddr}
+}
+
+// SeedUserAgent seeds the User-Agent header as "user_agent".
+func SeedUserAgent(r *http.Request) map[string]interface{} {
+	return map[string]interface{}{"user_agent": r.UserAgent()}
+}
+
+// RequestLoggerOption configures the RequestLogger middleware.
+type RequestLoggerOption func(*requestLoggerConfig)
+
+// requestLoggerConfig holds the settings collected from RequestLoggerOptions.
+type requestLoggerConfig struct {
+	seeders []FieldSeeder
+}
+
+// WithFieldSeeders replaces the default seeders (SeedRequestID, SeedMethod and
+// SeedPath) with the given ones.
+func WithFieldSeeders(seeders ...FieldSeeder) RequestLoggerOption {
+	return func(c *requestLoggerConfig) {
+		c.seeders = seeders
+	}
+}
+
+// RequestContext gives every request its own context so fields never leak
+// between requests. It ensures the request has a request ID, creates a fresh
+// MutableFields seeded by the configured seeders and attaches both the logger,
+// typically a *logger.Logger, and the fields to the context. Downstream handlers
+// retrieve the logger with goctx.GetLoggerFromContext. Use RequestLogger to
+// also log when requests start and finish.
+func RequestContext(log goctx.Logger, opts ...RequestLoggerOption) func(http.Handler) http.Handler {
+	config := newRequestLoggerConfig(opts...)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(requestContext(r, log, config)))
+		})
+	}
+}
+
+// RequestLogger binds a request-scoped logger into the context of every request
+// like RequestContext and logs when the request starts and finishes.
+func RequestLogger(log goctx.Logger, opts ...RequestLoggerOption) func(http.Handler) http.Handler {
+	config := newRequestLoggerConfig(opts...)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			ctx := requestContext(r, log, config)
+
+			log.Info(ctx, "request started")
+
+			sw := newStatusWriter(w)
+			next.ServeHTTP(sw, r.WithContext(ctx))
+
+			fields := map[string]interface{}{
+				"status":   sw.status,
+				"bytes":    sw.written,
+				"duration": time.Since(start),
+			}
+			if sw.status >= http.StatusInternalServerError {
+				log.Error(ctx, "request finished", fields)
+				return
+			}
+			log.Info(ctx, "request finished", fields)
+		})
+	}
+}
+
+// newRequestLoggerConfig collects the given options over the default seeders.
+func newRequestLoggerConfig(opts ...RequestLoggerOption) *requestLoggerConfig {
+	config := &requestLoggerConfig{
+		seeders: []FieldSeeder{SeedRequestID, SeedMethod, SeedPath},
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+	return config
+}
+
+// requestContext returns the request's context carrying a request ID, the
+// logger and a fresh MutableFields seeded by the configured seeders.
+func requestContext(r *http.Request, log goctx.Logger, config *requestLoggerConfig) context.Context {
+	ctx := r.Context()
+	if _, ok := goctx.RequestIDFromContext(ctx); !ok {
+		requestID := r.Header.Get(HeaderRequestID)
+		if !isValidRequestID(requestID) {
+			requestID = goctx.NewRequestID()
+		}
+		ctx = goctx.WithRequestID(ctx, requestID)
+	}
+	r = r.WithContext(ctx)
+
+	mutableFields := goctx.NewMutableFields()
+	for _, seed := range config.seeders {
+		if fields := seed(r); len(fields) > 0 {
+			mutableFields.AddField(fields)
+		}
+	}
+
+	ctx = goctx.AddLoggerToContex(ctx, log)
+	return goctx.WithMutableFields(ctx, mutableFields)
+}
@@ -0,0 +1,137 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+	"github.com/junkd0g/go-microservice-commons/logger"
+	"github.com/junkd0g/go-microservice-commons/middleware"
+)
+
+// newObservedLogger returns a logger whose entries are recorded by the returned observer.
+func newObservedLogger(t *testing.T) (*logger.Logger, *observer.ObservedLogs) {
+	t.Helper()
+
+	log, err := logger.NewLogger()
+	assert.NoError(t, err)
+
+	core, recorded := observer.New(zapcore.InfoLevel)
+	log.SetCore(core)
+	return log, recorded
+}
+
+func Test_RequestContext(t *testing.T) {
+	t.Run("should isolate the fields of concurrent requests", func(t *testing.T) {
+		log, recorded := newObservedLogger(t)
+
+		var inFlight sync.WaitGroup
+		inFlight.Add(2)
+
+		handler := middleware.RequestContext(log)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fields, ok := goctx.MutableFieldsFromContext(r.Context())
+			assert.True(t, ok)
+			fields.AddField(map[string]interface{}{r.URL.Query().Get("user"): "set"})
+
+			// Wait until both requests have added their fields
+			inFlight.Done()
+			inFlight.Wait()
+
+			handlerLog, err := goctx.GetLoggerFromContext(r.Context())
+			assert.NoError(t, err)
+			handlerLog.Info(r.Context(), "handling")
+		}))
+
+		var done sync.WaitGroup
+		for _, user := range []string{"alice", "bob"} {
+			done.Add(1)
+			go func(user string) {
+				defer done.Done()
+				handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/?user="+user, nil))
+			}(user)
+		}
+		done.Wait()
+
+		entries := recorded.All()
+		assert.Len(t, entries, 2)
+		requestIDs := map[interface{}]bool{}
+		for _, entry := range entries {
+			fields := entry.ContextMap()
+			_, alice := fields["alice"]
+			_, bob := fields["bob"]
+			assert.True(t, alice != bob, "fields leaked between requests: %v", fields)
+			assert.NotEmpty(t, fields["request_id"])
+			requestIDs[fields["request_id"]] = true
+		}
+		assert.Len(t, requestIDs, 2)
+	})
+}
+
+func Test_RequestLogger(t *testing.T) {
+	t.Run("should bind a primed logger and log start and finish", func(t *testing.T) {
+		log, recorded := newObservedLogger(t)
+
+		handler := middleware.RequestLogger(log)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			handlerLog, err := goctx.GetLoggerFromContext(r.Context())
+			assert.NoError(t, err)
+
+			handlerLog.Info(r.Context(), "handling")
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+		req := httptest.NewRequest(http.MethodPost, "/users", nil)
+		req.Header.Set(middleware.HeaderRequestID, "some-request-id")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		entries := recorded.All()
+		assert.Len(t, entries, 3)
+		assert.Equal(t, "request started", entries[0].Message)
+		assert.Equal(t, "handling", entries[1].Message)
+		assert.Equal(t, "request finished", entries[2].Message)
+
+		for _, entry := range entries {
+			fields := entry.ContextMap()
+			assert.Equal(t, "some-request-id", fields["request_id"])
+			assert.Equal(t, http.MethodPost, fields["method"])
+			assert.Equal(t, "/users", fields["path"])
+		}
+		assert.Equal(t, int64(http.StatusCreated), entries[2].ContextMap()["status"])
+	})
+
+	t.Run("should log server errors at error level", func(t *testing.T) {
+		log, recorded := newObservedLogger(t)
+
+		handler := middleware.RequestLogger(log)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+		entries := recorded.All()
+		assert.Len(t, entries, 2)
+		assert.Equal(t, zapcore.ErrorLevel, entries[1].Level)
+	})
+
+	t.Run("should seed only the configured fields", func(t *testing.T) {
+		log, recorded := newObservedLogger(t)
+
+		handler := middleware.RequestLogger(log, middleware.WithFieldSeeders(
+			middleware.SeedPath,
+			func(r *http.Request) map[string]interface{} {
+				return map[string]interface{}{"tenant": r.Header.Get("X-Tenant-ID")}
+			},
+		))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Tenant-ID", "tenant-123")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		fields := recorded.All()[0].ContextMap()
+		assert.Equal(t, map[string]interface{}{"path": "/", "tenant": "tenant-123"}, fields)
+	})
+}
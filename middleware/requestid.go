@@ -0,0 +1,94 @@
+/*
+Package middleware provides HTTP middleware for microservices that wires
+the service context (request ID, logger and logger fields) into inbound
+requests and propagates it on outbound calls.
+*/
+package middleware
+
+import (
+	"net/http"
+
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+)
+
+const (
+	// HeaderRequestID is the header used to carry the request ID between services.
+	HeaderRequestID = "X-Request-ID"
+
+	// MaxRequestIDLength is the length above which inbound request IDs are replaced.
+	MaxRequestIDLength = 128
+)
+
+// RequestID reads the request ID from the inbound X-Request-ID header, generating
+// a new one when absent, stores it in the request context and echoes it on the response.
+// Inbound IDs longer than MaxRequestIDLength or with characters other than
+// letters, digits, '.', '_' and '-' are replaced by a generated one, so clients
+// can't inject arbitrary values into logs and response headers.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(HeaderRequestID)
+		if !isValidRequestID(requestID) {
+			requestID = goctx.NewRequestID()
+		}
+
+		ctx := goctx.WithRequestID(r.Context(), requestID)
+		w.Header().Set(HeaderRequestID, requestID)
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// isValidRequestID reports whether the inbound request ID is non-empty, at most
+// MaxRequestIDLength long and made of [A-Za-z0-9._-] only.
+func isValidRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > MaxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(requestID); i++ {
+		c := requestID[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		case c == '.', c == '_', c == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// RequestIDTransport is an http.RoundTripper that sets the X-Request-ID header
+// on outbound requests from the request ID stored in the request context.
+type RequestIDTransport struct {
+	base http.RoundTripper
+}
+
+// NewRequestIDTransport wraps the given RoundTripper. If base is nil,
+// http.DefaultTransport is used.
+func NewRequestIDTransport(base http.RoundTripper) *RequestIDTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &RequestIDTransport{
+		base: base,
+	}
+}
+
+// RoundTrip sets the X-Request-ID header and delegates to the wrapped RoundTripper.
+// A new request ID is generated if the context has none. A header already set
+// by the caller is left untouched.
+func (t *RequestIDTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if r.Header.Get(HeaderRequestID) != "" {
+		return t.base.RoundTrip(r)
+	}
+
+	requestID, ok := goctx.RequestIDFromContext(r.Context())
+	if !ok {
+		requestID = goctx.NewRequestID()
+	}
+
+	// RoundTrippers must not modify the original request.
+	outbound := r.Clone(r.Context())
+	outbound.Header.Set(HeaderRequestID, requestID)
+
+	return t.base.RoundTrip(outbound)
+}
@@ -0,0 +1,118 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+	"github.com/junkd0g/go-microservice-commons/middleware"
+)
+
+func Test_RequestID(t *testing.T) {
+	t.Run("should reuse the inbound request id", func(t *testing.T) {
+		var got string
+		handler := middleware.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got, _ = goctx.RequestIDFromContext(r.Context())
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(middleware.HeaderRequestID, "some-request-id")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, "some-request-id", got)
+		assert.Equal(t, "some-request-id", rec.Header().Get(middleware.HeaderRequestID))
+	})
+
+	t.Run("should generate a request id when missing", func(t *testing.T) {
+		var got string
+		handler := middleware.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got, _ = goctx.RequestIDFromContext(r.Context())
+		}))
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.NotEmpty(t, got)
+		assert.Equal(t, got, rec.Header().Get(middleware.HeaderRequestID))
+	})
+
+	t.Run("should replace an unsafe inbound request id", func(t *testing.T) {
+		for _, inbound := range []string{
+			"some id with spaces",
+			"id\x1b[31mred",
+			"id;drop",
+			strings.Repeat("a", middleware.MaxRequestIDLength+1),
+		} {
+			var got string
+			handler := middleware.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got, _ = goctx.RequestIDFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(middleware.HeaderRequestID, inbound)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.NotEmpty(t, got)
+			assert.NotEqual(t, inbound, got)
+			assert.Equal(t, got, rec.Header().Get(middleware.HeaderRequestID))
+		}
+	})
+}
+
+func Test_RequestIDTransport(t *testing.T) {
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get(middleware.HeaderRequestID)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: middleware.NewRequestIDTransport(nil)}
+
+	t.Run("should propagate the request id from the context", func(t *testing.T) {
+		ctx := goctx.WithRequestID(context.Background(), "some-request-id")
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		assert.NoError(t, err)
+
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		resp.Body.Close()
+
+		assert.Equal(t, "some-request-id", received)
+		assert.Empty(t, req.Header.Get(middleware.HeaderRequestID))
+	})
+
+	t.Run("should generate a request id when the context has none", func(t *testing.T) {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, nil)
+		assert.NoError(t, err)
+
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		resp.Body.Close()
+
+		assert.NotEmpty(t, received)
+	})
+
+	t.Run("should flow end to end through the inbound middleware", func(t *testing.T) {
+		handler := middleware.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, server.URL, nil)
+			assert.NoError(t, err)
+
+			resp, err := client.Do(req)
+			assert.NoError(t, err)
+			resp.Body.Close()
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(middleware.HeaderRequestID, "inbound-request-id")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		assert.Equal(t, "inbound-request-id", received)
+	})
+}
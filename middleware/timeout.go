@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+)
+
+// TimeoutOption configures the Timeout middleware.
+type TimeoutOption func(*timeoutConfig)
+
+// timeoutConfig holds the settings collected from TimeoutOptions.
+type timeoutConfig struct {
+	message       string
+	slowThreshold time.Duration
+}
+
+// WithTimeoutMessage sets the body of the 503 response sent on timeout.
+func WithTimeoutMessage(message string) TimeoutOption {
+	return func(c *timeoutConfig) {
+		c.message = message
+	}
+}
+
+// WithSlowThreshold logs a warning for requests that complete within the
+// timeout but take longer than threshold.
+func WithSlowThreshold(threshold time.Duration) TimeoutOption {
+	return func(c *timeoutConfig) {
+		c.slowThreshold = threshold
+	}
+}
+
+// Timeout caps handler execution time at d using http.TimeoutHandler semantics:
+// the request context gets a deadline and, if the handler has not finished in
+// time, the client receives 503 Service Unavailable. Timed out and slow
+// requests are logged as warnings with the elapsed time and path through the
+// context logger, if present. Handlers should watch the request context to
+// stop work early once it is cancelled.
+func Timeout(d time.Duration, opts ...TimeoutOption) func(http.Handler) http.Handler {
+	config := &timeoutConfig{
+		message: http.StatusText(http.StatusServiceUnavailable),
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	return func(next http.Handler) http.Handler {
+		timeoutHandler := http.TimeoutHandler(next, d, config.message)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			sw := newStatusWriter(w)
+			timeoutHandler.ServeHTTP(sw, r)
+
+			elapsed := time.Since(start)
+			timedOut := sw.status == http.StatusServiceUnavailable && elapsed >= d
+			slow := config.slowThreshold > 0 && elapsed >= config.slowThreshold
+
+			if !timedOut && !slow {
+				return
+			}
+
+			log, err := goctx.GetLoggerFromContext(r.Context())
+			if err != nil {
+				return
+			}
+
+			msg := "slow request"
+			if timedOut {
+				msg = "request timed out"
+			}
+			goctx.Warn(r.Context(), log, msg, map[string]interface{}{
+				"path":    r.URL.Path,
+				"elapsed": elapsed,
+				"timeout": d,
+			})
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+
+	goctx "github.com/junkd0g/go-microservice-commons/context"
+	"github.com/junkd0g/go-microservice-commons/middleware"
+)
+
+func Test_Timeout(t *testing.T) {
+	t.Run("should let fast handlers through", func(t *testing.T) {
+		log, recorded := newObservedLogger(t)
+
+		handler := middleware.Timeout(time.Second)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/fast", nil)
+		req = req.WithContext(goctx.AddLoggerToContex(req.Context(), log))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Zero(t, recorded.Len())
+	})
+
+	t.Run("should return 503 and log a warning for slow handlers", func(t *testing.T) {
+		log, recorded := newObservedLogger(t)
+
+		handler := middleware.Timeout(20 * time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+				w.WriteHeader(http.StatusOK)
+			}
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/slow", nil)
+		req = req.WithContext(goctx.AddLoggerToContex(req.Context(), log))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+		entries := recorded.All()
+		assert.Len(t, entries, 1)
+		assert.Equal(t, zapcore.WarnLevel, entries[0].Level)
+		assert.Equal(t, "request timed out", entries[0].Message)
+		assert.Equal(t, "/slow", entries[0].ContextMap()["path"])
+	})
+
+	t.Run("should warn about requests slower than the threshold", func(t *testing.T) {
+		log, recorded := newObservedLogger(t)
+
+		handler := middleware.Timeout(time.Second, middleware.WithSlowThreshold(10*time.Millisecond))(
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(20 * time.Millisecond)
+			}),
+		)
+
+		req := httptest.NewRequest(http.MethodGet, "/sluggish", nil)
+		req = req.WithContext(goctx.AddLoggerToContex(req.Context(), log))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		entries := recorded.All()
+		assert.Len(t, entries, 1)
+		assert.Equal(t, "slow request", entries[0].Message)
+	})
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import "net/http"
+
+// statusWriter wraps an http.ResponseWriter to record the status code and
+// number of bytes written by the handler.
+type statusWriter struct {
+	http.ResponseWriter
+	status      int
+	written     int
+	wroteHeader bool
+}
+
+// newStatusWriter wraps w, defaulting the status to 200 OK as net/http does.
+func newStatusWriter(w http.ResponseWriter) *statusWriter {
+	return &statusWriter{
+		ResponseWriter: w,
+		status:         http.StatusOK,
+	}
+}
+
+// WriteHeader records the status code and forwards it.
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write records the number of bytes written and forwards them.
+func (w *statusWriter) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+	w.written += n
+	w.wroteHeader = true
+	return n, err
+}
+
+// Unwrap returns the wrapped ResponseWriter for use with http.ResponseController.
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}